@@ -0,0 +1,86 @@
+package semver
+
+import (
+	"time"
+)
+
+// MetadataTimestamp reads the time a version was published or built from a
+// timestamp in its build metadata. It is for version sets where the build
+// metadata is the only place the dates are kept, such as
+// 1.2.3+ts.20240115093000 or 1.2.3+20240115, and allows policies like
+// no versions older than 180 days.
+//
+// The zero value reads the ts key in the form written by BuildInfo.Metadata.
+type MetadataTimestamp struct {
+	// Key is the metadata key whose value is the timestamp, see
+	// Version.MetadataValue. When it is empty the first metadata
+	// identifier that parses with the layout is used. It defaults to ts
+	// when both Key and Layout are empty.
+	Key string
+
+	// Layout is the time.Parse layout of the timestamp. Timestamps without a
+	// time zone are in UTC. It defaults to 20060102150405.
+	Layout string
+}
+
+// Time returns the timestamp in the metadata of the version and whether
+// there is one that parses with the layout.
+func (m MetadataTimestamp) Time(v *Version) (time.Time, bool) {
+	if v == nil {
+		return time.Time{}, false
+	}
+
+	key, layout := m.Key, m.Layout
+	if layout == "" {
+		layout = buildTimeLayout
+		if key == "" {
+			key = "ts"
+		}
+	}
+
+	if key != "" {
+		val, ok := v.MetadataValue(key)
+		if !ok {
+			return time.Time{}, false
+		}
+		t, err := time.Parse(layout, val)
+		return t, err == nil
+	}
+
+	for _, id := range v.MetadataIdentifiers() {
+		if t, err := time.Parse(layout, id); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Age returns how long before now the version's timestamp is and whether it
+// has one. The age is negative for timestamps after now.
+func (m MetadataTimestamp) Age(v *Version, now time.Time) (time.Duration, bool) {
+	t, ok := m.Time(v)
+	if !ok {
+		return 0, false
+	}
+	return now.Sub(t), true
+}
+
+// Stale reports whether the version's timestamp is more than maxAge before
+// now. Versions without a timestamp are stale as their age is not known,
+// while MaxAgeRule only breaks for them when RequireTimestamp is set.
+func (m MetadataTimestamp) Stale(v *Version, maxAge time.Duration, now time.Time) bool {
+	age, ok := m.Age(v, now)
+	return !ok || age > maxAge
+}
+
+// FilterFresh returns the versions in the collection that are not stale, in
+// the same order. Versions without a timestamp are left out.
+func (m MetadataTimestamp) FilterFresh(c Collection, maxAge time.Duration, now time.Time) Collection {
+	var out Collection
+	for _, v := range c {
+		if !m.Stale(v, maxAge, now) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
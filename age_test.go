@@ -0,0 +1,42 @@
+package semver
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMetadataTimestamp(t *testing.T) {
+	now := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		m   MetadataTimestamp
+		v   string
+		age time.Duration
+		ok  bool
+	}{
+		{MetadataTimestamp{}, "1.2.3+build.4.ts.20240630000000", 24 * time.Hour, true},
+		{MetadataTimestamp{}, "1.2.3+build.4", 0, false},
+		{MetadataTimestamp{}, "1.2.3+ts.2024-06-30", 0, false},
+		{MetadataTimestamp{Layout: "20060102"}, "1.2.3+linux.20240621", 10 * 24 * time.Hour, true},
+		{MetadataTimestamp{Key: "date", Layout: "2006-01-02"}, "1.2.3+date.2024-06-30", 24 * time.Hour, true},
+		{MetadataTimestamp{Key: "date", Layout: "2006-01-02"}, "1.2.3+2024-06-30", 0, false},
+		{MetadataTimestamp{}, "1.2.3", 0, false},
+	}
+
+	for _, tc := range tests {
+		age, ok := tc.m.Age(MustParse(tc.v), now)
+		if ok != tc.ok || age != tc.age {
+			t.Errorf("Expected the age of %s with %+v to be %s, %t but got %s, %t", tc.v, tc.m, tc.age, tc.ok, age, ok)
+		}
+	}
+
+	c := Collection{
+		MustParse("1.0.0+ts.20231201000000"),
+		MustParse("1.1.0+ts.20240601000000"),
+		MustParse("1.2.0"),
+		MustParse("1.3.0+ts.20240630000000"),
+	}
+	fresh := MetadataTimestamp{}.FilterFresh(c, 180*24*time.Hour, now)
+	if len(fresh) != 2 || fresh[0] != c[1] || fresh[1] != c[3] {
+		t.Errorf("Expected 1.1.0 and 1.3.0 to be fresh but got %v", fresh)
+	}
+}
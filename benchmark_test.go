@@ -44,6 +44,12 @@ func BenchmarkNewConstraintRange(b *testing.B) {
 	benchNewConstraint(">=2.1.x, <3.1.0", b)
 }
 
+func BenchmarkNewConstraintHyphenRange(b *testing.B) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	benchNewConstraint("1.2.3 - 2.4.5", b)
+}
+
 func BenchmarkNewConstraintUnion(b *testing.B) {
 	b.ReportAllocs()
 	b.ResetTimer()
@@ -110,6 +116,17 @@ func benchValidateVersion(c, v string, b *testing.B) {
 	}
 }
 
+func benchValidateFirstVersion(c, v string, b *testing.B) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	version, _ := NewVersion(v)
+	constraint, _ := NewConstraint(c)
+
+	for i := 0; i < b.N; i++ {
+		constraint.ValidateFirst(version)
+	}
+}
+
 /* Validate benchmarks, including fails */
 
 func BenchmarkValidateVersionUnary(b *testing.B) {
@@ -184,6 +201,18 @@ func BenchmarkValidateVersionUnionFail(b *testing.B) {
 	benchValidateVersion("~2.0.0 || =3.1.0", "3.1.1", b)
 }
 
+func BenchmarkValidateFirstVersionRangeFail(b *testing.B) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	benchValidateFirstVersion(">=2.1.x, <3.1.0", "1.4.5", b)
+}
+
+func BenchmarkValidateFirstVersionUnionFail(b *testing.B) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	benchValidateFirstVersion("~2.0.0 || =3.1.0", "3.1.1", b)
+}
+
 /* Version creation benchmarks */
 
 func benchNewVersion(v string, b *testing.B) {
@@ -245,3 +274,52 @@ func BenchmarkStrictNewVersionMetaDash(b *testing.B) {
 	b.ResetTimer()
 	benchStrictNewVersion("1.0.0-alpha.1+meta.data", b)
 }
+
+/* Version comparison benchmarks */
+
+func benchCompareVersion(v1, v2 string, b *testing.B) {
+	sv1, err := NewVersion(v1)
+	if err != nil {
+		b.Fatal(err)
+	}
+	sv2, err := NewVersion(v2)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = sv1.Compare(sv2)
+	}
+}
+
+func BenchmarkCompareVersionPre(b *testing.B) {
+	benchCompareVersion("1.0.0-alpha.1", "1.0.0-alpha.2", b)
+}
+
+func BenchmarkCompareVersionPreAlphanum(b *testing.B) {
+	benchCompareVersion("1.0.0-alpha.beta.rc", "1.0.0-alpha.beta.rd", b)
+}
+
+/* Compiled constraint benchmarks */
+
+func benchCompiledCheckVersion(c, v string, b *testing.B) {
+	cs, _ := NewConstraint(c)
+	cc := cs.Compile()
+	ver, _ := NewVersion(v)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cc.Check(ver)
+	}
+}
+
+func BenchmarkCompiledCheckVersionRange(b *testing.B) {
+	benchCompiledCheckVersion(">=2.1.x, <3.1.0", "2.4.5", b)
+}
+
+func BenchmarkCompiledCheckVersionUnion(b *testing.B) {
+	benchCompiledCheckVersion("~2.0.0 || =3.1.0", "3.1.0", b)
+}
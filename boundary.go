@@ -0,0 +1,153 @@
+package semver
+
+import (
+	"fmt"
+	"math"
+)
+
+// NextPatchOf returns the next patch version after the release of v, such as
+// 1.2.4 for 1.2.3 or 1.2.3-beta.1. The prerelease and metadata of v are
+// dropped. It is useful for turning an inclusive upper bound into an
+// exclusive one, as <=1.2.3 allows the same releases as <1.2.4. Unlike
+// IncPatch the patch version is always increased. It is nil when the patch
+// version can not be increased.
+func NextPatchOf(v *Version) *Version {
+	if v.patch == math.MaxUint64 {
+		return nil
+	}
+	return New(v.major, v.minor, v.patch+1, "", "")
+}
+
+// NextMinorOf returns the first version of the next minor release line after
+// v, such as 1.3.0 for 1.2.3, which is the exclusive upper bound of ~1.2.3.
+// It is nil when the minor version can not be increased.
+func NextMinorOf(v *Version) *Version {
+	if v.minor == math.MaxUint64 {
+		return nil
+	}
+	return New(v.major, v.minor+1, 0, "", "")
+}
+
+// NextMajorOf returns the first version of the next major release line after
+// v, such as 2.0.0 for 1.2.3, which is the exclusive upper bound of ^1.2.3.
+// It is nil when the major version can not be increased.
+func NextMajorOf(v *Version) *Version {
+	if v.major == math.MaxUint64 {
+		return nil
+	}
+	return New(v.major+1, 0, 0, "", "")
+}
+
+// SmallestPrereleaseAbove returns the lowest version above every release
+// lower than the release of v, which is that release with the prerelease 0,
+// such as 1.2.3-0 for 1.2.3 or 1.2.3-beta.1. It is the bound to use when the
+// prereleases of a release need to be included or excluded, as <1.2.3-0
+// excludes the prereleases of 1.2.3 while <1.2.3 allows them with some
+// prerelease policies. The metadata of v is dropped.
+func SmallestPrereleaseAbove(v *Version) *Version {
+	return New(v.major, v.minor, v.patch, "0", "")
+}
+
+// ToInclusiveBounds returns the constraints with each > and < comparator
+// rewritten as the >= or <= comparator allowing the same versions, for
+// systems that only accept closed ranges such as SQL BETWEEN. >1.2.3
+// becomes >=1.2.4, >1.2 becomes >=1.3.0, >1.2.3-beta becomes
+// >=1.2.3-beta.0, and <1.2.3 becomes <=1.2.2. Other constraints, such as ^
+// and ~, are kept.
+//
+// A < comparator with a prerelease or a patch version of 0, such as <2.0.0,
+// has no highest version below it and an error wrapping
+// ErrUnsupportedRange is returned. The prerelease policy and deny list are
+// kept. With PrereleaseSameTuple a prerelease of the version at a rewritten
+// bound may be treated differently.
+func (cs Constraints) ToInclusiveBounds() (*Constraints, error) {
+	return rewriteBounds(&cs, func(c *constraint) (string, error) {
+		if c.dirty && !c.minorDirty && !c.patchDirty {
+			return c.string(), nil
+		}
+
+		switch c.origfunc {
+		case ">":
+			v := boundAbove(c)
+			if v == nil {
+				return "", fmt.Errorf("can not make %s an inclusive bound", c.string())
+			}
+			return ">=" + v.String(), nil
+		case "<":
+			if c.con.pre != "" || c.con.patch == 0 {
+				return "", fmt.Errorf("%w: %s has no highest version", ErrUnsupportedRange, c.string())
+			}
+			return "<=" + New(c.con.major, c.con.minor, c.con.patch-1, "", "").String(), nil
+		}
+		return c.string(), nil
+	})
+}
+
+// ToExclusiveBounds returns the constraints with each <= comparator
+// rewritten as the < comparator allowing the same versions, for systems that
+// only accept ranges with an exclusive upper bound, such as the half open
+// ranges ^ and ~ are written as. <=1.2.3 becomes <1.2.4, <=1.2 becomes
+// <1.3.0, and <=1.2.3-beta becomes <1.2.3-beta.0. Lower bounds and other
+// constraints are kept.
+//
+// With PrereleaseSameTuple the new bound is the lowest prerelease of the
+// version, such as <1.2.4-0 for <=1.2.3, so the prereleases of that version
+// are still not allowed. The prerelease policy and deny list are kept.
+func (cs Constraints) ToExclusiveBounds() (*Constraints, error) {
+	return rewriteBounds(&cs, func(c *constraint) (string, error) {
+		if c.dirty && !c.minorDirty && !c.patchDirty {
+			return c.string(), nil
+		}
+
+		switch c.origfunc {
+		case "<=", "=<":
+			v := boundAbove(c)
+			if v == nil {
+				return "", fmt.Errorf("can not make %s an exclusive bound", c.string())
+			}
+			if cs.PrereleasePolicy == PrereleaseSameTuple && v.pre == "" {
+				v = SmallestPrereleaseAbove(v)
+			}
+			return "<" + v.String(), nil
+		}
+		return c.string(), nil
+	})
+}
+
+// boundAbove returns the lowest version above those allowed by <=c, which
+// is also the lowest version allowed by >c. A wildcard constraint covers
+// its whole release line, so it is 1.3.0 for 1.2 and 2.0.0 for 1. It is nil
+// when the version can not be increased.
+func boundAbove(c *constraint) *Version {
+	switch {
+	case c.minorDirty:
+		return NextMajorOf(c.con)
+	case c.patchDirty:
+		return NextMinorOf(c.con)
+	case c.con.pre != "":
+		return New(c.con.major, c.con.minor, c.con.patch, c.con.pre+".0", "")
+	}
+	return NextPatchOf(c.con)
+}
+
+// rewriteBounds rewrites each constraint with rewrite and parses the result
+// keeping the settings of c.
+func rewriteBounds(c *Constraints, rewrite func(c *constraint) (string, error)) (*Constraints, error) {
+	if c.IsEmpty() {
+		return c, nil
+	}
+
+	groups := make([][]string, len(c.constraints))
+	for k, o := range c.constraints {
+		g := make([]string, len(o))
+		for i, cc := range o {
+			s, err := rewrite(cc)
+			if err != nil {
+				return nil, err
+			}
+			g[i] = s
+		}
+		groups[k] = g
+	}
+	return rebuildConstraints(c, groups)
+}
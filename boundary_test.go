@@ -0,0 +1,107 @@
+package semver
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestBoundaryHelpers(t *testing.T) {
+	tests := []struct {
+		version             string
+		patch, minor, major string
+		smallestPrerelease  string
+	}{
+		{"1.2.3", "1.2.4", "1.3.0", "2.0.0", "1.2.3-0"},
+		{"1.2.3-beta.1+b1", "1.2.4", "1.3.0", "2.0.0", "1.2.3-0"},
+		{"v0.0.0", "0.0.1", "0.1.0", "1.0.0", "0.0.0-0"},
+	}
+
+	for _, tc := range tests {
+		v := MustParse(tc.version)
+		if got := NextPatchOf(v).String(); got != tc.patch {
+			t.Errorf("Expected the next patch of %s to be %s but got %s", tc.version, tc.patch, got)
+		}
+		if got := NextMinorOf(v).String(); got != tc.minor {
+			t.Errorf("Expected the next minor of %s to be %s but got %s", tc.version, tc.minor, got)
+		}
+		if got := NextMajorOf(v).String(); got != tc.major {
+			t.Errorf("Expected the next major of %s to be %s but got %s", tc.version, tc.major, got)
+		}
+		if got := SmallestPrereleaseAbove(v).String(); got != tc.smallestPrerelease {
+			t.Errorf("Expected the smallest prerelease above %s to be %s but got %s", tc.version, tc.smallestPrerelease, got)
+		}
+	}
+
+	v := New(math.MaxUint64, math.MaxUint64, math.MaxUint64, "", "")
+	if NextPatchOf(v) != nil || NextMinorOf(v) != nil || NextMajorOf(v) != nil {
+		t.Error("Expected no next version when it would overflow")
+	}
+
+	// <=1.2.3 and <1.2.4 allow the same releases.
+	a, _ := NewConstraint("<=1.2.3")
+	b, _ := NewConstraint("<" + NextPatchOf(MustParse("1.2.3")).String())
+	for _, s := range []string{"1.2.2", "1.2.3", "1.2.4", "1.2.4-beta"} {
+		if a.Check(MustParse(s)) != b.Check(MustParse(s)) {
+			t.Errorf("Expected %s to check the same with %s and %s", s, a, b)
+		}
+	}
+}
+
+func TestBoundsConversion(t *testing.T) {
+	tests := []struct {
+		constraint string
+		policy     PrereleasePolicy
+		inclusive  string
+		exclusive  string
+	}{
+		{">1.2.3 <1.5.2", PrereleaseDefault, ">=1.2.4 <=1.5.1", ">1.2.3 <1.5.2"},
+		{">=1.2.3 <=1.5.2", PrereleaseDefault, ">=1.2.3 <=1.5.2", ">=1.2.3 <1.5.3"},
+		{">1.2 <=1", PrereleaseDefault, ">=1.3.0 <=1", ">1.2 <2.0.0"},
+		{">1.2.3-beta || =<1.2", PrereleaseDefault, "=<1.2 || >=1.2.3-beta.0", ">1.2.3-beta || <1.3.0"},
+		{"<=1.2.3-beta.1", PrereleaseDefault, "<=1.2.3-beta.1", "<1.2.3-beta.1.0"},
+		{"^1.2 <=1.4.0", PrereleaseSameTuple, "^1.2 <=1.4.0", "^1.2 <1.4.1-0"},
+		{"1.2 - 1.4", PrereleaseDefault, ">=1.2 <=1.4", ">=1.2 <1.5.0"},
+		{"<2.0.0", PrereleaseDefault, "", "<2.0.0"},
+		{"<1.2.3-beta", PrereleaseDefault, "", "<1.2.3-beta"},
+		{"*", PrereleaseDefault, "*", "*"},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		c.PrereleasePolicy = tc.policy
+
+		conversions := []struct {
+			name     string
+			convert  func() (*Constraints, error)
+			expected string
+		}{
+			{"inclusive", c.ToInclusiveBounds, tc.inclusive},
+			{"exclusive", c.ToExclusiveBounds, tc.exclusive},
+		}
+		for _, conv := range conversions {
+			res, err := conv.convert()
+			if conv.expected == "" {
+				if !errors.Is(err, ErrUnsupportedRange) {
+					t.Errorf("Expected %s bounds of %q to be unsupported but got %v", conv.name, tc.constraint, err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if res.String() != conv.expected || res.PrereleasePolicy != tc.policy {
+				t.Errorf("Expected %s bounds of %q to be %q but got %q", conv.name, tc.constraint, conv.expected, res)
+			}
+			for _, s := range []string{"1.2.2", "1.2.3", "1.2.3-beta.1", "1.2.4", "1.3.0", "1.4.0", "1.4.1-alpha", "1.5.1", "1.5.2", "1.9.0", "2.0.0"} {
+				v := MustParse(s)
+				if res.Check(v) != c.Check(v) {
+					t.Errorf("Expected %s bounds %q of %q to check %s the same", conv.name, res, tc.constraint, s)
+				}
+			}
+		}
+	}
+}
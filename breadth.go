@@ -0,0 +1,136 @@
+package semver
+
+// BreadthKind classifies how many versions constraints allow. The kinds are
+// ordered from the narrowest to the broadest.
+type BreadthKind uint8
+
+const (
+	// BreadthEmpty is for constraints that do not allow any version.
+	BreadthEmpty BreadthKind = iota
+
+	// BreadthExact is for constraints allowing a single version, such as
+	// =1.2.3.
+	BreadthExact
+
+	// BreadthPatch is for constraints whose release versions are all in one
+	// minor release line, such as ~1.2.3 or >=1.2.3 <=1.2.7.
+	BreadthPatch
+
+	// BreadthMinor is for constraints whose release versions are all in one
+	// major release line, such as ^1.2.3 or >=1.2.0 <1.5.0.
+	BreadthMinor
+
+	// BreadthMajor is for constraints with an upper bound spanning more than
+	// one major release line, such as >=1.2.0 <3.0.0.
+	BreadthMajor
+
+	// BreadthUnbounded is for constraints without an upper bound, such as
+	// >=1.2.0 or *.
+	BreadthUnbounded
+)
+
+func (k BreadthKind) String() string {
+	switch k {
+	case BreadthEmpty:
+		return "empty"
+	case BreadthExact:
+		return "exact"
+	case BreadthPatch:
+		return "patch"
+	case BreadthMinor:
+		return "minor"
+	case BreadthMajor:
+		return "major"
+	case BreadthUnbounded:
+		return "unbounded"
+	}
+	return "unknown"
+}
+
+// Breadth estimates how many versions constraints allow. It is returned by
+// EstimateBreadth.
+type Breadth struct {
+	Kind BreadthKind
+
+	// Width is the number of release lines of the kind the constraints span,
+	// such as 3 patch versions for >=1.2.3 <=1.2.5, 3 minor release lines
+	// for >=1.2.0 <1.5.0, or 2 major release lines for >=1.2.0 <3.0.0. It is
+	// 1 for exact constraints and 0 for empty ones. It is also 0 when the
+	// number is not finite, such as the patch versions of ~1.2.3, the minor
+	// release lines of ^1.2.3, or the versions of >=1.2.0.
+	Width uint64
+}
+
+// EstimateBreadth classifies the constraints by the release versions they
+// allow, from exact to unbounded, along with the number of versions or
+// release lines they span where it is finite. Linters can use it to warn
+// about ranges that are too broad, such as >=1.2.0, or too narrow, such as
+// =1.2.3, in the same way for every form of constraint.
+//
+// The breadth is an estimate. It goes from the lowest to the highest version
+// the constraints allow, including any gaps between groups and the versions
+// excluded by != constraints and the deny list. Prereleases are not counted.
+func (cs Constraints) EstimateBreadth() Breadth {
+	ivs := cs.intervalSet()
+	if len(ivs) == 0 {
+		return Breadth{Kind: BreadthEmpty}
+	}
+	if len(ivs) == 1 && ivs[0].point() {
+		return Breadth{Kind: BreadthExact, Width: 1}
+	}
+
+	last := ivs[len(ivs)-1]
+	if last.hi == nil {
+		return Breadth{Kind: BreadthUnbounded}
+	}
+	// The lowest release version allowed.
+	lo := ivs[0].lo
+	switch {
+	case lo == nil:
+		lo = New(0, 0, 0, "", "")
+	case lo.pre != "":
+		lo = New(lo.major, lo.minor, lo.patch, "", "")
+	case !ivs[0].loInc:
+		lo = New(lo.major, lo.minor, lo.patch+1, "", "")
+	}
+
+	// The highest release version allowed. A minor or patch version that is
+	// not finite, such as the patch versions below <1.3.0, is open.
+	major, minor, patch := last.hi.major, last.hi.minor, last.hi.patch
+	var openMinor, openPatch bool
+	if !last.hiInc || last.hi.pre != "" {
+		switch {
+		case patch > 0:
+			patch--
+		case minor > 0:
+			minor--
+			openPatch = true
+		case major > 0:
+			major--
+			openMinor, openPatch = true, true
+		default:
+			return Breadth{Kind: BreadthEmpty}
+		}
+	}
+
+	// Only prereleases are allowed when the lowest release version is above
+	// the highest one, such as for >1.2.3 <1.2.4.
+	if major < lo.major || (major == lo.major && !openMinor &&
+		(minor < lo.minor || (minor == lo.minor && !openPatch && patch < lo.patch))) {
+		return Breadth{Kind: BreadthEmpty}
+	}
+
+	switch {
+	case major == lo.major && !openMinor && minor == lo.minor:
+		if openPatch {
+			return Breadth{Kind: BreadthPatch}
+		}
+		return Breadth{Kind: BreadthPatch, Width: patch - lo.patch + 1}
+	case major == lo.major:
+		if openMinor {
+			return Breadth{Kind: BreadthMinor}
+		}
+		return Breadth{Kind: BreadthMinor, Width: minor - lo.minor + 1}
+	}
+	return Breadth{Kind: BreadthMajor, Width: major - lo.major + 1}
+}
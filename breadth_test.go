@@ -0,0 +1,46 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestEstimateBreadth(t *testing.T) {
+	tests := []struct {
+		constraint string
+		kind       BreadthKind
+		width      uint64
+	}{
+		{"=1.2.3", BreadthExact, 1},
+		{">=1.2.3 <=1.2.3", BreadthExact, 1},
+		{">=1.2.3 <=1.2.7", BreadthPatch, 5},
+		{">1.2.3 <1.2.6", BreadthPatch, 2},
+		{"~1.2.3", BreadthPatch, 0},
+		{"1.2.x", BreadthPatch, 0},
+		{"^0.2.3", BreadthPatch, 0},
+		{">=1.2.0 <1.5.0", BreadthMinor, 3},
+		{"^1.2.3", BreadthMinor, 0},
+		{"1.x", BreadthMinor, 0},
+		{"~1.2 || ~1.4", BreadthMinor, 3},
+		{">=1.2.0 <3.0.0", BreadthMajor, 2},
+		{"<=3.1.0", BreadthMajor, 4},
+		{"^1 || ^3", BreadthMajor, 3},
+		{">=1.2.0", BreadthUnbounded, 0},
+		{"*", BreadthUnbounded, 0},
+		{"<1.0.0 >=2.0.0", BreadthEmpty, 0},
+		{">1.2.3 <1.2.4", BreadthEmpty, 0},
+		{">=1.3.0-beta <1.3.0", BreadthEmpty, 0},
+		{">=1.3.0-beta <=1.3.1", BreadthPatch, 2},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		b := c.EstimateBreadth()
+		if b.Kind != tc.kind || b.Width != tc.width {
+			t.Errorf("Expected %q to have breadth %s %d but got %s %d", tc.constraint, tc.kind, tc.width, b.Kind, b.Width)
+		}
+	}
+}
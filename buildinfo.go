@@ -0,0 +1,115 @@
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// buildTimeLayout is the layout of timestamps in build metadata. It only
+// uses digits so it is a valid metadata identifier.
+const buildTimeLayout = "20060102150405"
+
+// BuildInfo holds details of a build from a CI system that can be recorded in
+// the build metadata of a version. Empty fields are left out.
+//
+// The metadata is a list of key and value identifiers in the form
+// branch.<branch>.build.<number>.sha.<commit>.ts.<timestamp>, such as
+// 1.2.3+branch.main.build.42.sha.4f2a9c1.ts.20240115093000.
+type BuildInfo struct {
+	// Branch is the name of the branch. Characters that are not allowed in
+	// metadata are replaced, see SanitizeMetadataIdentifier.
+	Branch string
+
+	// Number is the build number. Zero means there is no build number.
+	Number uint64
+
+	// Commit is the commit the build is from, such as a git SHA.
+	Commit string
+
+	// Time is when the build happened. It is recorded in UTC to the second.
+	Time time.Time
+}
+
+// Metadata returns the build info as spec valid build metadata that can be
+// passed to Version.SetMetadata. An empty string is returned when all the
+// fields are empty.
+func (b BuildInfo) Metadata() string {
+	var ids []string
+	if s := SanitizeMetadataIdentifier(b.Branch); s != "" {
+		ids = append(ids, "branch", s)
+	}
+	if b.Number != 0 {
+		ids = append(ids, "build", strconv.FormatUint(b.Number, 10))
+	}
+	if s := SanitizeMetadataIdentifier(strings.ToLower(b.Commit)); s != "" {
+		ids = append(ids, "sha", s)
+	}
+	if !b.Time.IsZero() {
+		ids = append(ids, "ts", b.Time.UTC().Format(buildTimeLayout))
+	}
+	return strings.Join(ids, ".")
+}
+
+// ParseBuildInfo reads build info from build metadata in the form written by
+// BuildInfo.Metadata. Identifiers that are not part of the build info are
+// skipped. An error is returned when a build number or timestamp is not
+// valid. Note, the branch is the sanitized form of the original name.
+func ParseBuildInfo(metadata string) (BuildInfo, error) {
+	var b BuildInfo
+	if metadata == "" {
+		return b, nil
+	}
+
+	ids := strings.Split(metadata, ".")
+	for i := 0; i+1 < len(ids); i++ {
+		val := ids[i+1]
+		switch ids[i] {
+		case "branch":
+			b.Branch = val
+		case "build":
+			n, err := strconv.ParseUint(val, 10, 64)
+			if err != nil {
+				return BuildInfo{}, fmt.Errorf("%w: invalid build number %q", ErrInvalidMetadata, val)
+			}
+			b.Number = n
+		case "sha":
+			b.Commit = val
+		case "ts":
+			t, err := time.Parse(buildTimeLayout, val)
+			if err != nil {
+				return BuildInfo{}, fmt.Errorf("%w: invalid build timestamp %q", ErrInvalidMetadata, val)
+			}
+			b.Time = t
+		default:
+			continue
+		}
+		i++
+	}
+
+	return b, nil
+}
+
+// SanitizeMetadataIdentifier turns a string, such as a branch name, into a
+// single valid build metadata identifier. Each run of characters that are not
+// ASCII letters or digits, including hyphens, is replaced by a single hyphen
+// and leading and trailing runs are removed. For example, feature/ABC_12
+// becomes feature-ABC-12. An empty string is returned when nothing is left.
+func SanitizeMetadataIdentifier(s string) string {
+	var sb strings.Builder
+	sep := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if strings.IndexByte(allowed, c) >= 0 && c != '-' {
+			if sep && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			sep = false
+			sb.WriteByte(c)
+			continue
+		}
+		sep = true
+	}
+	return sb.String()
+}
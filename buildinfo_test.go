@@ -0,0 +1,75 @@
+package semver
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBuildInfoMetadata(t *testing.T) {
+	ts := time.Date(2024, 1, 15, 10, 30, 0, 0, time.FixedZone("CET", 3600))
+	tests := []struct {
+		info     BuildInfo
+		expected string
+	}{
+		{BuildInfo{}, ""},
+		{BuildInfo{Number: 42}, "build.42"},
+		{
+			BuildInfo{Branch: "feature/ABC_12", Number: 42, Commit: "4F2A9C1", Time: ts},
+			"branch.feature-ABC-12.build.42.sha.4f2a9c1.ts.20240115093000",
+		},
+		{BuildInfo{Branch: "///", Commit: "abc"}, "sha.abc"},
+	}
+
+	for _, tc := range tests {
+		m := tc.info.Metadata()
+		if m != tc.expected {
+			t.Errorf("Expected metadata %q but got %q", tc.expected, m)
+		}
+		if _, err := MustParse("1.2.3").SetMetadata(m); m != "" && err != nil {
+			t.Errorf("Expected metadata %q to be valid but got %s", m, err)
+		}
+	}
+}
+
+func TestParseBuildInfo(t *testing.T) {
+	b, err := ParseBuildInfo("branch.feature-ABC-12.build.42.sha.4f2a9c1.ts.20240115093000")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	e := BuildInfo{
+		Branch: "feature-ABC-12",
+		Number: 42,
+		Commit: "4f2a9c1",
+		Time:   time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC),
+	}
+	if b != e {
+		t.Errorf("Expected %+v but got %+v", e, b)
+	}
+
+	b, err = ParseBuildInfo("linux.build.7.amd64")
+	if err != nil || b != (BuildInfo{Number: 7}) {
+		t.Errorf("Expected build number 7 but got %+v with error %v", b, err)
+	}
+
+	for _, m := range []string{"build.x", "ts.2024"} {
+		if _, err := ParseBuildInfo(m); !errors.Is(err, ErrInvalidMetadata) {
+			t.Errorf("Expected error for %q to wrap %q but got %v", m, ErrInvalidMetadata, err)
+		}
+	}
+}
+
+func TestSanitizeMetadataIdentifier(t *testing.T) {
+	tests := map[string]string{
+		"main":             "main",
+		"feature/ABC_12":   "feature-ABC-12",
+		"--release//1.x--": "release-1-x",
+		"dependabot/npm/é": "dependabot-npm",
+		"":                 "",
+	}
+	for in, e := range tests {
+		if a := SanitizeMetadataIdentifier(in); a != e {
+			t.Errorf("Expected %q to sanitize to %q but got %q", in, e, a)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package semver
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+// A CheckCache remembers the results of Constraints.Check for the most
+// recently used pairs of constraints and versions. It is for programs that
+// check the same versions against the same constraints over and over, such
+// as controllers evaluating a version list on every reconcile loop.
+//
+// Constraints are keyed by pointer, so the same constraints need to be the
+// same *Constraints to share results, such as those from a
+// ConstraintInterner. Constraints must not be modified once they have been
+// used with the cache. Versions are keyed by their value without the build
+// metadata, which Check ignores, so they do not need to be shared.
+//
+// A CheckCache is safe for concurrent use.
+type CheckCache struct {
+	size int
+
+	mu           sync.Mutex
+	entries      map[checkKey]*list.Element
+	order        *list.List
+	hits, misses uint64
+}
+
+// checkKey identifies a pair of constraints and a version in a CheckCache.
+type checkKey struct {
+	cs                  *Constraints
+	major, minor, patch uint64
+	pre                 string
+}
+
+// checkEntry is the value of an element in CheckCache.order.
+type checkEntry struct {
+	key checkKey
+	ok  bool
+}
+
+// NewCheckCache returns a CheckCache holding up to size results. When it is
+// full the least recently used result is dropped. A size less than 1 caches
+// nothing.
+func NewCheckCache(size int) *CheckCache {
+	return &CheckCache{
+		size:    size,
+		entries: make(map[checkKey]*list.Element),
+		order:   list.New(),
+	}
+}
+
+type bypassCheckCacheKey struct{}
+
+// BypassCheckCache returns a context that makes CheckCache.Check call
+// Constraints.Check without looking up or storing results, such as for a
+// reconcile triggered by a change to the constraints.
+func BypassCheckCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassCheckCacheKey{}, true)
+}
+
+// Check returns the result of cs.Check(v), from the cache when it has one.
+// False is returned for nil constraints or versions.
+func (c *CheckCache) Check(ctx context.Context, cs *Constraints, v *Version) bool {
+	if cs == nil || v == nil {
+		return false
+	}
+	if c.size < 1 || ctx.Value(bypassCheckCacheKey{}) != nil {
+		return cs.Check(v)
+	}
+
+	k := checkKey{cs: cs, major: v.major, minor: v.minor, patch: v.patch, pre: v.pre}
+	c.mu.Lock()
+	e, hit := c.entries[k]
+	var ok bool
+	if hit {
+		c.hits++
+		c.order.MoveToFront(e)
+		ok = e.Value.(*checkEntry).ok
+	} else {
+		c.misses++
+	}
+	c.mu.Unlock()
+
+	if m := loadMetrics(); m != nil {
+		m.CacheLookup(CacheCheck, hit)
+	}
+	if hit {
+		return ok
+	}
+
+	ok = cs.Check(v)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, found := c.entries[k]; found {
+		return ok
+	}
+	c.entries[k] = c.order.PushFront(&checkEntry{key: k, ok: ok})
+	if c.order.Len() > c.size {
+		last := c.order.Back()
+		c.order.Remove(last)
+		delete(c.entries, last.Value.(*checkEntry).key)
+	}
+	return ok
+}
+
+// Len returns the number of results in the cache.
+func (c *CheckCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Stats returns the number of lookups that found a result in the cache and
+// the number that did not. Lookups bypassed with BypassCheckCache are not
+// counted.
+func (c *CheckCache) Stats() (hits, misses uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
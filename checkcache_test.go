@@ -0,0 +1,60 @@
+package semver
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCheckCache(t *testing.T) {
+	ctx := context.Background()
+	cc := NewCheckCache(2)
+	c1 := mustConstraint(t, "^1.2")
+	c2 := mustConstraint(t, "~1.2")
+
+	if !cc.Check(ctx, c1, MustParse("1.3.0")) {
+		t.Error("Expected ^1.2 to allow 1.3.0")
+	}
+	if !cc.Check(ctx, c1, MustParse("1.3.0+b1")) {
+		t.Error("Expected ^1.2 to allow 1.3.0+b1 from the cache")
+	}
+	if hits, misses := cc.Stats(); hits != 1 || misses != 1 {
+		t.Errorf("Expected 1 hit and 1 miss but got %d and %d", hits, misses)
+	}
+
+	if cc.Check(ctx, c2, MustParse("1.3.0")) {
+		t.Error("Expected ~1.2 not to allow 1.3.0")
+	}
+	if cc.Check(ctx, c1, MustParse("1.3.0-beta.1")) {
+		t.Error("Expected ^1.2 not to allow 1.3.0-beta.1")
+	}
+	if cc.Len() != 2 {
+		t.Errorf("Expected the cache to be bounded to 2 results but got %d", cc.Len())
+	}
+
+	// The least recently used result, ^1.2 with 1.3.0, was dropped.
+	cc.Check(ctx, c1, MustParse("1.3.0"))
+	if hits, misses := cc.Stats(); hits != 1 || misses != 4 {
+		t.Errorf("Expected 1 hit and 4 misses but got %d and %d", hits, misses)
+	}
+
+	cc.Check(BypassCheckCache(ctx), c1, MustParse("1.3.0"))
+	if hits, misses := cc.Stats(); hits != 1 || misses != 4 {
+		t.Errorf("Expected bypassed checks not to be counted but got %d and %d", hits, misses)
+	}
+
+	if cc.Check(ctx, nil, MustParse("1.3.0")) || cc.Check(ctx, c1, nil) {
+		t.Error("Expected nil constraints or versions not to be allowed")
+	}
+
+	m := newTestMetrics()
+	SetMetrics(m)
+	t.Cleanup(func() { SetMetrics(nil) })
+	cc.Check(ctx, c1, MustParse("1.3.0"))
+	if l := m.caches[CacheCheck]; l[true] != 1 {
+		t.Errorf("Expected a cache hit to be reported but got %v", l)
+	}
+
+	if (NewCheckCache(0)).Check(ctx, c1, MustParse("1.3.0")) != true {
+		t.Error("Expected a cache without room to still check")
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+var behindCommand = &command{
+	usage: "--available <file> [-c constraint] [--prereleases] <current>",
+	short: "print the available versions newer than a version",
+	long: "Behind prints how many of the available versions are newer than the\n" +
+		"current version and lists them, such as to check in CI whether a\n" +
+		"dependency is out of date. With a constraint only the newer versions\n" +
+		"that satisfy it are counted. Prereleases are left out unless asked for.\n" +
+		"The exit status is 1 when there is a newer version.\n\n" +
+		"The available file has a version on each line. Blank lines and lines\n" +
+		"starting with # are ignored.",
+	run: runBehind,
+}
+
+func runBehind(fs *flag.FlagSet, args []string, stdout io.Writer) int {
+	available := fs.String("available", "", "the file of available versions")
+	constraint := fs.String("c", "", "only count the newer versions satisfying the constraint")
+	prereleases := fs.Bool("prereleases", false, "count prereleases")
+	if err := fs.Parse(args); err != nil {
+		return parseFailed(err)
+	}
+	if fs.NArg() != 1 || *available == "" {
+		fs.Usage()
+		return exitInvalid
+	}
+
+	current, err := semver.NewVersion(fs.Arg(0))
+	if err != nil {
+		return errorf(fs, "%s", err)
+	}
+	var c *semver.Constraints
+	if *constraint != "" {
+		if c, err = semver.NewConstraint(*constraint); err != nil {
+			return errorf(fs, "%s", err)
+		}
+	}
+	vs, err := readVersions(*available)
+	if err != nil {
+		return errorf(fs, "%s", err)
+	}
+
+	var newer semver.Collection
+	for _, v := range vs.Between(current, nil, semver.BetweenOptions{ExcludeLo: true}) {
+		if (v.Prerelease() != "" && !*prereleases) || (c != nil && !c.Check(v)) {
+			continue
+		}
+		// Versions that only differ by metadata are counted once.
+		if len(newer) > 0 && newer[len(newer)-1].Equal(v) {
+			continue
+		}
+		newer = append(newer, v)
+	}
+
+	if len(newer) == 0 {
+		fmt.Fprintf(stdout, "%s is up to date\n", current.Original())
+		return exitOK
+	}
+	noun := "versions"
+	if len(newer) == 1 {
+		noun = "version"
+	}
+	fmt.Fprintf(stdout, "%s is %d %s behind %s:\n", current.Original(), len(newer), noun, newer[len(newer)-1].Original())
+	for _, v := range newer {
+		fmt.Fprintf(stdout, "  %s\n", v.Original())
+	}
+	return exitFail
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestBehind(t *testing.T) {
+	available := writeFile(t, "available.txt", "1.0.0\n1.1.0\n1.2.0\n1.2.0+b2\n1.3.0-rc.1\n2.0.0\n")
+
+	tests := []struct {
+		args     []string
+		code     int
+		expected string
+	}{
+		{[]string{"1.1.0"}, exitFail, "1.1.0 is 2 versions behind 2.0.0:\n  1.2.0\n  2.0.0\n"},
+		{[]string{"-c", "^1", "v1.1.0"}, exitFail, "v1.1.0 is 1 version behind 1.2.0:\n  1.2.0\n"},
+		{[]string{"--prereleases", "-c", "^1.3.0-0", "1.2.0"}, exitFail, "1.2.0 is 1 version behind 1.3.0-rc.1:\n  1.3.0-rc.1\n"},
+		{[]string{"-c", "^1", "1.2.0"}, exitOK, "1.2.0 is up to date\n"},
+		{[]string{"2.0.0"}, exitOK, "2.0.0 is up to date\n"},
+	}
+	for _, tc := range tests {
+		args := append([]string{"behind", "--available", available}, tc.args...)
+		code, stdout, stderr := runArgs(args...)
+		if code != tc.code || stdout != tc.expected {
+			t.Errorf("Expected %q to exit with %d and print %q but got %d, %q, and %q", args, tc.code, tc.expected, code, stdout, stderr)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"behind", "1.0.0"},
+		{"behind", "--available", available},
+		{"behind", "--available", available, "nope"},
+		{"behind", "--available", available, "-c", "^nope", "1.0.0"},
+	} {
+		if code, _, stderr := runArgs(args...); code != exitInvalid || stderr == "" {
+			t.Errorf("Expected %q to exit with status 2 but got %d", args, code)
+		}
+	}
+}
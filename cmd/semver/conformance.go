@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/Masterminds/semver/v3/conformance"
+)
+
+var conformanceCommand = &command{
+	usage: "[--dialect <name>] --vectors <file>",
+	short: "check constraints against test vectors in a dialect",
+	long: "Conformance checks constraints against the test vectors in a JSON file\n" +
+		"in the dialect and prints the vectors with a different result, such as\n" +
+		"to compare with a reference implementation before rolling it out.\n\n" +
+		"The file has a list of objects with constraint, version, and expected\n" +
+		"fields or of arrays of the three in that order:\n\n" +
+		"\t[[\"^1.2.3\", \"1.8.1\", true], [\"^1.2.3\", \"2.0.0\", false]]",
+	run: runConformance,
+}
+
+func runConformance(fs *flag.FlagSet, args []string, stdout io.Writer) int {
+	var names []string
+	for _, d := range semver.Dialects() {
+		names = append(names, d.Name)
+	}
+	dialect := fs.String("dialect", semver.DialectDefault.Name, "the dialect, one of "+strings.Join(names, ", "))
+	vectors := fs.String("vectors", "", "the JSON file with the test vectors")
+	if err := fs.Parse(args); err != nil {
+		return parseFailed(err)
+	}
+	if *vectors == "" || fs.NArg() > 0 {
+		fs.Usage()
+		return exitInvalid
+	}
+
+	d, ok := semver.LookupDialect(*dialect)
+	if !ok {
+		return errorf(fs, "unknown dialect %q", *dialect)
+	}
+	vs, err := conformance.LoadFile(*vectors)
+	if err != nil {
+		return errorf(fs, "%s", err)
+	}
+
+	r := conformance.Run(vs, d)
+	for _, mm := range r.Mismatches {
+		fmt.Fprintln(stdout, mm)
+	}
+	fmt.Fprintf(stdout, "%d of %d vectors match the %s dialect\n", r.Total-len(r.Mismatches), r.Total, d.Name)
+	if !r.Conforms() {
+		return exitFail
+	}
+	return exitOK
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	vectors := writeFile(t, "vectors.json", `[
+		["^1.2.3", "1.8.1", true],
+		{"constraint": ">=1.2.3-beta.1 <2", "version": "1.2.3-beta.2", "expected": true},
+		["^1.2.3", "v1.8.1", true]
+	]`)
+
+	code, stdout, _ := runArgs("conformance", "--dialect", "npm", "--vectors", vectors)
+	if e := "3 of 3 vectors match the npm dialect\n"; code != exitOK || stdout != e {
+		t.Errorf("Expected %q but got %d and %q", e, code, stdout)
+	}
+
+	code, stdout, _ = runArgs("conformance", "--dialect", "strict", "--vectors", vectors)
+	if code != exitFail || !strings.HasSuffix(stdout, "1 of 3 vectors match the strict dialect\n") ||
+		!strings.Contains(stdout, `1: ">=1.2.3-beta.1 <2" with "1.2.3-beta.2": expected true but got false`) {
+		t.Errorf("Expected mismatches but got %d and %q", code, stdout)
+	}
+
+	code, stdout, _ = runArgs("conformance", "--vectors", vectors)
+	if code != exitFail || !strings.Contains(stdout, "the default dialect") {
+		t.Errorf("Expected the default dialect but got %d and %q", code, stdout)
+	}
+
+	bad := writeFile(t, "bad.json", `[["^1.2.3"]]`)
+	for _, args := range [][]string{
+		{"conformance"},
+		{"conformance", "--dialect", "swift", "--vectors", vectors},
+		{"conformance", "--vectors", bad},
+	} {
+		if code, _, _ := runArgs(args...); code != exitInvalid {
+			t.Errorf("Expected %q to exit with status 2 but got %d", args, code)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// constgenDirective marks the constants constgen generates constraints for.
+const constgenDirective = "//semver:constraint"
+
+var constgenCommand = &command{
+	usage: "[-o file] [dir]",
+	short: "generate constraints parsed ahead of time for go generate",
+	long: "Constgen parses the string constants in the Go package in dir, or the\n" +
+		"current directory, marked with a //semver:constraint comment and writes\n" +
+		"a file declaring a *semver.Constraints for each, named after the constant\n" +
+		"with a Constraints suffix. The constraints are built with\n" +
+		"semver.Precompiled so they are not parsed at run time, and an invalid\n" +
+		"constraint fails when the file is generated rather than when it is used.\n" +
+		"It is meant to be run by go generate:\n\n" +
+		"\t//go:generate go run github.com/Masterminds/semver/v3/cmd/semver constgen\n\n" +
+		"\t//semver:constraint\n" +
+		"\tconst supported = \"^1.2 || ~2.0\"\n\n" +
+		"Run go generate again after changing the constants.",
+	run: runConstgen,
+}
+
+// constgenConstant is a constant marked for constgen.
+type constgenConstant struct {
+	name string
+	cs   *semver.Constraints
+}
+
+func runConstgen(fs *flag.FlagSet, args []string, stdout io.Writer) int {
+	out := fs.String("o", "semver_constraints.go", "the file to write, relative to dir")
+	if err := fs.Parse(args); err != nil {
+		return parseFailed(err)
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return exitInvalid
+	}
+	dir := "."
+	if fs.NArg() == 1 {
+		dir = fs.Arg(0)
+	}
+	outPath := filepath.Join(dir, *out)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return errorf(fs, "%s", err)
+	}
+	sort.Strings(files)
+
+	fset := token.NewFileSet()
+	var pkg string
+	var consts []constgenConstant
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") || filepath.Clean(name) == filepath.Clean(outPath) {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+		if err != nil {
+			return errorf(fs, "%s", err)
+		}
+		pkg = f.Name.Name
+
+		found, err := markedConstants(fset, f)
+		if err != nil {
+			return errorf(fs, "%s", err)
+		}
+		consts = append(consts, found...)
+	}
+	if len(consts) == 0 {
+		return errorf(fs, "no constants marked %s in %s", constgenDirective, dir)
+	}
+
+	src, err := generateConstraints(pkg, consts)
+	if err != nil {
+		return errorf(fs, "%s", err)
+	}
+	if err := os.WriteFile(outPath, src, 0o644); err != nil {
+		return errorf(fs, "%s", err)
+	}
+	return exitOK
+}
+
+// markedConstants returns the string constants in the file marked with
+// constgenDirective, parsed as constraints.
+func markedConstants(fset *token.FileSet, f *ast.File) ([]constgenConstant, error) {
+	var out []constgenConstant
+	for _, d := range f.Decls {
+		gd, ok := d.(*ast.GenDecl)
+		if !ok || gd.Tok != token.CONST {
+			continue
+		}
+		for _, s := range gd.Specs {
+			vs := s.(*ast.ValueSpec)
+			if !hasDirective(vs.Doc) && !hasDirective(vs.Comment) && !(len(gd.Specs) == 1 && hasDirective(gd.Doc)) {
+				continue
+			}
+
+			for i, n := range vs.Names {
+				pos := fset.Position(n.Pos())
+				if i >= len(vs.Values) {
+					return nil, fmt.Errorf("%s: %s has no value", pos, n.Name)
+				}
+				lit, ok := vs.Values[i].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					return nil, fmt.Errorf("%s: %s is not a string literal", pos, n.Name)
+				}
+				s, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %s: %w", pos, n.Name, err)
+				}
+				cs, err := semver.NewConstraint(s)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %s: %w", pos, n.Name, err)
+				}
+				out = append(out, constgenConstant{name: n.Name, cs: cs})
+			}
+		}
+	}
+	return out, nil
+}
+
+// hasDirective reports if a comment group has a line that is the directive.
+func hasDirective(cg *ast.CommentGroup) bool {
+	if cg == nil {
+		return false
+	}
+	for _, c := range cg.List {
+		if strings.TrimSpace(c.Text) == constgenDirective {
+			return true
+		}
+	}
+	return false
+}
+
+// generateConstraints returns the formatted source of the generated file.
+func generateConstraints(pkg string, consts []constgenConstant) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by \"semver constgen\"; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	fmt.Fprintf(&b, "import \"github.com/Masterminds/semver/v3\"\n")
+
+	for _, c := range consts {
+		fmt.Fprintf(&b, "\n// %sConstraints is %s, %s, parsed ahead of time.\n", c.name, c.name, strconv.Quote(c.cs.String()))
+		fmt.Fprintf(&b, "var %sConstraints = semver.Precompiled(\n", c.name)
+		for _, g := range c.cs.Precompile() {
+			fmt.Fprintf(&b, "[]semver.PrecompiledComparator{\n")
+			for _, p := range g {
+				fmt.Fprintf(&b, "{%s},\n", precompiledFields(p))
+			}
+			fmt.Fprintf(&b, "},\n")
+		}
+		fmt.Fprintf(&b, ")\n")
+	}
+
+	return format.Source(b.Bytes())
+}
+
+// precompiledFields returns the fields of a PrecompiledComparator literal
+// leaving out the ones that are the zero value.
+func precompiledFields(p semver.PrecompiledComparator) string {
+	var f []string
+	str := func(name, v string) {
+		if v != "" {
+			f = append(f, name+": "+strconv.Quote(v))
+		}
+	}
+	num := func(name string, v uint64) {
+		if v != 0 {
+			f = append(f, name+": "+strconv.FormatUint(v, 10))
+		}
+	}
+	boolean := func(name string, v bool) {
+		if v {
+			f = append(f, name+": true")
+		}
+	}
+
+	str("Operator", p.Operator)
+	str("Version", p.Version)
+	num("Major", p.Major)
+	num("Minor", p.Minor)
+	num("Patch", p.Patch)
+	str("Prerelease", p.Prerelease)
+	str("Metadata", p.Metadata)
+	boolean("AnyMajor", p.AnyMajor)
+	boolean("AnyMinor", p.AnyMinor)
+	boolean("AnyPatch", p.AnyPatch)
+	num("Start", uint64(p.Start))
+	num("End", uint64(p.End))
+	return strings.Join(f, ", ")
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConstgen(t *testing.T) {
+	dir := filepath.Dir(writeFile(t, "deps.go", `package deps
+
+//go:generate go run github.com/Masterminds/semver/v3/cmd/semver constgen
+
+//semver:constraint
+const supported = "^1.2 || ~2.0.x"
+
+const (
+	// Minimum is the oldest supported release.
+	//semver:constraint
+	Minimum = ">=v1.0.0-beta"
+
+	unmarked = "nope"
+)
+`))
+
+	code, _, stderr := runArgs("constgen", dir)
+	if code != exitOK {
+		t.Fatalf("Expected exit status 0 but got %d and %q", code, stderr)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "semver_constraints.go"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	src := string(b)
+	if _, err := parser.ParseFile(token.NewFileSet(), "", b, 0); err != nil {
+		t.Errorf("Expected the generated file to parse but got %s", err)
+	}
+	for _, s := range []string{
+		`// Code generated by "semver constgen"; DO NOT EDIT.`,
+		"package deps",
+		`var supportedConstraints = semver.Precompiled(`,
+		`{Operator: "^", Version: "1.2", Major: 1, Minor: 2, AnyPatch: true, End: 4}`,
+		`{Operator: "~", Version: "2.0.x", Major: 2, AnyPatch: true, Start: 8, End: 14}`,
+		`var MinimumConstraints = semver.Precompiled(`,
+		`{Operator: ">=", Version: "v1.0.0-beta", Major: 1, Prerelease: "beta", End: 13}`,
+	} {
+		if !strings.Contains(src, s) {
+			t.Errorf("Expected %q in %s", s, src)
+		}
+	}
+	if strings.Contains(src, "unmarked") {
+		t.Errorf("Expected the unmarked constant to be skipped in %s", src)
+	}
+
+	// The generated file is skipped when generating again.
+	if code, _, stderr := runArgs("constgen", dir); code != exitOK {
+		t.Errorf("Expected generating again to succeed but got %d and %q", code, stderr)
+	}
+
+	bad := filepath.Dir(writeFile(t, "bad.go", "package bad\n\n//semver:constraint\nconst r = \"^1.2 ||| 3\"\n"))
+	if code, _, stderr := runArgs("constgen", bad); code != exitInvalid || !strings.Contains(stderr, "bad.go:4") {
+		t.Errorf("Expected an invalid constraint to fail with its position but got %d and %q", code, stderr)
+	}
+	empty := filepath.Dir(writeFile(t, "empty.go", "package empty\n"))
+	if code, _, _ := runArgs("constgen", empty); code != exitInvalid {
+		t.Errorf("Expected no marked constants to fail but got %d", code)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+var explainCommand = &command{
+	usage: "[--json] <constraint> <version>",
+	short: "print why a version does or does not satisfy a constraint",
+	long: "Explain prints how the version was checked against each group and\n" +
+		"constraint, and why any of them failed.",
+	run: runExplain,
+}
+
+func runExplain(fs *flag.FlagSet, args []string, stdout io.Writer) int {
+	asJSON := fs.Bool("json", false, "print the explanation as JSON")
+	if err := fs.Parse(args); err != nil {
+		return parseFailed(err)
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return exitInvalid
+	}
+
+	c, err := semver.NewConstraint(fs.Arg(0))
+	if err != nil {
+		return errorf(fs, "%s", err)
+	}
+	v, err := semver.NewVersion(fs.Arg(1))
+	if err != nil {
+		return errorf(fs, "%s", err)
+	}
+
+	ex := c.Explain(v)
+	if *asJSON {
+		b, err := json.Marshal(ex)
+		if err != nil {
+			return errorf(fs, "%s", err)
+		}
+		fmt.Fprintf(stdout, "%s\n", b)
+	} else {
+		fmt.Fprintln(stdout, ex)
+	}
+
+	if !ex.Satisfied {
+		return exitFail
+	}
+	return exitOK
+}
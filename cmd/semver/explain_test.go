@@ -0,0 +1,33 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	code, stdout, _ := runArgs("explain", ">=2.1 <2.3 || ^3", "2.4.0")
+	if code != exitFail {
+		t.Errorf("Expected exit status 1 but got %d", code)
+	}
+	for _, s := range []string{`2.4.0 does not satisfy ">=2.1 <2.3 || ^3"`, "group 1", "<2.3: fail", "group 2"} {
+		if !strings.Contains(stdout, s) {
+			t.Errorf("Expected %q in %q", s, stdout)
+		}
+	}
+
+	code, stdout, _ = runArgs("explain", "--json", "^2", "2.4.0")
+	if code != exitOK || !strings.HasPrefix(stdout, `{"schema":"semver.explanation/v1"`) {
+		t.Errorf("Expected a JSON explanation but got %d and %q", code, stdout)
+	}
+
+	for _, args := range [][]string{
+		{"explain", "^2"},
+		{"explain", "nope", "2.4.0"},
+		{"explain", "^2", "nope"},
+	} {
+		if code, _, stderr := runArgs(args...); code != exitInvalid || stderr == "" {
+			t.Errorf("Expected %q to exit with status 2 but got %d", args, code)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+/*
+Command semver works with semantic versions and constraints from the command
+line, exposing parts of the semver package to release managers and scripts.
+
+Usage:
+
+	semver <command> [arguments]
+
+The commands are:
+
+	behind         print the available versions newer than a version
+	conformance    check constraints against test vectors in a dialect
+	constgen       generate constraints parsed ahead of time for go generate
+	explain        print why a version does or does not satisfy a constraint
+	matrix         print the versions supported by a set of components
+	policy         check versions against the rules of a policy
+	registry-diff  compare the versions from two sources
+	resolve        select versions of packages and their requirements
+	span           print the available versions between two versions
+
+Run "semver help <command>" for the arguments of a command.
+
+The exit status is 0 on success, 1 when the result is negative, such as a
+version that does not satisfy a constraint, and 2 for invalid arguments.
+*/
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Exit statuses of the commands.
+const (
+	exitOK      = 0
+	exitFail    = 1
+	exitInvalid = 2
+)
+
+// command is a subcommand of semver.
+type command struct {
+	// usage is the arguments of the command after its name.
+	usage string
+
+	// short is a one line description of the command for the list of
+	// commands.
+	short string
+
+	// long describes the command in full for its usage, which is printed by
+	// semver help <command>.
+	long string
+
+	// run defines the flags of the command on fs, parses args with them,
+	// and runs the command.
+	run func(fs *flag.FlagSet, args []string, stdout io.Writer) int
+}
+
+var commands = map[string]*command{
+	"behind":        behindCommand,
+	"conformance":   conformanceCommand,
+	"constgen":      constgenCommand,
+	"explain":       explainCommand,
+	"matrix":        matrixCommand,
+	"policy":        policyCommand,
+	"registry-diff": registryDiffCommand,
+	"resolve":       resolveCommand,
+	"span":          spanCommand,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command named by the first argument and returns the exit
+// status.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitInvalid
+	}
+
+	name := args[0]
+	if name == "help" || name == "-h" || name == "--help" {
+		if len(args) > 1 {
+			if c, ok := commands[args[1]]; ok {
+				return c.run(newFlagSet(args[1], c, stdout), []string{"-h"}, stdout)
+			}
+		}
+		usage(stdout)
+		return exitOK
+	}
+
+	c, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "semver: unknown command %q\n", name)
+		usage(stderr)
+		return exitInvalid
+	}
+
+	return c.run(newFlagSet(name, c, stderr), args[1:], stdout)
+}
+
+// newFlagSet returns the flag set for a command with its usage written to w.
+func newFlagSet(name string, c *command, w io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet("semver "+name, flag.ContinueOnError)
+	fs.SetOutput(w)
+	fs.Usage = func() {
+		fmt.Fprintf(w, "usage: semver %s %s\n\n%s\n", name, c.usage, c.long)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "usage: semver <command> [arguments]\n\ncommands:\n")
+	names := make([]string, 0, len(commands))
+	for n := range commands {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		fmt.Fprintf(w, "  %-14s %s\n", n, commands[n].short)
+	}
+}
+
+// parseFailed returns the exit status for an error from parsing flags.
+func parseFailed(err error) int {
+	if err == flag.ErrHelp {
+		return exitOK
+	}
+	return exitInvalid
+}
+
+// errorf writes an error for a command to stderr and returns exitInvalid.
+func errorf(fs *flag.FlagSet, format string, args ...interface{}) int {
+	fmt.Fprintf(fs.Output(), "%s: %s\n", fs.Name(), fmt.Sprintf(format, args...))
+	return exitInvalid
+}
+
+// line is a line of an input file along with its line number.
+type line struct {
+	num  int
+	text string
+}
+
+// readLines returns the lines of a file that are not blank or comments
+// starting with #, with surrounding whitespace removed.
+func readLines(name string) ([]line, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []line
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		t := strings.TrimSpace(sc.Text())
+		if t == "" || strings.HasPrefix(t, "#") {
+			continue
+		}
+		lines = append(lines, line{num: n, text: t})
+	}
+	return lines, sc.Err()
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runArgs runs the command line and returns its exit status and output.
+func runArgs(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// writeFile writes a file to a temporary directory and returns its path.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return p
+}
+
+func TestRun(t *testing.T) {
+	code, _, stderr := runArgs()
+	if code != exitInvalid || !strings.Contains(stderr, "matrix") {
+		t.Errorf("Expected usage with exit status 2 but got %d and %q", code, stderr)
+	}
+
+	code, _, stderr = runArgs("frobnicate")
+	if code != exitInvalid || !strings.Contains(stderr, `unknown command "frobnicate"`) {
+		t.Errorf("Expected an unknown command but got %d and %q", code, stderr)
+	}
+
+	code, stdout, _ := runArgs("help", "matrix")
+	if code != exitOK || !strings.Contains(stdout, "usage: semver matrix") || !strings.Contains(stdout, "-req") {
+		t.Errorf("Expected help for matrix but got %d and %q", code, stdout)
+	}
+
+	if !strings.Contains(stdout, "Matrix prints the versions supported") {
+		t.Errorf("Expected the description of matrix in %q", stdout)
+	}
+
+	code, stdout, _ = runArgs("help")
+	if code != exitOK || !strings.Contains(stdout, "commands:") {
+		t.Errorf("Expected help but got %d and %q", code, stdout)
+	}
+	if n := strings.Count(stdout, "\n"); n != len(commands)+3 {
+		t.Errorf("Expected one line for each command but got %q", stdout)
+	}
+}
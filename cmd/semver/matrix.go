@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+var matrixCommand = &command{
+	usage: "--req name=constraint...",
+	short: "print the versions supported by a set of components",
+	long: "Matrix prints the versions supported by all of the components, and the\n" +
+		"versions each pair supports as a table when there is more than one.",
+	run: runMatrix,
+}
+
+// requirements is a repeatable flag of name=constraint pairs.
+type requirements []semver.Component
+
+func (r *requirements) String() string {
+	s := make([]string, len(*r))
+	for i, c := range *r {
+		s[i] = c.Name + "=" + c.Constraint.String()
+	}
+	return strings.Join(s, " ")
+}
+
+func (r *requirements) Set(s string) error {
+	name, con, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("%q is not name=constraint", s)
+	}
+	c, err := semver.NewConstraint(con)
+	if err != nil {
+		return err
+	}
+	*r = append(*r, semver.Component{Name: name, Constraint: c})
+	return nil
+}
+
+func runMatrix(fs *flag.FlagSet, args []string, stdout io.Writer) int {
+	var reqs requirements
+	fs.Var(&reqs, "req", "a component and its constraint, such as app='^2' (repeatable)")
+	if err := fs.Parse(args); err != nil {
+		return parseFailed(err)
+	}
+	if len(reqs) == 0 || fs.NArg() > 0 {
+		fs.Usage()
+		return exitInvalid
+	}
+
+	m := semver.Compatibility(reqs...)
+	if len(reqs) == 1 {
+		fmt.Fprintln(stdout, m.Overall)
+	} else {
+		fmt.Fprintln(stdout, m)
+	}
+	if m.Overall.IsEmpty() {
+		return exitFail
+	}
+	return exitOK
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatrix(t *testing.T) {
+	code, stdout, _ := runArgs("matrix", "--req", "app=^2", "--req", "plugin=>=2.3 <2.6")
+	if code != exitOK {
+		t.Errorf("Expected exit status 0 but got %d", code)
+	}
+	e := "| | app | plugin |\n" +
+		"|---|---|---|\n" +
+		"| app | ^2 | ^2 >=2.3 <2.6 |\n" +
+		"| plugin | ^2 >=2.3 <2.6 | >=2.3 <2.6 |\n" +
+		"\n" +
+		"Overall: ^2 >=2.3 <2.6\n"
+	if stdout != e {
+		t.Errorf("Expected %q but got %q", e, stdout)
+	}
+
+	code, stdout, _ = runArgs("matrix", "--req", "app=^2")
+	if code != exitOK || stdout != "^2\n" {
+		t.Errorf("Expected the window of one component but got %d and %q", code, stdout)
+	}
+
+	code, stdout, _ = runArgs("matrix", "--req", "app=^1", "--req", "plugin=^2")
+	if code != exitFail || !strings.Contains(stdout, "Overall: none") {
+		t.Errorf("Expected no window with exit status 1 but got %d and %q", code, stdout)
+	}
+
+	for _, args := range [][]string{
+		{"matrix"},
+		{"matrix", "--req", "app"},
+		{"matrix", "--req", "app=nope"},
+		{"matrix", "--req", "app=^2", "extra"},
+	} {
+		if code, _, _ := runArgs(args...); code != exitInvalid {
+			t.Errorf("Expected %q to exit with status 2 but got %d", args, code)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+var policyCommand = &command{
+	usage: "--rules <file> <version>...",
+	short: "check versions against the rules of a policy",
+	long: "Policy checks each version against the rules in the file and prints\n" +
+		"whether it passes and which rules it breaks. The rules file must be\n" +
+		"JSON. YAML is only read when it is written as JSON:\n\n" +
+		"\t{\n" +
+		"\t  \"allow\": \">=1.0.0 <3.0.0\",\n" +
+		"\t  \"no_prerelease\": true,\n" +
+		"\t  \"deny\": [\"2.3.0\"]\n" +
+		"\t}\n",
+	run: runPolicy,
+}
+
+// policyRules is the rules file of the policy command.
+type policyRules struct {
+	// Allow is a constraint versions need to satisfy.
+	Allow string `json:"allow"`
+
+	// NoPrerelease does not allow prereleases.
+	NoPrerelease bool `json:"no_prerelease"`
+
+	// Deny lists versions that are not allowed.
+	Deny []string `json:"deny"`
+}
+
+// readPolicy reads a rules file into a policy.
+func readPolicy(name string) (*semver.Policy, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rules policyRules
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&rules); err != nil {
+		var se *json.SyntaxError
+		if errors.As(err, &se) {
+			return nil, fmt.Errorf("%s: the rules need to be JSON: %w", name, err)
+		}
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("%s: the rules need to be a single JSON object", name)
+	}
+
+	p := &semver.Policy{}
+	if rules.Allow != "" {
+		c, err := semver.NewConstraint(rules.Allow)
+		if err != nil {
+			return nil, fmt.Errorf("%s: allow: %w", name, err)
+		}
+		p.Rules = append(p.Rules, semver.AllowedRangeRule{Constraint: c})
+	}
+	if rules.NoPrerelease {
+		p.Rules = append(p.Rules, semver.NoPrereleaseRule{})
+	}
+	if len(rules.Deny) > 0 {
+		var r semver.DenyRule
+		for _, d := range rules.Deny {
+			v, err := semver.NewVersion(d)
+			if err != nil {
+				return nil, fmt.Errorf("%s: deny: %w", name, err)
+			}
+			r.Versions = append(r.Versions, v)
+		}
+		p.Rules = append(p.Rules, r)
+	}
+	return p, nil
+}
+
+func runPolicy(fs *flag.FlagSet, args []string, stdout io.Writer) int {
+	rules := fs.String("rules", "", "the file with the rules of the policy")
+	if err := fs.Parse(args); err != nil {
+		return parseFailed(err)
+	}
+	if *rules == "" || fs.NArg() == 0 {
+		fs.Usage()
+		return exitInvalid
+	}
+
+	p, err := readPolicy(*rules)
+	if err != nil {
+		return errorf(fs, "%s", err)
+	}
+
+	versions := make([]*semver.Version, fs.NArg())
+	for i, s := range fs.Args() {
+		if versions[i], err = semver.NewVersion(s); err != nil {
+			return errorf(fs, "%s", err)
+		}
+	}
+
+	code := exitOK
+	for _, v := range versions {
+		vd := p.EvaluateVersion(v)
+		if vd.Allowed {
+			fmt.Fprintf(stdout, "%s: pass\n", v.Original())
+			continue
+		}
+
+		code = exitFail
+		fmt.Fprintf(stdout, "%s: fail\n", v.Original())
+		for _, vi := range vd.Violations {
+			fmt.Fprintf(stdout, "  %s\n", vi)
+		}
+	}
+	return code
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPolicy(t *testing.T) {
+	rules := writeFile(t, "policy.yaml", `{"allow": ">=1.0.0 <3.0.0", "no_prerelease": true, "deny": ["2.3.0"]}`)
+
+	code, stdout, _ := runArgs("policy", "--rules", rules, "1.2.0", "v2.3.0", "3.0.0-beta.1")
+	if code != exitFail {
+		t.Errorf("Expected exit status 1 but got %d", code)
+	}
+	e := "1.2.0: pass\n" +
+		"v2.3.0: fail\n" +
+		"  deny: 2.3.0 is denied\n" +
+		"3.0.0-beta.1: fail\n" +
+		"  allowed-range: 3.0.0-beta.1 is not in the allowed range >=1.0.0 <3.0.0\n" +
+		"  no-prerelease: 3.0.0-beta.1 is a prerelease\n"
+	if stdout != e {
+		t.Errorf("Expected %q but got %q", e, stdout)
+	}
+
+	if code, _, _ := runArgs("policy", "--rules", rules, "1.2.0", "2.9.0"); code != exitOK {
+		t.Errorf("Expected exit status 0 but got %d", code)
+	}
+
+	bad := writeFile(t, "bad.yaml", `{"deny": ["nope"]}`)
+	unknown := writeFile(t, "unknown.json", `{"allowed": ">=1.0.0"}`)
+	yaml := writeFile(t, "rules.yaml", "allow: \">=1.0.0\"\n")
+	for _, args := range [][]string{
+		{"policy", "1.2.0"},
+		{"policy", "--rules", rules},
+		{"policy", "--rules", rules, "nope"},
+		{"policy", "--rules", bad, "1.2.0"},
+		{"policy", "--rules", unknown, "1.2.0"},
+		{"policy", "--rules", filepath.Join(t.TempDir(), "missing.yaml"), "1.2.0"},
+	} {
+		if code, _, _ := runArgs(args...); code != exitInvalid {
+			t.Errorf("Expected %q to exit with status 2 but got %d", args, code)
+		}
+	}
+
+	_, _, stderr := runArgs("policy", "--rules", yaml, "1.2.0")
+	if !strings.Contains(stderr, "the rules need to be JSON") {
+		t.Errorf("Expected an error that the rules need to be JSON but got %q", stderr)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+var registryDiffCommand = &command{
+	usage: "[--common=false] <source-a> <source-b>",
+	short: "compare the versions from two sources",
+	long: "Registry-diff compares the versions from two sources, such as a registry\n" +
+		"and its mirror, and prints the versions only in the first, the versions\n" +
+		"only in the second, and the versions in both. Versions are compared by\n" +
+		"their canonical form so v1.2.3 and 1.2.3 are the same version.\n\n" +
+		sourceUsage,
+	run: runRegistryDiff,
+}
+
+func runRegistryDiff(fs *flag.FlagSet, args []string, stdout io.Writer) int {
+	common := fs.Bool("common", true, "print the versions in both sources")
+	if err := fs.Parse(args); err != nil {
+		return parseFailed(err)
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return exitInvalid
+	}
+
+	ctx := context.Background()
+	a, err := readSource(ctx, fs.Arg(0))
+	if err != nil {
+		return errorf(fs, "%s", err)
+	}
+	b, err := readSource(ctx, fs.Arg(1))
+	if err != nil {
+		return errorf(fs, "%s", err)
+	}
+
+	onlyA, onlyB, both := diffVersions(a, b)
+	printVersions(stdout, "only in "+fs.Arg(0), onlyA)
+	printVersions(stdout, "only in "+fs.Arg(1), onlyB)
+	if *common {
+		printVersions(stdout, "in both", both)
+	}
+
+	if len(onlyA) > 0 || len(onlyB) > 0 {
+		return exitFail
+	}
+	return exitOK
+}
+
+// diffVersions splits the versions of two sorted collections into those only
+// in a, those only in b, and those in both, each in sorted order.
+func diffVersions(a, b semver.Collection) (onlyA, onlyB, both []string) {
+	inA := make(map[string]bool, len(a))
+	for _, v := range a {
+		inA[v.String()] = true
+	}
+	inB := make(map[string]bool, len(b))
+	for _, v := range b {
+		inB[v.String()] = true
+	}
+
+	seen := make(map[string]bool, len(a))
+	for _, v := range a {
+		s := v.String()
+		switch {
+		case seen[s]:
+		case inB[s]:
+			both = append(both, s)
+		default:
+			onlyA = append(onlyA, s)
+		}
+		seen[s] = true
+	}
+	for _, v := range b {
+		s := v.String()
+		if !inA[s] && !seen[s] {
+			onlyB = append(onlyB, s)
+		}
+		seen[s] = true
+	}
+	return onlyA, onlyB, both
+}
+
+// printVersions prints a heading with the number of versions followed by the
+// versions indented on their own lines.
+func printVersions(w io.Writer, heading string, vs []string) {
+	fmt.Fprintf(w, "%s (%d):\n", heading, len(vs))
+	for _, v := range vs {
+		fmt.Fprintf(w, "  %s\n", v)
+	}
+}
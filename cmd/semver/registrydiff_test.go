@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegistryDiff(t *testing.T) {
+	a := writeFile(t, "a.txt", "1.0.0\nv1.1.0\n1.2.0\n# retracted\n2.0.0-beta.1\n")
+	b := writeFile(t, "b.txt", "1.1.0\n1.2.0\n1.3.0\n")
+
+	code, stdout, _ := runArgs("registry-diff", a, b)
+	if code != exitFail {
+		t.Errorf("Expected exit status 1 but got %d", code)
+	}
+	expected := "only in " + a + " (2):\n  1.0.0\n  2.0.0-beta.1\n" +
+		"only in " + b + " (1):\n  1.3.0\n" +
+		"in both (2):\n  1.1.0\n  1.2.0\n"
+	if stdout != expected {
+		t.Errorf("Expected %q but got %q", expected, stdout)
+	}
+
+	code, stdout, _ = runArgs("registry-diff", "--common=false", b, b)
+	if code != exitOK || strings.Contains(stdout, "in both") {
+		t.Errorf("Expected the same versions without the common ones but got %d and %q", code, stdout)
+	}
+
+	bad := writeFile(t, "bad.txt", "1.0.0\nnope\n")
+	for _, args := range [][]string{
+		{"registry-diff", a},
+		{"registry-diff", a, bad},
+		{"registry-diff", a, filepath.Join(t.TempDir(), "missing.txt")},
+		{"registry-diff", a, "oci:registry-only"},
+	} {
+		if code, _, stderr := runArgs(args...); code != exitInvalid || stderr == "" {
+			t.Errorf("Expected %q to exit with status 2 but got %d", args, code)
+		}
+	}
+}
+
+func TestRegistryDiffGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "initial"},
+		{"tag", "v1.0.0"},
+		{"tag", "v1.1.0"},
+		{"tag", "latest"},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %s: %s", args, err, out)
+		}
+	}
+
+	b := writeFile(t, "b.txt", "1.0.0\n1.1.0\n")
+	code, stdout, stderr := runArgs("registry-diff", "git:"+dir, b)
+	if code != exitOK || !strings.Contains(stdout, "in both (2):") {
+		t.Errorf("Expected the tags to match the file but got %d, %q, and %q", code, stdout, stderr)
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/Masterminds/semver/v3/resolver"
+)
+
+var resolveCommand = &command{
+	usage: "--requirements <file> --available <file>",
+	short: "select versions of packages and their requirements",
+	long: "Resolve selects a version of each required package and of the packages\n" +
+		"they require, and prints them as a lock file. When there is no such\n" +
+		"selection it prints the requirements that conflict.\n\n" +
+		"The requirements file has a package and a constraint on each line:\n\n" +
+		"\tapp ^2.1\n\n" +
+		"The available file has a package and a version on each line, followed\n" +
+		"by the requirements of the version after a colon separated by semicolons:\n\n" +
+		"\tapp 2.1.0: lib >=1.2 <2; log ^1\n\n" +
+		"Blank lines and lines starting with # are ignored.",
+	run: runResolve,
+}
+
+// fileSource is a resolver.Source read from an available file.
+type fileSource struct {
+	versions map[string]semver.Collection
+	deps     map[string]map[string]*semver.Constraints
+}
+
+func (s *fileSource) Versions(name string) (semver.Collection, error) {
+	return s.versions[name], nil
+}
+
+func (s *fileSource) Dependencies(name string, v *semver.Version) (map[string]*semver.Constraints, error) {
+	return s.deps[name+"@"+v.String()], nil
+}
+
+// readRequirements reads lines of a package name followed by a constraint.
+func readRequirements(name string) (map[string]*semver.Constraints, error) {
+	lines, err := readLines(name)
+	if err != nil {
+		return nil, err
+	}
+
+	reqs := make(map[string]*semver.Constraints)
+	for _, l := range lines {
+		pkg, c, err := parseRequirement(l.text)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", name, l.num, err)
+		}
+		reqs[pkg] = c
+	}
+	return reqs, nil
+}
+
+// readAvailable reads lines of a package name and version followed by the
+// requirements of the version.
+func readAvailable(name string) (*fileSource, error) {
+	lines, err := readLines(name)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &fileSource{
+		versions: make(map[string]semver.Collection),
+		deps:     make(map[string]map[string]*semver.Constraints),
+	}
+	for _, l := range lines {
+		head, deps, _ := strings.Cut(l.text, ":")
+		f := strings.Fields(head)
+		if len(f) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected a package and a version", name, l.num)
+		}
+		v, err := semver.NewVersion(f[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", name, l.num, err)
+		}
+		s.versions[f[0]] = append(s.versions[f[0]], v)
+
+		for _, d := range strings.Split(deps, ";") {
+			if strings.TrimSpace(d) == "" {
+				continue
+			}
+			pkg, c, err := parseRequirement(d)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", name, l.num, err)
+			}
+			k := f[0] + "@" + v.String()
+			if s.deps[k] == nil {
+				s.deps[k] = make(map[string]*semver.Constraints)
+			}
+			s.deps[k][pkg] = c
+		}
+	}
+	return s, nil
+}
+
+// parseRequirement parses a package name followed by a constraint.
+func parseRequirement(s string) (string, *semver.Constraints, error) {
+	pkg, con, ok := strings.Cut(strings.TrimSpace(s), " ")
+	if !ok {
+		return "", nil, fmt.Errorf("expected a package and a constraint in %q", s)
+	}
+	c, err := semver.NewConstraint(con)
+	if err != nil {
+		return "", nil, err
+	}
+	return pkg, c, nil
+}
+
+func runResolve(fs *flag.FlagSet, args []string, stdout io.Writer) int {
+	reqFile := fs.String("requirements", "", "the file with the required packages")
+	availFile := fs.String("available", "", "the file with the available versions")
+	if err := fs.Parse(args); err != nil {
+		return parseFailed(err)
+	}
+	if *reqFile == "" || *availFile == "" || fs.NArg() > 0 {
+		fs.Usage()
+		return exitInvalid
+	}
+
+	reqs, err := readRequirements(*reqFile)
+	if err != nil {
+		return errorf(fs, "%s", err)
+	}
+	src, err := readAvailable(*availFile)
+	if err != nil {
+		return errorf(fs, "%s", err)
+	}
+
+	sel, err := resolver.Resolve(src, reqs)
+	var ce *resolver.ConflictError
+	switch {
+	case errors.As(err, &ce):
+		fmt.Fprintf(stdout, "no version of %s satisfies:\n", ce.Package)
+		for _, r := range ce.Requirements {
+			fmt.Fprintf(stdout, "  %s\n", r)
+		}
+		return exitFail
+	case err != nil:
+		return errorf(fs, "%s", err)
+	}
+
+	names := make([]string, 0, len(sel))
+	for n := range sel {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		fmt.Fprintf(stdout, "%s %s\n", n, sel[n])
+	}
+	return exitOK
+}
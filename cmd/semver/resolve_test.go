@@ -0,0 +1,49 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	available := writeFile(t, "versions.txt", `# app and its libraries
+app 2.1.0: lib >=1.2 <2; log ^1
+app 2.2.0: lib ^2
+
+lib 1.2.0
+lib 1.3.0: log ~1.1
+lib 2.0.0
+log 1.1.4
+log 1.2.0
+`)
+
+	reqs := writeFile(t, "reqs.txt", "app ^2.1\n")
+	code, stdout, _ := runArgs("resolve", "--requirements", reqs, "--available", available)
+	if e := "app 2.2.0\nlib 2.0.0\n"; code != exitOK || stdout != e {
+		t.Errorf("Expected %q but got %d and %q", e, code, stdout)
+	}
+
+	reqs = writeFile(t, "reqs.txt", "app ~2.1.0\nlog >=1.2\n")
+	code, stdout, _ = runArgs("resolve", "--requirements", reqs, "--available", available)
+	if e := "app 2.1.0\nlib 1.2.0\nlog 1.2.0\n"; code != exitOK || stdout != e {
+		t.Errorf("Expected %q but got %d and %q", e, code, stdout)
+	}
+
+	reqs = writeFile(t, "reqs.txt", "app ~2.1.0\nlib ^2\n")
+	code, stdout, _ = runArgs("resolve", "--requirements", reqs, "--available", available)
+	if code != exitFail || stdout == "" {
+		t.Errorf("Expected a conflict but got %d and %q", code, stdout)
+	}
+
+	bad := writeFile(t, "bad.txt", "app\n")
+	for _, args := range [][]string{
+		{"resolve", "--requirements", reqs},
+		{"resolve", "--requirements", bad, "--available", available},
+		{"resolve", "--requirements", reqs, "--available", bad},
+		{"resolve", "--requirements", reqs, "--available", filepath.Join(t.TempDir(), "missing.txt")},
+	} {
+		if code, _, _ := runArgs(args...); code != exitInvalid {
+			t.Errorf("Expected %q to exit with status 2 but got %d", args, code)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/Masterminds/semver/v3/source"
+)
+
+// sourceUsage describes the forms of the sources of versions accepted by
+// readSource.
+const sourceUsage = "A source is a file with a version on each line, git:<dir> for the tags\n" +
+	"of a git repository, goproxy:<module> for a module from the Go module\n" +
+	"proxy, npm:<package> for a package from the npm registry, or\n" +
+	"oci:<registry>/<repository> for the tags of images in an OCI registry.\n" +
+	"Tags that are not versions are skipped."
+
+// readSource returns the versions from a source in one of the forms in
+// sourceUsage, sorted from lowest to highest.
+func readSource(ctx context.Context, spec string) (semver.Collection, error) {
+	kind, name, ok := strings.Cut(spec, ":")
+	if !ok {
+		return readVersions(spec)
+	}
+
+	var vs semver.Collection
+	var err error
+	switch kind {
+	case "git":
+		vs, err = gitTags(ctx, name)
+	case "goproxy":
+		vs, err = (&source.GoProxy{}).List(ctx, name)
+	case "npm":
+		vs, err = (&source.NPM{}).List(ctx, name)
+	case "oci":
+		registry, repo, found := strings.Cut(name, "/")
+		if !found {
+			return nil, fmt.Errorf("%s: expected oci:<registry>/<repository>", spec)
+		}
+		vs, err = (&source.OCI{URL: "https://" + registry}).List(ctx, repo)
+	default:
+		return readVersions(spec)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", spec, err)
+	}
+
+	sort.Sort(vs)
+	return vs, nil
+}
+
+// readVersions reads a file with a version on each line, sorted from lowest
+// to highest.
+func readVersions(name string) (semver.Collection, error) {
+	lines, err := readLines(name)
+	if err != nil {
+		return nil, err
+	}
+
+	vs := make(semver.Collection, len(lines))
+	for i, l := range lines {
+		if vs[i], err = semver.NewVersion(l.text); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", name, l.num, err)
+		}
+	}
+	sort.Sort(vs)
+	return vs, nil
+}
+
+// gitTags returns the tags of the git repository in dir that are versions.
+func gitTags(ctx context.Context, dir string) (semver.Collection, error) {
+	out, err := exec.CommandContext(ctx, "git", "-C", dir, "tag", "--list").Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok && len(ee.Stderr) > 0 {
+			return nil, fmt.Errorf("git tag: %s", strings.TrimSpace(string(ee.Stderr)))
+		}
+		return nil, err
+	}
+
+	var vs semver.Collection
+	for _, t := range strings.Fields(string(out)) {
+		if v, err := semver.NewVersion(t); err == nil {
+			vs = append(vs, v)
+		}
+	}
+	return vs, nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+var spanCommand = &command{
+	usage: "--available <file> [--include-from] [--include-to=false] [--prereleases] <from> <to>",
+	short: "print the available versions between two versions",
+	long: "Span prints the available versions between two versions, such as the\n" +
+		"releases an upgrade goes past, for release notes. By default the from\n" +
+		"version is left out, the to version is included, and prereleases are\n" +
+		"left out unless they are one of the two versions.\n\n" +
+		"The available file has a version on each line. Blank lines and lines\n" +
+		"starting with # are ignored.",
+	run: runSpan,
+}
+
+func runSpan(fs *flag.FlagSet, args []string, stdout io.Writer) int {
+	available := fs.String("available", "", "the file of available versions")
+	includeFrom := fs.Bool("include-from", false, "include the from version")
+	includeTo := fs.Bool("include-to", true, "include the to version")
+	prereleases := fs.Bool("prereleases", false, "include prereleases")
+	if err := fs.Parse(args); err != nil {
+		return parseFailed(err)
+	}
+	if fs.NArg() != 2 || *available == "" {
+		fs.Usage()
+		return exitInvalid
+	}
+
+	from, err := semver.NewVersion(fs.Arg(0))
+	if err != nil {
+		return errorf(fs, "%s", err)
+	}
+	to, err := semver.NewVersion(fs.Arg(1))
+	if err != nil {
+		return errorf(fs, "%s", err)
+	}
+	if from.GreaterThan(to) {
+		return errorf(fs, "%s is above %s", from, to)
+	}
+	vs, err := readVersions(*available)
+	if err != nil {
+		return errorf(fs, "%s", err)
+	}
+
+	opts := semver.BetweenOptions{ExcludeLo: !*includeFrom, ExcludeHi: !*includeTo}
+	for _, v := range vs.Between(from, to, opts) {
+		if v.Prerelease() != "" && !*prereleases && !v.Equal(from) && !v.Equal(to) {
+			continue
+		}
+		fmt.Fprintln(stdout, v.Original())
+	}
+	return exitOK
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestSpan(t *testing.T) {
+	available := writeFile(t, "available.txt", "1.0.0\n1.1.0\nv1.2.0\n1.3.0-rc.1\n1.3.0\n2.0.0\n")
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"1.0.0", "1.3.0"}, "1.1.0\nv1.2.0\n1.3.0\n"},
+		{[]string{"--include-from", "--include-to=false", "1.0.0", "1.3.0"}, "1.0.0\n1.1.0\nv1.2.0\n"},
+		{[]string{"--prereleases", "1.2.0", "1.3.0"}, "1.3.0-rc.1\n1.3.0\n"},
+		{[]string{"1.2.0", "1.3.0-rc.1"}, "1.3.0-rc.1\n"},
+		{[]string{"1.0.5", "1.9"}, "1.1.0\nv1.2.0\n1.3.0\n"},
+		{[]string{"--include-from", "2.0.0", "2.0.0"}, "2.0.0\n"},
+		{[]string{"--include-to=false", "2.0.0", "2.0.0"}, ""},
+		{[]string{"3.0.0", "4.0.0"}, ""},
+	}
+	for _, tc := range tests {
+		args := append([]string{"span", "--available", available}, tc.args...)
+		code, stdout, stderr := runArgs(args...)
+		if code != exitOK || stdout != tc.expected {
+			t.Errorf("Expected %q to print %q but got %d, %q, and %q", args, tc.expected, code, stdout, stderr)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"span", "1.0.0", "1.3.0"},
+		{"span", "--available", available, "1.3.0", "1.0.0"},
+		{"span", "--available", available, "nope", "1.0.0"},
+		{"span", "--available", available, "1.0.0"},
+	} {
+		if code, _, stderr := runArgs(args...); code != exitInvalid || stderr == "" {
+			t.Errorf("Expected %q to exit with status 2 but got %d", args, code)
+		}
+	}
+}
@@ -1,5 +1,10 @@
 package semver
 
+import (
+	"fmt"
+	"sort"
+)
+
 // Collection is a collection of Version instances and implements the sort
 // interface. See the sort package for more details.
 // https://golang.org/pkg/sort/
@@ -22,3 +27,215 @@ func (c Collection) Less(i, j int) bool {
 func (c Collection) Swap(i, j int) {
 	c[i], c[j] = c[j], c[i]
 }
+
+// MetadataCollection is a collection of Version instances that implements the
+// sort interface in the same manner as Collection except that versions which
+// are otherwise equal are ordered by their build metadata. See
+// Version.CompareWithMetadata for details.
+type MetadataCollection []*Version
+
+// Len returns the length of a collection. The number of Version instances
+// on the slice.
+func (c MetadataCollection) Len() int {
+	return len(c)
+}
+
+// Less is needed for the sort interface to compare two Version objects on the
+// slice. It checks if one is less than the other, including the metadata.
+func (c MetadataCollection) Less(i, j int) bool {
+	return c[i].CompareWithMetadata(c[j]) < 0
+}
+
+// Swap is needed for the sort interface to replace the Version objects
+// at two different positions in the slice.
+func (c MetadataCollection) Swap(i, j int) {
+	c[i], c[j] = c[j], c[i]
+}
+
+// FilterChannel returns the versions in the release channel, see
+// Version.Channel, in the order they are in the collection. An empty name
+// returns the releases. Channel names are compared exactly so beta does not
+// match beta2. Nil versions are skipped.
+func (c Collection) FilterChannel(name string) Collection {
+	var res Collection
+	for _, v := range c {
+		if v == nil {
+			continue
+		}
+		if name == "" && v.pre != "" {
+			continue
+		}
+		if v.Channel() == name {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// BetweenOptions are the options for Collection.Between. The zero value
+// includes both ends of the window.
+type BetweenOptions struct {
+	// ExcludeLo leaves out versions equal to the low end of the window.
+	ExcludeLo bool
+
+	// ExcludeHi leaves out versions equal to the high end of the window.
+	ExcludeHi bool
+}
+
+// Between returns the versions from lo to hi, in the order they are in the
+// collection. A nil lo or hi leaves that end of the window open. Versions
+// are compared with Compare, so build metadata is ignored, and prereleases
+// are treated like any other version rather than by a PrereleasePolicy.
+// This is for callers that already have the two versions, such as the
+// current and the target of an upgrade, where constraints would need to be
+// built from them. Nil versions are skipped. The collection does not need
+// to be sorted.
+func (c Collection) Between(lo, hi *Version, opts BetweenOptions) Collection {
+	w := Window{From: lo, To: hi, IncludeFrom: !opts.ExcludeLo, IncludeTo: !opts.ExcludeHi}
+	var res Collection
+	for _, v := range c {
+		if w.Contains(v) {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// IndexOfMax returns the index of the highest version in the collection, or
+// -1 when there are no versions. When the highest version is in the
+// collection more than once the first index is returned. Nil versions are
+// skipped. The collection does not need to be sorted.
+func (c Collection) IndexOfMax() int {
+	m := -1
+	for i, v := range c {
+		if v != nil && (m < 0 || v.GreaterThan(c[m])) {
+			m = i
+		}
+	}
+	return m
+}
+
+// IndexOfMin returns the index of the lowest version in the collection, or
+// -1 when there are no versions, in the same manner as IndexOfMax.
+func (c Collection) IndexOfMin() int {
+	m := -1
+	for i, v := range c {
+		if v != nil && (m < 0 || v.LessThan(c[m])) {
+			m = i
+		}
+	}
+	return m
+}
+
+// FilterIndices returns the indexes of the versions in the collection that
+// satisfy the constraints, in order. It is the same as
+// Constraints.FilterVersions but for very large collections, and for
+// filtering one collection many times, it avoids copying the version
+// pointers into a new slice. Nil versions are skipped.
+func (c Collection) FilterIndices(cs *Constraints) []int {
+	var res []int
+	pre := cs.prereleaseGroups()
+	for i, v := range c {
+		if v != nil && cs.checkGroups(v, pre) {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+// Search returns the index of the first version in a sorted collection that
+// is not lower than v, using binary search. This is where v would be
+// inserted to keep the collection sorted, and it is the length of the
+// collection when every version is lower. Build metadata is ignored, as
+// with Version.Compare. The collection must be sorted, such as with
+// sort.Sort, and must not contain nil versions.
+func (c Collection) Search(v *Version) int {
+	return sort.Search(len(c), func(i int) bool {
+		return c[i].Compare(v) >= 0
+	})
+}
+
+// SearchConstraintBounds returns the range of a sorted collection, c[start:end],
+// between the lowest and highest versions the constraints allow, using
+// binary search. Every version that satisfies the constraints is in the
+// range, so resolvers with sorted catalogs can skip the rest, but versions
+// in the range still need to be checked as they may be excluded by a gap
+// between groups, a != constraint, the deny list, or being a prerelease.
+// When the constraints do not allow any version start and end are equal.
+// The collection must be sorted, such as with sort.Sort, and must not
+// contain nil versions.
+func (c Collection) SearchConstraintBounds(cs *Constraints) (start, end int) {
+	ivs := cs.intervalSet()
+	if len(ivs) == 0 {
+		return 0, 0
+	}
+
+	if lo := ivs[0].lo; lo != nil {
+		start = c.Search(lo)
+	}
+	end = len(c)
+	if hi := ivs[len(ivs)-1].hi; hi != nil {
+		end = start + sort.Search(len(c)-start, func(i int) bool {
+			d := c[start+i].Compare(hi)
+			return d > 0 || (d == 0 && !ivs[len(ivs)-1].hiInc)
+		})
+	}
+	return start, end
+}
+
+// Compare compares two versions in the same manner as Version.Compare. A nil
+// version is lower than any other version. It can be passed to functions
+// such as slices.SortFunc without wrapping the versions in a Collection.
+func Compare(a, b *Version) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	return a.Compare(b)
+}
+
+// CompareStrings parses two versions with NewVersion and compares them. An
+// error is returned when either of them can not be parsed.
+func CompareStrings(a, b string) (int, error) {
+	va, err := NewVersion(a)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing version %q: %w", a, err)
+	}
+	vb, err := NewVersion(b)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing version %q: %w", b, err)
+	}
+	return va.Compare(vb), nil
+}
+
+// ParseVersions parses many versions in a single call in the same manner as
+// NewVersion. The versions that parse successfully are returned in the order
+// they were passed in and an error is returned for each one that did not.
+//
+// This is intended for ingesting a large number of versions, such as those
+// from a registry. The returned versions share a single allocation and their
+// prerelease and metadata share memory with the passed in strings. Keeping
+// any one of them alive keeps all of them alive.
+func ParseVersions(vs []string) (Collection, []error) {
+	var errs []error
+	arena := make([]Version, len(vs))
+	c := make(Collection, 0, len(vs))
+	m := loadMetrics()
+	for i, v := range vs {
+		err := parseVersion(v, &arena[i])
+		if m != nil {
+			m.VersionParsed(err)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error parsing version %q: %w", v, err))
+			continue
+		}
+		c = append(c, &arena[i])
+	}
+
+	return c, errs
+}
@@ -1,7 +1,9 @@
 package semver
 
 import (
+	"errors"
 	"reflect"
+	"slices"
 	"sort"
 	"testing"
 )
@@ -44,3 +46,281 @@ func TestCollection(t *testing.T) {
 		t.Error("Sorting Collection failed")
 	}
 }
+
+func TestMetadataCollection(t *testing.T) {
+	raw := []string{
+		"1.2.3+build.2",
+		"1.0",
+		"1.2.3",
+		"1.2.3+build.1",
+		"1.2.3-beta+build.3",
+	}
+
+	vs := make([]*Version, len(raw))
+	for i, r := range raw {
+		vs[i] = MustParse(r)
+	}
+
+	sort.Sort(MetadataCollection(vs))
+
+	e := []string{
+		"1.0",
+		"1.2.3-beta+build.3",
+		"1.2.3",
+		"1.2.3+build.1",
+		"1.2.3+build.2",
+	}
+
+	a := make([]string, len(vs))
+	for i, v := range vs {
+		a[i] = v.Original()
+	}
+
+	if !reflect.DeepEqual(a, e) {
+		t.Errorf("Sorting MetadataCollection failed. Expected %v but got %v", e, a)
+	}
+}
+
+func TestParseVersions(t *testing.T) {
+	c, errs := ParseVersions([]string{"1.2.3", "foo", "v2.0-beta.1+build.5", "1.2.3.4", "0.4"})
+
+	a := make([]string, len(c))
+	for i, v := range c {
+		a[i] = v.Original()
+	}
+	e := []string{"1.2.3", "v2.0-beta.1+build.5", "0.4"}
+	if !reflect.DeepEqual(a, e) {
+		t.Errorf("Expected parsed versions %v but got %v", e, a)
+	}
+
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 errors but got %d", len(errs))
+	}
+	if !errors.Is(errs[0], ErrInvalidSemVer) {
+		t.Errorf("Expected error to wrap ErrInvalidSemVer but got %q", errs[0])
+	}
+	if s := errs[1].Error(); s != `error parsing version "1.2.3.4": Invalid Semantic Version: Version has too many segments` {
+		t.Errorf("Unexpected error message %q", s)
+	}
+
+	if c[1].Prerelease() != "beta.1" || c[1].Metadata() != "build.5" {
+		t.Errorf("Unexpected version parts for %s", c[1])
+	}
+
+	c, errs = ParseVersions(nil)
+	if len(c) != 0 || errs != nil {
+		t.Error("Expected no versions and no errors when parsing nothing")
+	}
+}
+
+func TestCompareFunc(t *testing.T) {
+	vs := []*Version{
+		MustParse("1.2.3"),
+		nil,
+		MustParse("1.0.0-beta"),
+		MustParse("0.4.2"),
+		MustParse("1.0.0"),
+	}
+	slices.SortFunc(vs, Compare)
+
+	var got []string
+	for _, v := range vs {
+		if v == nil {
+			got = append(got, "nil")
+			continue
+		}
+		got = append(got, v.String())
+	}
+	e := []string{"nil", "0.4.2", "1.0.0-beta", "1.0.0", "1.2.3"}
+	if !reflect.DeepEqual(got, e) {
+		t.Errorf("Expected %q but got %q", e, got)
+	}
+}
+
+func TestCompareStrings(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.2.3", "1.2.4", -1},
+		{"v1.2", "1.2.0", 0},
+		{"2.0.0", "2.0.0-rc.1", 1},
+		{"1.0.0+b1", "1.0.0+b2", 0},
+	}
+
+	for _, tc := range tests {
+		d, err := CompareStrings(tc.a, tc.b)
+		if err != nil {
+			t.Errorf("Unexpected error comparing %q and %q: %s", tc.a, tc.b, err)
+			continue
+		}
+		if d != tc.expected {
+			t.Errorf("Expected comparing %q and %q to be %d but got %d", tc.a, tc.b, tc.expected, d)
+		}
+	}
+
+	if _, err := CompareStrings("1.2.3", "one"); !errors.Is(err, ErrInvalidSemVer) {
+		t.Errorf("Expected %q but got %v", ErrInvalidSemVer, err)
+	}
+}
+
+func TestCollectionFilterChannel(t *testing.T) {
+	c := Collection{
+		MustParse("1.2.0-beta.1"),
+		MustParse("1.2.0"),
+		nil,
+		MustParse("1.3.0-beta2"),
+		MustParse("1.3.0-rc.1"),
+		MustParse("1.3.0-beta.3"),
+		MustParse("1.3.0-4"),
+		MustParse("1.3.0+b5"),
+	}
+
+	tests := []struct {
+		name     string
+		expected []string
+	}{
+		{"beta", []string{"1.2.0-beta.1", "1.3.0-beta.3"}},
+		{"rc", []string{"1.3.0-rc.1"}},
+		{"", []string{"1.2.0", "1.3.0+b5"}},
+		{"alpha", nil},
+	}
+
+	for _, tc := range tests {
+		var got []string
+		for _, v := range c.FilterChannel(tc.name) {
+			got = append(got, v.String())
+		}
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("Expected channel %q to have %q but got %q", tc.name, tc.expected, got)
+		}
+	}
+}
+
+func TestCollectionBetween(t *testing.T) {
+	c := Collection{
+		MustParse("1.4.0"),
+		nil,
+		MustParse("2.0.0-beta.1"),
+		MustParse("1.2.3"),
+		MustParse("2.0.0"),
+		MustParse("1.2.3+b1"),
+		MustParse("1.1.0"),
+	}
+
+	tests := []struct {
+		lo, hi   string
+		opts     BetweenOptions
+		expected []string
+	}{
+		{"1.2.3", "2.0.0", BetweenOptions{}, []string{"1.4.0", "2.0.0-beta.1", "1.2.3", "2.0.0", "1.2.3+b1"}},
+		{"1.2.3", "2.0.0", BetweenOptions{ExcludeLo: true, ExcludeHi: true}, []string{"1.4.0", "2.0.0-beta.1"}},
+		{"", "1.2.3", BetweenOptions{ExcludeHi: true}, []string{"1.1.0"}},
+		{"1.5.0", "", BetweenOptions{}, []string{"2.0.0-beta.1", "2.0.0"}},
+		{"3.0.0", "", BetweenOptions{}, nil},
+	}
+
+	for _, tc := range tests {
+		var lo, hi *Version
+		if tc.lo != "" {
+			lo = MustParse(tc.lo)
+		}
+		if tc.hi != "" {
+			hi = MustParse(tc.hi)
+		}
+		var got []string
+		for _, v := range c.Between(lo, hi, tc.opts) {
+			got = append(got, v.String())
+		}
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("Expected %q to %q with %+v to have %q but got %q", tc.lo, tc.hi, tc.opts, tc.expected, got)
+		}
+	}
+}
+
+func TestCollectionIndices(t *testing.T) {
+	c := Collection{
+		MustParse("1.4.0"),
+		nil,
+		MustParse("2.0.0-beta.1"),
+		MustParse("1.2.3"),
+		MustParse("1.9.0"),
+		MustParse("1.2.3+b1"),
+		MustParse("1.9.0+b2"),
+	}
+
+	if i := c.IndexOfMax(); i != 2 {
+		t.Errorf("Expected the max at 2 but got %d", i)
+	}
+	if i := c[3:].IndexOfMax(); i != 1 {
+		t.Errorf("Expected the first of the equal max versions at 1 but got %d", i)
+	}
+	if i := c.IndexOfMin(); i != 3 {
+		t.Errorf("Expected the min at 3 but got %d", i)
+	}
+	if i := (Collection{nil}).IndexOfMax(); i != -1 {
+		t.Errorf("Expected no max but got %d", i)
+	}
+	if i := Collection(nil).IndexOfMin(); i != -1 {
+		t.Errorf("Expected no min but got %d", i)
+	}
+
+	cs, err := NewConstraint("^1.2 !=1.4.0")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if got := c.FilterIndices(cs); !reflect.DeepEqual(got, []int{3, 4, 5, 6}) {
+		t.Errorf("Expected indices [3 4 5 6] but got %v", got)
+	}
+}
+
+func TestCollectionSearch(t *testing.T) {
+	var c Collection
+	for _, s := range []string{"1.0.0", "1.2.0-beta.1", "1.2.0", "1.2.3", "1.4.0", "2.0.0", "2.1.0", "3.0.0"} {
+		c = append(c, MustParse(s))
+	}
+
+	searches := []struct {
+		version  string
+		expected int
+	}{
+		{"0.9.0", 0},
+		{"1.2.0", 2},
+		{"1.2.0+b1", 2},
+		{"1.3.0", 4},
+		{"4.0.0", 8},
+	}
+	for _, tc := range searches {
+		if i := c.Search(MustParse(tc.version)); i != tc.expected {
+			t.Errorf("Expected %s to be searched at %d but got %d", tc.version, tc.expected, i)
+		}
+	}
+
+	bounds := []struct {
+		constraint string
+		start, end int
+	}{
+		{"^1.2", 2, 5},
+		{">1.2.3 <=2.1.0", 3, 7},
+		{"<2", 0, 5},
+		{"~1.2 || >=3", 2, 8},
+		{"*", 0, 8},
+		{"^5", 8, 8},
+		{"<1 >2", 0, 0},
+	}
+	for _, tc := range bounds {
+		cs, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		start, end := c.SearchConstraintBounds(cs)
+		if start != tc.start || end != tc.end {
+			t.Errorf("Expected %q to have bounds %d and %d but got %d and %d", tc.constraint, tc.start, tc.end, start, end)
+		}
+		for _, v := range c.FilterIndices(cs) {
+			if v < start || v >= end {
+				t.Errorf("Expected %s satisfying %q to be in the bounds", c[v], tc.constraint)
+			}
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package semver
+
+// Comparator is one constraint, such as <2, in a string of constraints along
+// with where it was written. It is returned by Constraints.Comparators.
+type Comparator struct {
+	// Group is the index of the group of AND constraints, separated by ||,
+	// that the comparator is in.
+	Group int
+
+	// Operator is the operator as written, such as >= or ~. It is empty
+	// when the version has no operator. Each side of a hyphen range, such
+	// as 1.2 - 1.4, is its own comparator with the >= and <= operators.
+	Operator string
+
+	// Version is the version as written, such as 1.x or v2.
+	Version string
+
+	// Start and End are the byte range of the comparator in the string the
+	// constraints were parsed from. For a side of a hyphen range that is
+	// the range of its version.
+	Start, End int
+
+	// Source is where the comparator came from, see
+	// Constraints.WithSource. It is empty when it was not recorded.
+	Source string
+}
+
+// Comparators returns the comparators in the constraints in the order they
+// were written. The byte ranges allow editors and linters to point at, or
+// replace, a single comparator in the original string, such as the <2 in
+// >=1.2 <2. Constraints built from others, such as by Intersection, keep
+// the byte ranges of the strings their comparators were parsed from.
+func (cs Constraints) Comparators() []Comparator {
+	var out []Comparator
+	for k, o := range cs.constraints {
+		for _, c := range o {
+			out = append(out, Comparator{
+				Group:    k,
+				Operator: c.origfunc,
+				Version:  c.orig,
+				Start:    c.start,
+				End:      c.end,
+				Source:   c.source,
+			})
+		}
+	}
+	return out
+}
@@ -0,0 +1,53 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestComparators(t *testing.T) {
+	tests := []struct {
+		constraint string
+		expected   []string
+	}{
+		{">=1.2 <2", []string{"0:>=1.2", "0:<2"}},
+		{"^1.2.3 || ~2.4", []string{"0:^1.2.3", "1:~2.4"}},
+		{" >= 1.2 , != 1.3.0 ", []string{"0:>= 1.2", "0:!= 1.3.0"}},
+		{"1.2 - 1.4.5", []string{"0:1.2", "0:1.4.5"}},
+		{"!=1.3.0 1.2 - 1.4.5, <1.4.0 || 2.x", []string{"0:!=1.3.0", "0:1.2", "0:1.4.5", "0:<1.4.0", "1:2.x"}},
+		{"1.0 - 1.1, 1.3 - 1.4", []string{"0:1.0", "0:1.1", "0:1.3", "0:1.4"}},
+		{"v1.2.3", []string{"0:v1.2.3"}},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		cmp := c.Comparators()
+		if len(cmp) != len(tc.expected) {
+			t.Fatalf("Expected %d comparators for %q but got %v", len(tc.expected), tc.constraint, cmp)
+		}
+		for i, m := range cmp {
+			got := string(rune('0'+m.Group)) + ":" + tc.constraint[m.Start:m.End]
+			if got != tc.expected[i] {
+				t.Errorf("Expected comparator %d of %q to be %q but got %q", i, tc.constraint, tc.expected[i], got)
+			}
+		}
+	}
+
+	c, _ := NewConstraint("1.2 - 1.4.5")
+	if m := c.Comparators()[1]; m.Operator != "<=" || m.Version != "1.4.5" {
+		t.Errorf("Expected the upper side of a hyphen range to be <=1.4.5 but got %+v", m)
+	}
+
+	EmptyConstraintMatchesAll = true
+	defer func() { EmptyConstraintMatchesAll = false }()
+	c, err := NewConstraint("^1 ||  ")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if m := c.Comparators()[1]; m.Start != 5 || m.End != 7 {
+		t.Errorf("Expected an empty group to span it but got %+v", m)
+	}
+}
@@ -0,0 +1,145 @@
+package semver
+
+// CompiledConstraints is a form of Constraints prepared ahead of time for
+// checking versions as quickly as possible. Checking a version against
+// CompiledConstraints does not allocate. It is useful when the same
+// constraints are checked against a very large number of versions, such as
+// when resolving dependencies.
+//
+// CompiledConstraints are created with Constraints.Compile and are safe for
+// concurrent use.
+type CompiledConstraints struct {
+	groups []compiledGroup
+
+	// deny is the deny list of the constraints.
+	deny []*Version
+
+	// source is set when prerelease versions need the same tuple policy.
+	// They are checked against the original constraints.
+	source *Constraints
+
+	// traced is set when the constraints have a Trace hook. Every version
+	// is then checked against source so the hook is called.
+	traced bool
+}
+
+// compiledGroup is an AND group of constraints.
+type compiledGroup struct {
+	// prerelease is false when the group can never be satisfied by a
+	// prerelease version. In that case prerelease versions are rejected
+	// before any of the matchers run.
+	prerelease bool
+
+	matchers []func(v *Version) bool
+}
+
+// Compile prepares the constraints for fast repeated checks. The result of
+// checking a version against the compiled form is the same as Check. When
+// the constraints have a Trace hook the compiled form calls it and is no
+// faster than Check.
+func (cs Constraints) Compile() *CompiledConstraints {
+	pre := cs.prereleaseGroups()
+	cc := &CompiledConstraints{groups: make([]compiledGroup, len(cs.checks))}
+	for k, o := range cs.checks {
+		g := compiledGroup{
+			prerelease: pre[k],
+			matchers:   make([]func(v *Version) bool, len(o)),
+		}
+		for i, c := range o {
+			g.matchers[i] = compileConstraint(c)
+		}
+		cc.groups[k] = g
+	}
+
+	cc.deny = append([]*Version(nil), cs.Deny...)
+	if cs.PrereleasePolicy == PrereleaseSameTuple || cs.Trace != nil {
+		cc.source = &cs
+		cc.traced = cs.Trace != nil
+	}
+
+	return cc
+}
+
+// Check tests if a version satisfies the constraints.
+func (cc *CompiledConstraints) Check(v *Version) bool {
+	for _, d := range cc.deny {
+		if v.Equal(d) {
+			return false
+		}
+	}
+
+	if cc.traced || (v.pre != "" && cc.source != nil) {
+		return cc.source.Check(v)
+	}
+
+	for _, g := range cc.groups {
+		if v.pre != "" && !g.prerelease {
+			continue
+		}
+
+		joy := true
+		for _, m := range g.matchers {
+			if !m(v) {
+				joy = false
+				break
+			}
+		}
+
+		if joy {
+			return true
+		}
+	}
+
+	return false
+}
+
+// compileConstraint returns a function reporting if a version meets the
+// constraint. Simple comparisons against a release version are turned into
+// integer comparisons of the major, minor, and patch versions. Everything
+// else uses the regular constraint functions.
+func compileConstraint(c *constraint) func(v *Version) bool {
+	fn := constraintOps[c.origfunc]
+	rejects := c.rejectsPrerelease()
+	generic := func(v *Version) bool {
+		if rejects && v.pre != "" {
+			return false
+		}
+		ok, _ := fn(v, c)
+		return ok
+	}
+
+	if c.dirty || c.con.pre != "" {
+		return generic
+	}
+
+	// The constraint is not looking for prereleases so, other than for !=,
+	// the group it is in has already filtered them out. Only the major,
+	// minor, and patch versions need to be compared.
+	major, minor, patch := c.con.major, c.con.minor, c.con.patch
+	core := func(v *Version) int {
+		if d := compareSegment(v.major, major); d != 0 {
+			return d
+		}
+		if d := compareSegment(v.minor, minor); d != 0 {
+			return d
+		}
+		return compareSegment(v.patch, patch)
+	}
+
+	switch c.origfunc {
+	case "", "=":
+		return func(v *Version) bool { return core(v) == 0 }
+	case "!=":
+		return func(v *Version) bool { return v.pre != "" || core(v) != 0 }
+	case ">":
+		return func(v *Version) bool { return core(v) > 0 }
+	case "<":
+		return func(v *Version) bool { return core(v) < 0 }
+	case ">=", "=>":
+		return func(v *Version) bool { return core(v) >= 0 }
+	case "<=", "=<":
+		return func(v *Version) bool { return core(v) <= 0 }
+	}
+
+	return generic
+}
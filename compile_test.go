@@ -0,0 +1,87 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestCompiledConstraintsCheck(t *testing.T) {
+	constraints := []string{
+		"*",
+		"1.2.3",
+		"=1.2.3-beta.1",
+		"!=1.2.3",
+		"!=1.2.x",
+		">1.2.3",
+		">1.2",
+		"<1.2.3",
+		"<1.x",
+		">=1.2.3-alpha",
+		"=>1.2.3",
+		"<=1.2.3",
+		"=<1.x",
+		"~1.2.3",
+		"~1",
+		"~0.0.0",
+		"^1.2.3",
+		"^0.2.3",
+		"^0.0.3",
+		"^0.x",
+		"1.2.3 - 2.0.0",
+		">=1.2.3, <2.0.0 || >=3.0.0-0",
+		"1.2.x || 2.x",
+	}
+
+	versions := []string{
+		"0.0.3",
+		"0.2.4",
+		"0.2.4-rc.1",
+		"1.0.0",
+		"1.2.2",
+		"1.2.3",
+		"1.2.3-alpha",
+		"1.2.3-beta.1",
+		"1.2.4",
+		"1.2.4-beta",
+		"1.3.0",
+		"2.0.0",
+		"2.0.0-rc.1",
+		"3.0.0-beta",
+		"3.1.0",
+	}
+
+	for _, cr := range constraints {
+		c, err := NewConstraint(cr)
+		if err != nil {
+			t.Errorf("err: %s", err)
+			continue
+		}
+
+		for _, p := range []PrereleasePolicy{PrereleaseDefault, PrereleaseSameTuple} {
+			c.PrereleasePolicy = p
+			cc := c.Compile()
+
+			for _, vr := range versions {
+				v := MustParse(vr)
+				if e, a := c.Check(v), cc.Check(v); e != a {
+					t.Errorf("Compiled constraint '%s' (policy %d) with '%s' returned %t but Check returned %t", cr, p, vr, a, e)
+				}
+			}
+		}
+	}
+}
+
+func TestCompiledConstraintsCheckAllocs(t *testing.T) {
+	c, err := NewConstraint("^1.2.3 || ~2.3.x, !=2.3.5 || >=3.0.0-0")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	cc := c.Compile()
+	v := MustParse("2.4.0")
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = cc.Check(v)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected compiled check not to allocate but got %v allocations", allocs)
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Package conformance checks how the semver package evaluates constraints
+against test vectors, such as those of node-semver, in each of its dialects,
+see semver.Dialect.
+
+A test vector is a constraint, a version, and if the version is expected to
+satisfy the constraint. Vectors are read from JSON as a list of objects or
+of three element arrays:
+
+	[
+		{"constraint": "^1.2.3", "version": "1.8.1", "expected": true},
+		[">=1.2.3-beta.1 <2", "1.2.3-beta.2", true]
+	]
+
+Running the vectors in each dialect shows which one matches the reference
+implementation they came from:
+
+	vectors, err := conformance.LoadFile("vectors.json")
+	if err != nil {
+		// Handle the file not being readable.
+	}
+	for _, r := range conformance.RunAll(vectors) {
+		fmt.Printf("%s: %d of %d match\n", r.Dialect.Name, r.Total-len(r.Mismatches), r.Total)
+	}
+*/
+package conformance
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// Vector is a test case of a constraint, a version, and if the version is
+// expected to satisfy the constraint.
+type Vector struct {
+	Constraint string `json:"constraint"`
+	Version    string `json:"version"`
+	Expected   bool   `json:"expected"`
+}
+
+// UnmarshalJSON reads a vector from an object with constraint, version, and
+// expected fields or from an array of the three in that order.
+func (v *Vector) UnmarshalJSON(b []byte) error {
+	if !bytes.HasPrefix(bytes.TrimSpace(b), []byte("[")) {
+		type vector Vector
+		return json.Unmarshal(b, (*vector)(v))
+	}
+
+	var a []json.RawMessage
+	if err := json.Unmarshal(b, &a); err != nil {
+		return err
+	}
+	if len(a) != 3 {
+		return fmt.Errorf("test vector %s does not have a constraint, version, and expected result", b)
+	}
+	if err := json.Unmarshal(a[0], &v.Constraint); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(a[1], &v.Version); err != nil {
+		return err
+	}
+	return json.Unmarshal(a[2], &v.Expected)
+}
+
+// Load reads a JSON list of test vectors.
+func Load(r io.Reader) ([]Vector, error) {
+	var vs []Vector
+	if err := json.NewDecoder(r).Decode(&vs); err != nil {
+		return nil, fmt.Errorf("error reading test vectors: %w", err)
+	}
+	return vs, nil
+}
+
+// LoadFile reads a JSON list of test vectors from a file.
+func LoadFile(path string) ([]Vector, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Load(f)
+}
+
+// Evaluate reports if the version satisfies the constraint in the dialect.
+// An error is returned when either of them can not be parsed.
+func Evaluate(d *semver.Dialect, constraint, version string) (bool, error) {
+	c, err := d.NewConstraint(constraint)
+	if err != nil {
+		return false, err
+	}
+	v, err := d.NewVersion(version)
+	if err != nil {
+		return false, err
+	}
+	return c.Check(v), nil
+}
+
+// Mismatch is a test vector whose result in a dialect is not the expected
+// one.
+type Mismatch struct {
+	Vector
+
+	// Index is the position of the vector in those that were run.
+	Index int
+
+	// Got is the result in the dialect. It is false when Err is set.
+	Got bool
+
+	// Err is set when the constraint or version can not be parsed.
+	Err error
+}
+
+func (m Mismatch) String() string {
+	if m.Err != nil {
+		return fmt.Sprintf("%d: %q with %q: expected %t but got error: %s", m.Index, m.Constraint, m.Version, m.Expected, m.Err)
+	}
+	return fmt.Sprintf("%d: %q with %q: expected %t but got %t", m.Index, m.Constraint, m.Version, m.Expected, m.Got)
+}
+
+// Report is the result of running test vectors in a dialect.
+type Report struct {
+	Dialect *semver.Dialect
+
+	// Total is the number of vectors that were run.
+	Total int
+
+	// Mismatches are the vectors that did not have the expected result, in
+	// the order they were run.
+	Mismatches []Mismatch
+}
+
+// Conforms reports if every vector had the expected result.
+func (r Report) Conforms() bool {
+	return len(r.Mismatches) == 0
+}
+
+// Run evaluates each of the vectors in the dialect. A constraint or version
+// that can not be parsed is a mismatch, even when the vector expects it not
+// to be satisfied, as it is not known how the reference would fail.
+func Run(vectors []Vector, d *semver.Dialect) Report {
+	r := Report{Dialect: d, Total: len(vectors)}
+	for i, v := range vectors {
+		got, err := Evaluate(d, v.Constraint, v.Version)
+		if err != nil || got != v.Expected {
+			r.Mismatches = append(r.Mismatches, Mismatch{Vector: v, Index: i, Got: got, Err: err})
+		}
+	}
+	return r
+}
+
+// RunAll runs the vectors in each of the registered dialects, see
+// semver.Dialects.
+func RunAll(vectors []Vector) []Report {
+	ds := semver.Dialects()
+	rs := make([]Report, len(ds))
+	for i, d := range ds {
+		rs[i] = Run(vectors, d)
+	}
+	return rs
+}
@@ -0,0 +1,78 @@
+package conformance
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+func TestLoad(t *testing.T) {
+	vs, err := Load(strings.NewReader(`[
+		{"constraint": "^1.2.3", "version": "1.8.1", "expected": true},
+		["^1.2.3", "2.0.0", false]
+	]`))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := []Vector{{"^1.2.3", "1.8.1", true}, {"^1.2.3", "2.0.0", false}}
+	if len(vs) != 2 || vs[0] != expected[0] || vs[1] != expected[1] {
+		t.Errorf("Expected %v but got %v", expected, vs)
+	}
+
+	for _, s := range []string{`[["^1.2.3", "2.0.0"]]`, `[["^1.2.3", "2.0.0", "false"]]`, `{}`} {
+		if _, err := Load(strings.NewReader(s)); err == nil {
+			t.Errorf("Expected an error reading %s", s)
+		}
+	}
+	if _, err := LoadFile("testdata/missing.json"); err == nil {
+		t.Error("Expected an error reading a missing file")
+	}
+}
+
+func TestRunNodeSemver(t *testing.T) {
+	vs, err := LoadFile("testdata/node-semver.json")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// The vectors are a hand picked subset of the range tests of
+	// node-semver, not the full suite, and the npm dialect matches them. The
+	// default prerelease policy differs on prereleases of other versions
+	// than the one in a constraint, and the strict dialect also rejects the
+	// v prefix.
+	expected := map[string][]int{
+		"default": {28, 41, 42},
+		"npm":     nil,
+		"strict":  {20, 21, 27, 28, 41, 42},
+	}
+	for _, r := range RunAll(vs) {
+		e, ok := expected[r.Dialect.Name]
+		if !ok {
+			continue
+		}
+		if r.Total != len(vs) {
+			t.Errorf("Expected %s to run %d vectors but got %d", r.Dialect.Name, len(vs), r.Total)
+		}
+		var got []int
+		for _, m := range r.Mismatches {
+			got = append(got, m.Index)
+		}
+		if len(got) != len(e) || r.Conforms() != (len(e) == 0) {
+			t.Fatalf("Expected %s to mismatch %v but got %v", r.Dialect.Name, e, r.Mismatches)
+		}
+		for i := range e {
+			if got[i] != e[i] {
+				t.Errorf("Expected %s to mismatch %v but got %v", r.Dialect.Name, e, r.Mismatches)
+				break
+			}
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	r := Run([]Vector{{">=1.x.y", "1.2.3", false}, {"1.2.3", "1.9.0", true}}, semver.DialectCargo)
+	if r.Conforms() || len(r.Mismatches) != 1 || r.Mismatches[0].Err == nil || !strings.Contains(r.Mismatches[0].String(), "error") {
+		t.Errorf("Expected an invalid constraint to be a mismatch with an error but got %v", r.Mismatches)
+	}
+}
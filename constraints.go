@@ -5,68 +5,447 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
 )
 
 // Constraints is one or more constraint that a semantic version can be
 // checked against.
 type Constraints struct {
+	// PrereleasePolicy specifies how prerelease versions are matched. The
+	// default is PrereleaseDefault.
+	PrereleasePolicy PrereleasePolicy
+
+	// Deny lists versions that never satisfy the constraints, such as those
+	// that have been retracted or yanked. Versions are compared with Equal so
+	// build metadata is ignored.
+	Deny []*Version
+
+	// Trace, when set, is called for each constraint a version is compared
+	// against by Check, CheckAll, FilterVersions, and Validate. This is
+	// useful for debug traces of why a version was or was not selected. It
+	// slows down checks and must be safe for concurrent use if the
+	// constraints are.
+	Trace func(TraceEvent)
+
 	constraints [][]*constraint
+
+	// The same groups of constraints as above with each group ordered so the
+	// cheapest and most selective constraints are checked first. This is the
+	// order used when only a pass or fail is needed.
+	checks [][]*constraint
+
+	// The rendered form of the constraints returned by String. Constraints
+	// are not changed after they are created so this is set once by
+	// newConstraints.
+	str string
 }
 
+// TraceEvent describes the comparison of a version against one constraint.
+// It is passed to Constraints.Trace.
+type TraceEvent struct {
+	// Version is the version being checked.
+	Version *Version
+
+	// Group is the index of the group of AND constraints, separated by ||,
+	// that the constraint is in.
+	Group int
+
+	// Constraint is the constraint the version is compared against, such as
+	// >=1.2.0.
+	Constraint string
+
+	// Satisfied is true when the version satisfies the constraint.
+	Satisfied bool
+
+	// Reason describes why the version does not satisfy the constraint. It
+	// is empty when it does.
+	Reason string
+}
+
+// trace reports a comparison to the Trace hook.
+func (cs Constraints) trace(v *Version, k int, c *constraint, ok bool, r constraintReason) {
+	e := TraceEvent{Version: v, Group: k, Constraint: c.string(), Satisfied: ok}
+	if !ok {
+		e.Reason = (&constraintError{v: v, orig: c.orig, reason: r}).Error()
+	}
+	cs.Trace(e)
+}
+
+// PrereleasePolicy specifies when a prerelease version can satisfy
+// constraints.
+type PrereleasePolicy int
+
+const (
+	// PrereleaseDefault only allows a prerelease version to satisfy a group
+	// of AND constraints when every constraint in the group has a prerelease.
+	// A != constraint against an exact version also allows them. For
+	// example, >=1.2.3-beta.1 allows 1.2.3-beta.2 and 1.5.0-alpha but
+	// >=1.2.3-beta.1 <2 allows neither.
+	PrereleaseDefault PrereleasePolicy = iota
+
+	// PrereleaseSameTuple matches the rule used by node-semver. A prerelease
+	// version can only satisfy a group of AND constraints when one of the
+	// constraints has a prerelease on the same major, minor, and patch
+	// version. For example, >=1.2.3-beta.1 <2 allows 1.2.3-beta.2 but not
+	// 1.5.0-alpha.
+	PrereleaseSameTuple
+)
+
+// ErrEmptyConstraint is returned when a constraint, or one of the groups
+// separated by ||, is empty or only contains whitespace. It is not returned
+// when EmptyConstraintMatchesAll is true.
+var ErrEmptyConstraint = errors.New("Constraint string empty")
+
+// EmptyConstraintMatchesAll specifies if NewConstraint treats an empty or
+// whitespace only constraint, or group separated by ||, as *. When false, the
+// default, an error wrapping ErrEmptyConstraint is returned instead. This is
+// useful when constraints come from user input where an empty value is a
+// mistake rather than a request for any version.
+var EmptyConstraintMatchesAll = false
+
+// ErrConstraintVPrefix is returned when a version in a constraint has a
+// leading v and RejectConstraintVPrefix is true.
+var ErrConstraintVPrefix = errors.New("Constraint version has a v prefix")
+
+// RejectConstraintVPrefix specifies if NewConstraint rejects a version with a
+// leading v, such as >=v1.2.3 or v1.2 - v1.4, with an error wrapping
+// ErrConstraintVPrefix. When false, the default, the v is allowed wherever a
+// version is, including after an operator and on either side of a hyphen
+// range, and the constraint is the same as one without it.
+var RejectConstraintVPrefix = false
+
 // NewConstraint returns a Constraints instance that a Version instance can
 // be checked against. If there is a parse error it will be returned.
+//
+// The constraint * matches any version that is not a prerelease. An empty
+// constraint is an error unless EmptyConstraintMatchesAll is true, in which
+// case it is the same as *.
+//
+// A version in a constraint may have a leading v, as versions passed to
+// NewVersion may. It is ignored when checking versions, so >=v1.2.3 checks
+// the same as >=1.2.3 and v1.2.3 is the same as 1.2.3 when constraints are
+// combined, such as by Intersection. The v is kept when the constraints are
+// written with String. See RejectConstraintVPrefix to reject it instead.
 func NewConstraint(c string) (*Constraints, error) {
+	cs, err := parseConstraints(c)
+	if m := loadMetrics(); m != nil {
+		m.ConstraintParsed(err)
+	}
+	return cs, err
+}
 
-	// Rewrite - ranges into a comparison operation.
-	c = rewriteRange(c)
+// parseConstraints does the work of NewConstraint.
+func parseConstraints(c string) (*Constraints, error) {
+	if err := checkLimit("MaxConstraintLength", MaxConstraintLength, len(c)); err != nil {
+		return nil, err
+	}
+	if err := checkLimit("MaxConstraintGroups", MaxConstraintGroups, strings.Count(c, "||")+1); err != nil {
+		return nil, err
+	}
+	if err := checkASCII(c); err != nil {
+		return nil, fmt.Errorf("improper constraint: %s: %w", c, err)
+	}
 
 	ors := strings.Split(c, "||")
 	or := make([][]*constraint, len(ors))
+	count := 0
+	off := 0
 	for k, v := range ors {
+		var result []*constraint
+		if strings.Trim(v, spaceChars) == "" {
+			if !EmptyConstraintMatchesAll {
+				return nil, fmt.Errorf("improper constraint: %q: %w", c, ErrEmptyConstraint)
+			}
+			result, _ = parseAndGroup("*", 0)
+			result[0].start, result[0].end = off, off+len(v)
+		} else {
+			var err error
+			result, err = parseAndGroup(v, off)
+			if err != nil {
+				return nil, err
+			}
+		}
+		off += len(v) + len("||")
 
-		// TODO: Find a way to validate and fetch all the constraints in a simpler form
+		count += len(result)
+		if err := checkLimit("MaxConstraintComparators", MaxConstraintComparators, count); err != nil {
+			return nil, err
+		}
+		or[k] = result
+	}
+
+	return newConstraints(or), nil
+}
 
-		// Validate the segment
-		if !validConstraintRegex.MatchString(v) {
-			return nil, fmt.Errorf("improper constraint: %s", v)
+// newConstraints creates Constraints from groups of parsed constraints and
+// sets up the fields derived from them.
+func newConstraints(or [][]*constraint) *Constraints {
+	o := &Constraints{
+		constraints: or,
+		checks:      make([][]*constraint, len(or)),
+	}
+	for k, v := range or {
+		c := make([]*constraint, len(v))
+		copy(c, v)
+		sort.SliceStable(c, func(i, j int) bool {
+			return c[i].checkCost() < c[j].checkCost()
+		})
+		o.checks[k] = c
+	}
+	o.str = o.render()
+	return o
+}
+
+// parseAndGroup parses a segment of AND constraints found between the ||
+// separators. Hyphen ranges (e.g., 1.2 - 1.4.5) are parsed directly into a
+// pair of >= and <= constraints. The text around the ranges is parsed as a
+// list of regular constraints. off is the byte offset of the segment in the
+// constraint string, used for the positions of the constraints.
+func parseAndGroup(g string, off int) ([]*constraint, error) {
+	var result []*constraint
+	rest := g
+	restOff := off
+	afterRange := false
+	for {
+		h := indexRangeHyphen(rest)
+		if h < 0 {
+			break
+		}
+
+		// The versions on either side of the hyphen run up to the nearest
+		// whitespace or comma.
+		start := strings.TrimRight(rest[:h], spaceChars)
+		lo := start[strings.LastIndexAny(start, spaceChars+",")+1:]
+		end := strings.TrimLeft(rest[h+1:], spaceChars)
+		hi := end
+		if i := strings.IndexAny(end, spaceChars+","); i >= 0 {
+			hi = end[:i]
 		}
 
-		cs := findConstraintRegex.FindAllString(v, -1)
-		if cs == nil {
-			cs = append(cs, v)
+		lc, err := parseRangeConstraint(">=", lo)
+		if errors.Is(err, ErrConstraintVPrefix) {
+			return nil, err
+		} else if err != nil {
+			return nil, fmt.Errorf("improper constraint: %s", g)
 		}
-		result := make([]*constraint, len(cs))
-		for i, s := range cs {
-			pc, err := parseConstraint(s)
+		hc, err := parseRangeConstraint("<=", hi)
+		if errors.Is(err, ErrConstraintVPrefix) {
+			return nil, err
+		} else if err != nil {
+			return nil, fmt.Errorf("improper constraint: %s", g)
+		}
+
+		before, ok := trimRangeSeparators(start[:len(start)-len(lo)], afterRange, true)
+		if !ok {
+			return nil, fmt.Errorf("improper constraint: %s", g)
+		}
+		if before != "" {
+			cs, err := parseConstraintList(before, restOff+strings.Index(start, before))
 			if err != nil {
 				return nil, err
 			}
+			result = append(result, cs...)
+		}
+
+		lc.start = restOff + len(start) - len(lo)
+		lc.end = lc.start + len(lo)
+		hc.start = restOff + len(rest) - len(end)
+		hc.end = hc.start + len(hi)
 
-			result[i] = pc
+		result = append(result, lc, hc)
+		restOff = hc.end
+		rest = end[len(hi):]
+		afterRange = true
+	}
+
+	if afterRange {
+		t, ok := trimRangeSeparators(rest, true, false)
+		if !ok {
+			return nil, fmt.Errorf("improper constraint: %s", g)
 		}
-		or[k] = result
+		if t == "" {
+			return result, nil
+		}
+		restOff += strings.Index(rest, t)
+		rest = t
 	}
 
-	o := &Constraints{constraints: or}
-	return o, nil
+	cs, err := parseConstraintList(rest, restOff)
+	if err != nil {
+		return nil, err
+	}
+	return append(result, cs...), nil
+}
+
+// parseConstraintList parses a list of comma or space separated constraints
+// that does not contain any hyphen ranges. off is the byte offset of the
+// list in the constraint string.
+func parseConstraintList(l string, off int) ([]*constraint, error) {
+	// TODO: Find a way to validate and fetch all the constraints in a simpler form
+
+	// Validate the segment
+	if !validConstraintRegex.MatchString(l) {
+		return nil, fmt.Errorf("improper constraint: %s", l)
+	}
+
+	idx := findConstraintRegex.FindAllStringIndex(l, -1)
+	if idx == nil {
+		idx = append(idx, []int{0, len(l)})
+	}
+	result := make([]*constraint, len(idx))
+	for i, m := range idx {
+		pc, err := parseConstraint(l[m[0]:m[1]])
+		if err != nil {
+			return nil, err
+		}
+
+		// Without an operator the match starts with the whitespace before
+		// the version.
+		m0 := m[0] + len(l[m[0]:m[1]]) - len(strings.TrimLeft(l[m[0]:m[1]], spaceChars))
+		pc.start, pc.end = off+m0, off+m[1]
+		result[i] = pc
+	}
+
+	return result, nil
 }
 
 // Check tests if a version satisfies the constraints.
 func (cs Constraints) Check(v *Version) bool {
+	if m := loadMetrics(); m != nil {
+		start := time.Now()
+		ok := cs.check(v)
+		m.Checked(time.Since(start), ok)
+		return ok
+	}
+	return cs.check(v)
+}
+
+// check does the work of Check.
+func (cs Constraints) check(v *Version) bool {
 	// TODO(mattfarina): For v4 of this library consolidate the Check and Validate
 	// functions as the underlying functions make that possible now.
+	if cs.denied(v) {
+		return false
+	}
+
 	// loop over the ORs and check the inner ANDs
-	for _, o := range cs.constraints {
-		joy := true
+	for k, o := range cs.checks {
+		if cs.groupMatches(k, o, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// groupMatches tests if a version satisfies all of the constraints in an AND
+// group.
+func (cs Constraints) groupMatches(k int, o []*constraint, v *Version) bool {
+	sameTuple := cs.PrereleasePolicy == PrereleaseSameTuple && v.pre != ""
+	if sameTuple && !hasSameTuple(o, v) {
+		return false
+	}
+
+	for _, c := range o {
+		ok, r := c.evaluate(v, !sameTuple)
+		if cs.Trace != nil {
+			cs.trace(v, k, c, ok, r)
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// denied reports if the version is in the deny list.
+func (cs Constraints) denied(v *Version) bool {
+	for _, d := range cs.Deny {
+		if v.Equal(d) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasSameTuple reports if any of the constraints has a prerelease and the
+// same major, minor, and patch versions as v.
+func hasSameTuple(o []*constraint, v *Version) bool {
+	for _, c := range o {
+		if c.sameTuple(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckAll tests each of the versions against the constraints and returns a
+// slice of results in the same order as the versions. It is equivalent to
+// calling Check for each version but work that does not depend on the version
+// is only done once. This is useful when checking a large number of versions
+// against the same constraints.
+func (cs Constraints) CheckAll(vs []*Version) []bool {
+	res := make([]bool, len(vs))
+	pre := cs.prereleaseGroups()
+	for i, v := range vs {
+		res[i] = cs.checkGroups(v, pre)
+	}
+
+	return res
+}
+
+// FilterVersions returns the versions that satisfy the constraints in the
+// order they were passed in.
+func (cs Constraints) FilterVersions(vs []*Version) []*Version {
+	var res []*Version
+	pre := cs.prereleaseGroups()
+	for _, v := range vs {
+		if cs.checkGroups(v, pre) {
+			res = append(res, v)
+		}
+	}
+
+	return res
+}
+
+// prereleaseGroups reports, for each OR group, if the group can be satisfied
+// by a prerelease version. With the default policy a group where any
+// constraint is only looking for release versions can never be satisfied by a
+// prerelease.
+func (cs Constraints) prereleaseGroups() []bool {
+	pre := make([]bool, len(cs.constraints))
+	for k, o := range cs.constraints {
+		pre[k] = true
+		if cs.PrereleasePolicy != PrereleaseDefault {
+			continue
+		}
 		for _, c := range o {
-			if check, _ := c.check(v); !check {
-				joy = false
+			if c.rejectsPrerelease() {
+				pre[k] = false
 				break
 			}
 		}
+	}
 
-		if joy {
+	return pre
+}
+
+// checkGroups is Check with the prerelease handling for each OR group decided
+// ahead of time by prereleaseGroups.
+func (cs Constraints) checkGroups(v *Version, pre []bool) bool {
+	if cs.denied(v) {
+		return false
+	}
+
+	for k, o := range cs.checks {
+		if v.pre != "" && !pre[k] {
+			continue
+		}
+
+		if cs.groupMatches(k, o, v) {
 			return true
 		}
 	}
@@ -77,29 +456,52 @@ func (cs Constraints) Check(v *Version) bool {
 // Validate checks if a version satisfies a constraint. If not a slice of
 // reasons for the failure are returned in addition to a bool.
 func (cs Constraints) Validate(v *Version) (bool, []error) {
+	if cs.denied(v) {
+		return false, []error{&constraintError{v: v, reason: reasonDenied}}
+	}
+
 	// loop over the ORs and check the inner ANDs
 	var e []error
 
 	// Capture the prerelease message only once. When it happens the first time
 	// this var is marked
 	var prerelesase bool
-	for _, o := range cs.constraints {
+	for k, o := range cs.constraints {
 		joy := true
+
+		// With the same tuple policy a prerelease version needs a constraint
+		// with a prerelease on the same major.minor.patch. When there is one
+		// the constraints only compare the versions.
+		sameTuple := cs.PrereleasePolicy == PrereleaseSameTuple && v.pre != ""
+		if sameTuple && !hasSameTuple(o, v) {
+			if !prerelesase {
+				e = append(e, &constraintError{v: v, reason: reasonPrereleaseTuple})
+				prerelesase = true
+			}
+			joy = false
+		}
+
 		for _, c := range o {
 			// Before running the check handle the case there the version is
 			// a prerelease and the check is not searching for prereleases.
-			if c.con.pre == "" && v.pre != "" {
+			if !sameTuple && c.con.pre == "" && v.pre != "" {
+				if cs.Trace != nil {
+					cs.trace(v, k, c, false, reasonPrerelease)
+				}
 				if !prerelesase {
-					em := fmt.Errorf("%s is a prerelease version and the constraint is only looking for release versions", v)
-					e = append(e, em)
+					e = append(e, &constraintError{v: v, reason: reasonPrerelease})
 					prerelesase = true
 				}
 				joy = false
 
 			} else {
 
-				if _, err := c.check(v); err != nil {
-					e = append(e, err)
+				ok, r := c.evaluate(v, !sameTuple)
+				if cs.Trace != nil {
+					cs.trace(v, k, c, ok, r)
+				}
+				if !ok {
+					e = append(e, &constraintError{v: v, orig: c.orig, reason: r})
 					joy = false
 				}
 			}
@@ -113,11 +515,86 @@ func (cs Constraints) Validate(v *Version) (bool, []error) {
 	return false, e
 }
 
+// ValidateFirst checks if a version satisfies the constraints like Validate
+// but returns only the first reason for a failure, which ReasonCode
+// identifies. Each group of AND constraints is only checked up to its first
+// failing constraint, and the error is for the first group that failed in
+// the order they were written. This is for hot paths that need more than
+// Check but can not afford building every reason. The error is nil when
+// the version satisfies the constraints.
+//
+// The result is always the same as that of Validate, not Check. They differ
+// for a prerelease and an exact != constraint: 1.2.4-beta satisfies !=1.2.3
+// with Check, and so with Explain and Compile, but not with Validate.
+func (cs Constraints) ValidateFirst(v *Version) (bool, error) {
+	if cs.denied(v) {
+		return false, &constraintError{v: v, reason: reasonDenied}
+	}
+
+	var first *constraintError
+	for k, o := range cs.constraints {
+		sameTuple := cs.PrereleasePolicy == PrereleaseSameTuple && v.pre != ""
+		var fail *constraint
+		r := reasonNone
+		if sameTuple && !hasSameTuple(o, v) {
+			r = reasonPrereleaseTuple
+		} else {
+			for _, c := range o {
+				var ok bool
+				if !sameTuple && c.con.pre == "" && v.pre != "" {
+					ok, r = false, reasonPrerelease
+				} else {
+					ok, r = c.evaluate(v, !sameTuple)
+				}
+				if cs.Trace != nil {
+					cs.trace(v, k, c, ok, r)
+				}
+				if !ok {
+					fail = c
+					break
+				}
+			}
+		}
+
+		if fail == nil && r != reasonPrereleaseTuple {
+			return true, nil
+		}
+		if first == nil {
+			first = &constraintError{v: v, reason: r}
+			if fail != nil && r != reasonPrerelease {
+				first.orig = fail.orig
+			}
+		}
+	}
+
+	if first == nil {
+		return false, nil
+	}
+	return false, first
+}
+
+// String converts the constraints into a string. Parsing the string with
+// NewConstraint produces constraints equivalent to these ones, including for
+// hyphen ranges, wildcards, and prereleases. See VerifyRoundTrip.
+//
+// The OR groups are written in a stable order that does not depend on the
+// order they were parsed in. Groups are ordered by comparing their
+// constraints in turn, first by version, then by operation, and then by the
+// text of the constraint. A group that is a prefix of another comes first.
+// The constraints within a group keep the order they were parsed in.
 func (cs Constraints) String() string {
+	if cs.str != "" {
+		return cs.str
+	}
+	return cs.render()
+}
+
+// render builds the string form of the constraints.
+func (cs Constraints) render() string {
 	buf := make([]string, len(cs.constraints))
 	var tmp bytes.Buffer
 
-	for k, v := range cs.constraints {
+	for k, v := range cs.sortedGroups() {
 		tmp.Reset()
 		vlen := len(v)
 		for kk, c := range v {
@@ -134,6 +611,60 @@ func (cs Constraints) String() string {
 	return strings.Join(buf, " || ")
 }
 
+// sortedGroups returns the OR groups in the order they are written by String.
+func (cs Constraints) sortedGroups() [][]*constraint {
+	g := make([][]*constraint, len(cs.constraints))
+	copy(g, cs.constraints)
+	sort.SliceStable(g, func(i, j int) bool {
+		return compareGroups(g[i], g[j]) < 0
+	})
+	return g
+}
+
+// compareGroups orders two AND groups by their constraints in turn.
+func compareGroups(a, b []*constraint) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if d := a[i].compare(b[i]); d != 0 {
+			return d
+		}
+	}
+	return compareSegment(uint64(len(a)), uint64(len(b)))
+}
+
+// VerifyRoundTrip checks that parsing the string form of the constraints
+// with NewConstraint produces equivalent constraints. The same groups of
+// comparisons need to be found, in the order String writes them, and each
+// comparison needs
+// to have the same operation, version, and wildcards. An error describing the
+// first difference is returned if they are not equivalent.
+//
+// This is intended for tests, such as fuzz tests, that store or transmit
+// constraints as strings.
+func VerifyRoundTrip(c *Constraints) error {
+	s := c.String()
+	rt, err := NewConstraint(s)
+	if err != nil {
+		return fmt.Errorf("constraint string %q does not parse: %w", s, err)
+	}
+
+	cg, rg := c.sortedGroups(), rt.sortedGroups()
+	if len(rg) != len(cg) {
+		return fmt.Errorf("constraint string %q has %d groups instead of %d", s, len(rg), len(cg))
+	}
+	for k, o := range cg {
+		if len(rg[k]) != len(o) {
+			return fmt.Errorf("constraint string %q has %d comparisons in group %d instead of %d", s, len(rg[k]), k, len(o))
+		}
+		for i, cc := range o {
+			if !cc.equivalent(rg[k][i]) {
+				return fmt.Errorf("constraint string %q parses %q as %q", s, cc.string(), rg[k][i].string())
+			}
+		}
+	}
+
+	return nil
+}
+
 // UnmarshalText implements the encoding.TextUnmarshaler interface.
 func (cs *Constraints) UnmarshalText(text []byte) error {
 	temp, err := NewConstraint(string(text))
@@ -151,9 +682,34 @@ func (cs Constraints) MarshalText() ([]byte, error) {
 	return []byte(cs.String()), nil
 }
 
+// Set implements the flag.Value interface so constraints can be passed as a
+// command line flag and are parsed when the flags are:
+//
+//	c := &semver.Constraints{}
+//	flag.Var(c, "range", "versions to allow")
+//
+// The prerelease policy and deny list are kept. Along with MarshalText and
+// UnmarshalText, flag.TextVar and configuration and environment variable
+// libraries that use them can also parse constraints.
+func (cs *Constraints) Set(s string) error {
+	temp, err := NewConstraint(s)
+	if err != nil {
+		return err
+	}
+
+	temp.PrereleasePolicy = cs.PrereleasePolicy
+	temp.Deny = cs.Deny
+	temp.Trace = cs.Trace
+	*cs = *temp
+
+	return nil
+}
+
 var constraintOps map[string]cfunc
 var constraintRegex *regexp.Regexp
-var constraintRangeRegex *regexp.Regexp
+
+// Used to parse the versions on either side of a hyphen range
+var constraintVersionRegex *regexp.Regexp
 
 // Used to find individual constraints within a multi-constraint string
 var findConstraintRegex *regexp.Regexp
@@ -188,9 +744,9 @@ func init() {
 		ops,
 		cvRegex))
 
-	constraintRangeRegex = regexp.MustCompile(fmt.Sprintf(
-		`\s*(%s)\s+-\s+(%s)\s*`,
-		cvRegex, cvRegex))
+	constraintVersionRegex = regexp.MustCompile(fmt.Sprintf(
+		`^(%s)$`,
+		cvRegex))
 
 	findConstraintRegex = regexp.MustCompile(fmt.Sprintf(
 		`(%s)\s*(%s)`,
@@ -224,63 +780,271 @@ type constraint struct {
 	minorDirty bool
 	dirty      bool
 	patchDirty bool
+
+	// The byte range of the constraint in the string it was parsed from.
+	start, end int
+
+	// Where the constraint came from, such as a file or the package that
+	// requires it. See Constraints.WithSource.
+	source string
 }
 
 // Check if a version meets the constraint
 func (c *constraint) check(v *Version) (bool, error) {
+	if ok, r := c.evaluate(v, true); !ok {
+		return false, &constraintError{v: v, orig: c.orig, reason: r}
+	}
+	return true, nil
+}
+
+// matches is check without the error describing why the version failed.
+func (c *constraint) matches(v *Version) bool {
+	ok, _ := c.evaluate(v, true)
+	return ok
+}
+
+// evaluate checks the version against the constraint. When rejectPrerelease
+// is true a prerelease version fails a constraint that is not looking for
+// prereleases. Otherwise the version is only compared to the constraint.
+func (c *constraint) evaluate(v *Version, rejectPrerelease bool) (bool, constraintReason) {
+	// If there is a pre-release on the version but the constraint isn't looking
+	// for them assume that pre-releases are not compatible. See issue 21 for
+	// more details.
+	if rejectPrerelease && v.pre != "" && c.rejectsPrerelease() {
+		return false, reasonPrerelease
+	}
+
 	return constraintOps[c.origfunc](v, c)
 }
 
+// sameTuple reports if the constraint has a prerelease and the same major,
+// minor, and patch versions as v.
+func (c *constraint) sameTuple(v *Version) bool {
+	return c.con.pre != "" &&
+		c.con.major == v.major &&
+		c.con.minor == v.minor &&
+		c.con.patch == v.patch
+}
+
+// rejectsPrerelease reports if the constraint fails every prerelease version
+// because the constraint is not looking for prereleases. A != against an exact
+// version is the one case that still accepts them.
+func (c *constraint) rejectsPrerelease() bool {
+	if c.con.pre != "" {
+		return false
+	}
+	return c.origfunc != "!=" || c.dirty
+}
+
+// equivalent reports if two constraints check versions the same way.
+func (c *constraint) equivalent(o *constraint) bool {
+	return canonicalOp(c.origfunc) == canonicalOp(o.origfunc) &&
+		c.con.Equal(o.con) &&
+		c.con.pre == o.con.pre &&
+		c.dirty == o.dirty &&
+		c.minorDirty == o.minorDirty &&
+		c.patchDirty == o.patchDirty
+}
+
+// canonicalOp returns the operator the op is an alias of, or op itself when
+// it is not an alias.
+func canonicalOp(op string) string {
+	switch op {
+	case "":
+		return "="
+	case "=>":
+		return ">="
+	case "=<":
+		return "<="
+	case "~>":
+		return "~"
+	}
+	return op
+}
+
+// checkCost ranks how early the constraint should be checked within an AND
+// group. Lower costs are checked first. Exact versions are the most selective
+// and the cheapest to check, followed by plain comparisons, the range
+// operators, and wildcards. != rarely fails so it is checked last.
+func (c *constraint) checkCost() int {
+	switch {
+	case c.origfunc == "!=":
+		return 4
+	case c.dirty:
+		return 3
+	case c.origfunc == "^" || c.origfunc == "~" || c.origfunc == "~>":
+		return 2
+	case c.origfunc == "" || c.origfunc == "=":
+		return 0
+	default:
+		return 1
+	}
+}
+
 // String prints an individual constraint into a string
 func (c *constraint) string() string {
 	return c.origfunc + c.orig
 }
 
-type cfunc func(v *Version, c *constraint) (bool, error)
-
-func parseConstraint(c string) (*constraint, error) {
-	if len(c) > 0 {
-		m := constraintRegex.FindStringSubmatch(c)
-		if m == nil {
-			return nil, fmt.Errorf("improper constraint: %s", c)
+// WithSource returns a copy of the constraints with the source recorded on
+// each of their constraints. The source describes where the constraints
+// came from, such as a manifest file or the package that requires them. It
+// is kept by the constraints built from them, such as by Intersection and
+// Union, and shows up in conflicts and explanations, so a failed resolution
+// can report "upper bound <2.0.0 from serviceB excludes lower bound >=2.0.0 (^2)
+// from serviceA" rather than only the constraints.
+func (cs Constraints) WithSource(source string) *Constraints {
+	or := make([][]*constraint, len(cs.constraints))
+	for k, o := range cs.constraints {
+		g := make([]*constraint, len(o))
+		for i, c := range o {
+			cc := *c
+			cc.source = source
+			g[i] = &cc
 		}
+		or[k] = g
+	}
 
-		cs := &constraint{
-			orig:     m[2],
-			origfunc: m[1],
-		}
+	res := newConstraints(or)
+	res.PrereleasePolicy = cs.PrereleasePolicy
+	res.Deny = cs.Deny
+	res.Trace = cs.Trace
+	return res
+}
 
-		ver := m[2]
-		minorDirty := false
-		patchDirty := false
-		dirty := false
-		if isX(m[3]) || m[3] == "" {
-			ver = fmt.Sprintf("0.0.0%s", m[6])
-			dirty = true
-		} else if isX(strings.TrimPrefix(m[4], ".")) || m[4] == "" {
-			minorDirty = true
-			dirty = true
-			ver = fmt.Sprintf("%s.0.0%s", m[3], m[6])
-		} else if isX(strings.TrimPrefix(m[5], ".")) || m[5] == "" {
-			dirty = true
-			patchDirty = true
-			ver = fmt.Sprintf("%s%s.0%s", m[3], m[4], m[6])
+// Sources returns the sources recorded on the constraints with WithSource,
+// in the order their constraints are written, with each source once.
+func (cs Constraints) Sources() []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, o := range cs.constraints {
+		for _, c := range o {
+			if c.source != "" && !seen[c.source] {
+				seen[c.source] = true
+				out = append(out, c.source)
+			}
 		}
+	}
+	return out
+}
 
-		con, err := NewVersion(ver)
-		if err != nil {
+// key identifies the constraint by how it is written, ignoring aliases of the
+// operator and a leading v on the version. Constraints with the same key are
+// the same constraint.
+func (c *constraint) key() string {
+	return canonicalOp(c.origfunc) + strings.TrimPrefix(c.orig, "v")
+}
 
-			// The constraintRegex should catch any regex parsing errors. So,
-			// we should never get here.
-			return nil, errors.New("constraint Parser Error")
-		}
+// compare orders two constraints by version, then operation, and then text.
+func (c *constraint) compare(o *constraint) int {
+	if d := c.con.Compare(o.con); d != 0 {
+		return d
+	}
+	if d := strings.Compare(canonicalOp(c.origfunc), canonicalOp(o.origfunc)); d != 0 {
+		return d
+	}
+	return strings.Compare(c.string(), o.string())
+}
+
+// constraintReason identifies why a version failed an individual constraint.
+type constraintReason uint8
+
+const (
+	reasonNone constraintReason = iota
+	reasonPrerelease
+	reasonEqual
+	reasonNotGreater
+	reasonNotLess
+	reasonLess
+	reasonGreater
+	reasonMajor
+	reasonMajorMinor
+	reasonNotEqual
+	reasonMinorZeroMajor
+	reasonMinor
+	reasonPatchZeroMinor
+	reasonPrereleaseTuple
+	reasonDenied
+)
+
+var constraintReasonFormats = [...]string{
+	reasonNone:            "",
+	reasonPrerelease:      "%s is a prerelease version and the constraint is only looking for release versions",
+	reasonEqual:           "%s is equal to %s",
+	reasonNotGreater:      "%s is less than or equal to %s",
+	reasonNotLess:         "%s is greater than or equal to %s",
+	reasonLess:            "%s is less than %s",
+	reasonGreater:         "%s is greater than %s",
+	reasonMajor:           "%s does not have same major version as %s",
+	reasonMajorMinor:      "%s does not have same major and minor version as %s",
+	reasonNotEqual:        "%s is not equal to %s",
+	reasonMinorZeroMajor:  "%s does not have same minor version as %s. Expected minor versions to match when constraint major version is 0",
+	reasonMinor:           "%s does not have same minor version as %s",
+	reasonPrereleaseTuple: "%s is a prerelease version and no constraint has a prerelease on the same major, minor, and patch version",
+	reasonDenied:          "%s is denied",
+	reasonPatchZeroMinor:  "%s does not equal %s. Expect version and constraint to equal when major and minor versions are 0",
+}
 
-		cs.con = con
-		cs.minorDirty = minorDirty
-		cs.patchDirty = patchDirty
-		cs.dirty = dirty
+// constraintReasonCodes are stable identifiers for the reasons, used in the
+// JSON form of an Explanation and by ReasonCode. Unlike the messages they
+// are not changed once released.
+var constraintReasonCodes = [...]string{
+	reasonNone:            "",
+	reasonPrerelease:      "prerelease",
+	reasonEqual:           "equal",
+	reasonNotGreater:      "not_greater",
+	reasonNotLess:         "not_less",
+	reasonLess:            "less",
+	reasonGreater:         "greater",
+	reasonMajor:           "major_mismatch",
+	reasonMajorMinor:      "major_minor_mismatch",
+	reasonNotEqual:        "not_equal",
+	reasonMinorZeroMajor:  "minor_mismatch_zero_major",
+	reasonMinor:           "minor_mismatch",
+	reasonPrereleaseTuple: "prerelease_tuple",
+	reasonDenied:          "denied",
+	reasonPatchZeroMinor:  "patch_mismatch_zero_minor",
+}
 
-		return cs, nil
+// ReasonCode returns a stable identifier for why a version failed a
+// constraint, such as "less" or "prerelease", for an error returned by
+// Validate or held in an Explanation. The codes do not change between
+// releases so they can be used by programs, unlike the error messages. An
+// empty string is returned for other errors.
+func ReasonCode(err error) string {
+	var ce *constraintError
+	if errors.As(err, &ce) {
+		return constraintReasonCodes[ce.reason]
+	}
+	return ""
+}
+
+// constraintError is the error returned when a version fails an individual
+// constraint. Formatting the message is deferred until Error is called so
+// callers only interested in whether a check passed don't pay for it.
+type constraintError struct {
+	v      *Version
+	orig   string
+	reason constraintReason
+}
+
+func (e *constraintError) Error() string {
+	if e.reason == reasonPrerelease || e.reason == reasonPrereleaseTuple || e.reason == reasonDenied {
+		return fmt.Sprintf(constraintReasonFormats[e.reason], e.v)
+	}
+	return fmt.Sprintf(constraintReasonFormats[e.reason], e.v, e.orig)
+}
+
+type cfunc func(v *Version, c *constraint) (bool, constraintReason)
+
+func parseConstraint(c string) (*constraint, error) {
+	if len(c) > 0 {
+		m := constraintRegex.FindStringSubmatch(c)
+		if m == nil {
+			return nil, fmt.Errorf("improper constraint: %s", c)
+		}
+
+		return newConstraint(m[1], m[2:])
 	}
 
 	// The rest is the special case where an empty string was passed in which
@@ -304,149 +1068,181 @@ func parseConstraint(c string) (*constraint, error) {
 	return cs, nil
 }
 
-// Constraint functions
-func constraintNotEqual(v *Version, c *constraint) (bool, error) {
-	if c.dirty {
+// parseRangeConstraint parses one side of a hyphen range as a constraint
+// with the operator op.
+func parseRangeConstraint(op, ver string) (*constraint, error) {
+	m := constraintVersionRegex.FindStringSubmatch(ver)
+	if m == nil {
+		return nil, fmt.Errorf("improper constraint: %s", ver)
+	}
+
+	return newConstraint(op, m[1:])
+}
+
+// newConstraint creates a constraint from an operator and the submatches of
+// cvRegex for the version, where m[0] is the entire version, m[1], m[2], and
+// m[3] are the major, minor, and patch parts, and m[4] is the prerelease.
+func newConstraint(op string, m []string) (*constraint, error) {
+	if RejectConstraintVPrefix && strings.HasPrefix(m[0], "v") {
+		return nil, fmt.Errorf("improper constraint: %s%s: %w", op, m[0], ErrConstraintVPrefix)
+	}
+
+	cs := &constraint{
+		orig:     m[0],
+		origfunc: op,
+	}
+
+	ver := m[0]
+	minorDirty := false
+	patchDirty := false
+	dirty := false
+	if isX(m[1]) || m[1] == "" {
+		ver = fmt.Sprintf("0.0.0%s", m[4])
+		dirty = true
+	} else if isX(strings.TrimPrefix(m[2], ".")) || m[2] == "" {
+		minorDirty = true
+		dirty = true
+		ver = fmt.Sprintf("%s.0.0%s", m[1], m[4])
+	} else if isX(strings.TrimPrefix(m[3], ".")) || m[3] == "" {
+		dirty = true
+		patchDirty = true
+		ver = fmt.Sprintf("%s%s.0%s", m[1], m[2], m[4])
+	}
 
-		// If there is a pre-release on the version but the constraint isn't looking
-		// for them assume that pre-releases are not compatible. See issue 21 for
-		// more details.
-		if v.Prerelease() != "" && c.con.Prerelease() == "" {
-			return false, fmt.Errorf("%s is a prerelease version and the constraint is only looking for release versions", v)
+	con, err := NewVersion(ver)
+	if err != nil {
+		// The version in a constraint is subject to MaxVersionLength.
+		var le *LimitError
+		if errors.As(err, &le) {
+			return nil, err
 		}
 
+		// The constraintRegex should catch any regex parsing errors. So,
+		// we should never get here.
+		return nil, errors.New("constraint Parser Error")
+	}
+
+	cs.con = con
+	cs.minorDirty = minorDirty
+	cs.patchDirty = patchDirty
+	cs.dirty = dirty
+
+	return cs, nil
+}
+
+// Constraint functions
+func constraintNotEqual(v *Version, c *constraint) (bool, constraintReason) {
+	if c.dirty {
+
 		if c.con.Major() != v.Major() {
-			return true, nil
+			return true, reasonNone
 		}
 		if c.con.Minor() != v.Minor() && !c.minorDirty {
-			return true, nil
+			return true, reasonNone
 		} else if c.minorDirty {
-			return false, fmt.Errorf("%s is equal to %s", v, c.orig)
+			return false, reasonEqual
 		} else if c.con.Patch() != v.Patch() && !c.patchDirty {
-			return true, nil
+			return true, reasonNone
 		} else if c.patchDirty {
 			// Need to handle prereleases if present
 			if v.Prerelease() != "" || c.con.Prerelease() != "" {
 				eq := comparePrerelease(v.Prerelease(), c.con.Prerelease()) != 0
 				if eq {
-					return true, nil
+					return true, reasonNone
 				}
-				return false, fmt.Errorf("%s is equal to %s", v, c.orig)
+				return false, reasonEqual
 			}
-			return false, fmt.Errorf("%s is equal to %s", v, c.orig)
+			return false, reasonEqual
 		}
 	}
 
 	eq := v.Equal(c.con)
 	if eq {
-		return false, fmt.Errorf("%s is equal to %s", v, c.orig)
+		return false, reasonEqual
 	}
 
-	return true, nil
+	return true, reasonNone
 }
 
-func constraintGreaterThan(v *Version, c *constraint) (bool, error) {
-
-	// If there is a pre-release on the version but the constraint isn't looking
-	// for them assume that pre-releases are not compatible. See issue 21 for
-	// more details.
-	if v.Prerelease() != "" && c.con.Prerelease() == "" {
-		return false, fmt.Errorf("%s is a prerelease version and the constraint is only looking for release versions", v)
-	}
+func constraintGreaterThan(v *Version, c *constraint) (bool, constraintReason) {
 
 	var eq bool
 
 	if !c.dirty {
 		eq = v.Compare(c.con) == 1
 		if eq {
-			return true, nil
+			return true, reasonNone
 		}
-		return false, fmt.Errorf("%s is less than or equal to %s", v, c.orig)
+		return false, reasonNotGreater
 	}
 
 	if v.Major() > c.con.Major() {
-		return true, nil
+		return true, reasonNone
 	} else if v.Major() < c.con.Major() {
-		return false, fmt.Errorf("%s is less than or equal to %s", v, c.orig)
+		return false, reasonNotGreater
 	} else if c.minorDirty {
 		// This is a range case such as >11. When the version is something like
 		// 11.1.0 is it not > 11. For that we would need 12 or higher
-		return false, fmt.Errorf("%s is less than or equal to %s", v, c.orig)
+		return false, reasonNotGreater
 	} else if c.patchDirty {
 		// This is for ranges such as >11.1. A version of 11.1.1 is not greater
 		// which one of 11.2.1 is greater
 		eq = v.Minor() > c.con.Minor()
 		if eq {
-			return true, nil
+			return true, reasonNone
 		}
-		return false, fmt.Errorf("%s is less than or equal to %s", v, c.orig)
+		return false, reasonNotGreater
 	}
 
 	// If we have gotten here we are not comparing pre-preleases and can use the
 	// Compare function to accomplish that.
 	eq = v.Compare(c.con) == 1
 	if eq {
-		return true, nil
+		return true, reasonNone
 	}
-	return false, fmt.Errorf("%s is less than or equal to %s", v, c.orig)
+	return false, reasonNotGreater
 }
 
-func constraintLessThan(v *Version, c *constraint) (bool, error) {
-	// If there is a pre-release on the version but the constraint isn't looking
-	// for them assume that pre-releases are not compatible. See issue 21 for
-	// more details.
-	if v.Prerelease() != "" && c.con.Prerelease() == "" {
-		return false, fmt.Errorf("%s is a prerelease version and the constraint is only looking for release versions", v)
-	}
-
+func constraintLessThan(v *Version, c *constraint) (bool, constraintReason) {
 	eq := v.Compare(c.con) < 0
 	if eq {
-		return true, nil
+		return true, reasonNone
 	}
-	return false, fmt.Errorf("%s is greater than or equal to %s", v, c.orig)
+	return false, reasonNotLess
 }
 
-func constraintGreaterThanEqual(v *Version, c *constraint) (bool, error) {
-
-	// If there is a pre-release on the version but the constraint isn't looking
-	// for them assume that pre-releases are not compatible. See issue 21 for
-	// more details.
-	if v.Prerelease() != "" && c.con.Prerelease() == "" {
-		return false, fmt.Errorf("%s is a prerelease version and the constraint is only looking for release versions", v)
-	}
+func constraintGreaterThanEqual(v *Version, c *constraint) (bool, constraintReason) {
 
 	eq := v.Compare(c.con) >= 0
 	if eq {
-		return true, nil
+		return true, reasonNone
 	}
-	return false, fmt.Errorf("%s is less than %s", v, c.orig)
+	return false, reasonLess
 }
 
-func constraintLessThanEqual(v *Version, c *constraint) (bool, error) {
-	// If there is a pre-release on the version but the constraint isn't looking
-	// for them assume that pre-releases are not compatible. See issue 21 for
-	// more details.
-	if v.Prerelease() != "" && c.con.Prerelease() == "" {
-		return false, fmt.Errorf("%s is a prerelease version and the constraint is only looking for release versions", v)
-	}
-
+func constraintLessThanEqual(v *Version, c *constraint) (bool, constraintReason) {
 	var eq bool
 
 	if !c.dirty {
 		eq = v.Compare(c.con) <= 0
 		if eq {
-			return true, nil
+			return true, reasonNone
 		}
-		return false, fmt.Errorf("%s is greater than %s", v, c.orig)
+		return false, reasonGreater
+	}
+
+	// <=* allows any version.
+	if !c.minorDirty && !c.patchDirty {
+		return true, reasonNone
 	}
 
 	if v.Major() > c.con.Major() {
-		return false, fmt.Errorf("%s is greater than %s", v, c.orig)
+		return false, reasonGreater
 	} else if v.Major() == c.con.Major() && v.Minor() > c.con.Minor() && !c.minorDirty {
-		return false, fmt.Errorf("%s is greater than %s", v, c.orig)
+		return false, reasonGreater
 	}
 
-	return true, nil
+	return true, reasonNone
 }
 
 // ~*, ~>* --> >= 0.0.0 (any)
@@ -455,56 +1251,42 @@ func constraintLessThanEqual(v *Version, c *constraint) (bool, error) {
 // ~1.2, ~1.2.x, ~>1.2, ~>1.2.x --> >=1.2.0, <1.3.0
 // ~1.2.3, ~>1.2.3 --> >=1.2.3, <1.3.0
 // ~1.2.0, ~>1.2.0 --> >=1.2.0, <1.3.0
-func constraintTilde(v *Version, c *constraint) (bool, error) {
-	// If there is a pre-release on the version but the constraint isn't looking
-	// for them assume that pre-releases are not compatible. See issue 21 for
-	// more details.
-	if v.Prerelease() != "" && c.con.Prerelease() == "" {
-		return false, fmt.Errorf("%s is a prerelease version and the constraint is only looking for release versions", v)
-	}
-
+func constraintTilde(v *Version, c *constraint) (bool, constraintReason) {
 	if v.LessThan(c.con) {
-		return false, fmt.Errorf("%s is less than %s", v, c.orig)
+		return false, reasonLess
 	}
 
 	// ~0.0.0 is a special case where all constraints are accepted. It's
 	// equivalent to >= 0.0.0.
 	if c.con.Major() == 0 && c.con.Minor() == 0 && c.con.Patch() == 0 &&
 		!c.minorDirty && !c.patchDirty {
-		return true, nil
+		return true, reasonNone
 	}
 
 	if v.Major() != c.con.Major() {
-		return false, fmt.Errorf("%s does not have same major version as %s", v, c.orig)
+		return false, reasonMajor
 	}
 
 	if v.Minor() != c.con.Minor() && !c.minorDirty {
-		return false, fmt.Errorf("%s does not have same major and minor version as %s", v, c.orig)
+		return false, reasonMajorMinor
 	}
 
-	return true, nil
+	return true, reasonNone
 }
 
 // When there is a .x (dirty) status it automatically opts in to ~. Otherwise
 // it's a straight =
-func constraintTildeOrEqual(v *Version, c *constraint) (bool, error) {
-	// If there is a pre-release on the version but the constraint isn't looking
-	// for them assume that pre-releases are not compatible. See issue 21 for
-	// more details.
-	if v.Prerelease() != "" && c.con.Prerelease() == "" {
-		return false, fmt.Errorf("%s is a prerelease version and the constraint is only looking for release versions", v)
-	}
-
+func constraintTildeOrEqual(v *Version, c *constraint) (bool, constraintReason) {
 	if c.dirty {
 		return constraintTilde(v, c)
 	}
 
 	eq := v.Equal(c.con)
 	if eq {
-		return true, nil
+		return true, reasonNone
 	}
 
-	return false, fmt.Errorf("%s is not equal to %s", v, c.orig)
+	return false, reasonNotEqual
 }
 
 // ^*      -->  (any)
@@ -516,17 +1298,10 @@ func constraintTildeOrEqual(v *Version, c *constraint) (bool, error) {
 // ^0.0.3  -->  >=0.0.3 <0.0.4
 // ^0.0    -->  >=0.0.0 <0.1.0
 // ^0      -->  >=0.0.0 <1.0.0
-func constraintCaret(v *Version, c *constraint) (bool, error) {
-	// If there is a pre-release on the version but the constraint isn't looking
-	// for them assume that pre-releases are not compatible. See issue 21 for
-	// more details.
-	if v.Prerelease() != "" && c.con.Prerelease() == "" {
-		return false, fmt.Errorf("%s is a prerelease version and the constraint is only looking for release versions", v)
-	}
-
+func constraintCaret(v *Version, c *constraint) (bool, constraintReason) {
 	// This less than handles prereleases
 	if v.LessThan(c.con) {
-		return false, fmt.Errorf("%s is less than %s", v, c.orig)
+		return false, reasonLess
 	}
 
 	var eq bool
@@ -539,35 +1314,35 @@ func constraintCaret(v *Version, c *constraint) (bool, error) {
 		// that greater but not within the same major range.
 		eq = v.Major() == c.con.Major()
 		if eq {
-			return true, nil
+			return true, reasonNone
 		}
-		return false, fmt.Errorf("%s does not have same major version as %s", v, c.orig)
+		return false, reasonMajor
 	}
 
 	// ^ when the major is 0 and minor > 0 is >=0.y.z < 0.y+1
 	if c.con.Major() == 0 && v.Major() > 0 {
-		return false, fmt.Errorf("%s does not have same major version as %s", v, c.orig)
+		return false, reasonMajor
 	}
 	// If the con Minor is > 0 it is not dirty
 	if c.con.Minor() > 0 || c.patchDirty {
 		eq = v.Minor() == c.con.Minor()
 		if eq {
-			return true, nil
+			return true, reasonNone
 		}
-		return false, fmt.Errorf("%s does not have same minor version as %s. Expected minor versions to match when constraint major version is 0", v, c.orig)
+		return false, reasonMinorZeroMajor
 	}
 	// ^ when the minor is 0 and minor > 0 is =0.0.z
 	if c.con.Minor() == 0 && v.Minor() > 0 {
-		return false, fmt.Errorf("%s does not have same minor version as %s", v, c.orig)
+		return false, reasonMinor
 	}
 
 	// At this point the major is 0 and the minor is 0 and not dirty. The patch
 	// is not dirty so we need to check if they are equal. If they are not equal
 	eq = c.con.Patch() == v.Patch()
 	if eq {
-		return true, nil
+		return true, reasonNone
 	}
-	return false, fmt.Errorf("%s does not equal %s. Expect version and constraint to equal when major and minor versions are 0", v, c.orig)
+	return false, reasonPatchZeroMinor
 }
 
 func isX(x string) bool {
@@ -579,16 +1354,61 @@ func isX(x string) bool {
 	}
 }
 
-func rewriteRange(i string) string {
-	m := constraintRangeRegex.FindAllStringSubmatch(i, -1)
-	if m == nil {
-		return i
+// spaceChars are the characters matched by \s in the constraint regular
+// expressions.
+const spaceChars = " \t\n\f\r"
+
+// indexRangeHyphen returns the index of the first hyphen in s surrounded by
+// whitespace, which separates the two versions of a hyphen range, or -1 if
+// there is none.
+func indexRangeHyphen(s string) int {
+	for i := 1; i < len(s)-1; i++ {
+		if s[i] == '-' &&
+			strings.IndexByte(spaceChars, s[i-1]) >= 0 &&
+			strings.IndexByte(spaceChars, s[i+1]) >= 0 {
+			return i
+		}
 	}
-	o := i
-	for _, v := range m {
-		t := fmt.Sprintf(">= %s, <= %s ", v[1], v[11])
-		o = strings.Replace(o, v[0], t, 1)
+	return -1
+}
+
+// trimRangeSeparators removes the whitespace and comma separating a hyphen
+// range from the text before or after it. leading and trailing say which
+// ends of s are next to a range. ok is false when a comma separates a range
+// from nothing.
+func trimRangeSeparators(s string, leading, trailing bool) (string, bool) {
+	t := strings.Trim(s, spaceChars)
+
+	if t == "" {
+		return "", true
 	}
 
-	return o
+	// A lone comma between two ranges is the separator for both of them.
+	if leading && trailing && t == "," {
+		return "", true
+	}
+
+	if leading && strings.HasPrefix(t, ",") {
+		t = strings.TrimLeft(t[1:], spaceChars)
+		if t == "" {
+			return "", false
+		}
+	}
+	if trailing && strings.HasSuffix(t, ",") {
+		t = strings.TrimRight(t[:len(t)-1], spaceChars)
+		if t == "" {
+			return "", false
+		}
+	}
+
+	// Without a range on a side its whitespace is left for the constraint
+	// parsing to validate.
+	if !leading {
+		t = s[:len(s)-len(strings.TrimLeft(s, spaceChars))] + t
+	}
+	if !trailing {
+		t += s[len(strings.TrimRight(s, spaceChars)):]
+	}
+
+	return t, true
 }
@@ -3,8 +3,13 @@ package semver
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -274,6 +279,100 @@ func TestNewConstraint(t *testing.T) {
 	}
 }
 
+func TestNewConstraintNonASCII(t *testing.T) {
+	for _, c := range []string{">=１.2.3", "^1.2.3-bеta", "1.2.3 — 2.0.0"} {
+		_, err := NewConstraint(c)
+		if !errors.Is(err, ErrNonASCII) {
+			t.Errorf("Expected error for constraint %q to wrap %q but got %v", c, ErrNonASCII, err)
+		}
+	}
+}
+
+func TestNewConstraintEmpty(t *testing.T) {
+	tests := []struct {
+		input string
+		st    string
+	}{
+		{"", "*"},
+		{" \t", "*"},
+		{"1.2 ||", "* || 1.2"},
+		{" || ^2", "* || ^2"},
+	}
+
+	for _, tc := range tests {
+		_, err := NewConstraint(tc.input)
+		if !errors.Is(err, ErrEmptyConstraint) {
+			t.Errorf("Expected error for constraint %q to wrap %q but got %v", tc.input, ErrEmptyConstraint, err)
+		}
+	}
+
+	EmptyConstraintMatchesAll = true
+	defer func() { EmptyConstraintMatchesAll = false }()
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.input)
+		if err != nil {
+			t.Errorf("Unexpected error for constraint %q: %s", tc.input, err)
+			continue
+		}
+		if c.String() != tc.st {
+			t.Errorf("Expected constraint %q to be %q but got %q", tc.input, tc.st, c.String())
+		}
+		if !c.Check(MustParse("3.1.4")) {
+			t.Errorf("Expected constraint %q to match all versions", tc.input)
+		}
+		if c.Check(MustParse("3.1.4-beta")) {
+			t.Errorf("Expected constraint %q not to match prereleases", tc.input)
+		}
+	}
+
+	if _, err := NewConstraint("1, ,2"); err == nil || errors.Is(err, ErrEmptyConstraint) {
+		t.Errorf("Expected an improper constraint error for an empty AND constraint but got %v", err)
+	}
+}
+
+func TestNewConstraintVPrefix(t *testing.T) {
+	tests := []struct {
+		prefixed, bare string
+	}{
+		{"v1.2.3", "1.2.3"},
+		{">= v1.2, <v2", ">= 1.2, <2"},
+		{"v1.2 - v1.4.5", "1.2 - 1.4.5"},
+		{"v1.2 - 1.4.5 || ^v2.1", "1.2 - 1.4.5 || ^2.1"},
+		{"~v1.x !=v1.2.3-beta", "~1.x !=1.2.3-beta"},
+	}
+
+	for _, tc := range tests {
+		p, err := NewConstraint(tc.prefixed)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		b, err := NewConstraint(tc.bare)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		for _, vs := range []string{"1.1.0", "1.2.0", "1.2.3", "1.2.3-beta", "1.4.5", "1.9.0", "2.0.0", "2.1.0", "2.5.0", "v1.2.3"} {
+			v := MustParse(vs)
+			if p.Check(v) != b.Check(v) {
+				t.Errorf("Expected %q and %q to check %s the same", tc.prefixed, tc.bare, vs)
+			}
+		}
+		if i := Intersection(p, b); i.String() != b.String() && i.String() != p.String() {
+			t.Errorf("Expected the intersection of %q and %q to be one of them but got %q", tc.prefixed, tc.bare, i)
+		}
+	}
+
+	RejectConstraintVPrefix = true
+	defer func() { RejectConstraintVPrefix = false }()
+	for _, tc := range tests {
+		if _, err := NewConstraint(tc.prefixed); !errors.Is(err, ErrConstraintVPrefix) {
+			t.Errorf("Expected error for constraint %q to wrap %q but got %v", tc.prefixed, ErrConstraintVPrefix, err)
+		}
+		if _, err := NewConstraint(tc.bare); err != nil {
+			t.Errorf("Unexpected error for constraint %q: %s", tc.bare, err)
+		}
+	}
+}
+
 func TestConstraintsCheck(t *testing.T) {
 	tests := []struct {
 		constraint string
@@ -419,26 +518,225 @@ func TestConstraintsCheck(t *testing.T) {
 		if a != tc.check {
 			t.Errorf("Constraint '%s' failing with '%s'", tc.constraint, tc.version)
 		}
+
+		if all := c.CheckAll([]*Version{v}); all[0] != a {
+			t.Errorf("CheckAll for constraint '%s' with '%s' did not match Check", tc.constraint, tc.version)
+		}
+	}
+}
+
+func TestConstraintsCheckOrder(t *testing.T) {
+	c, err := NewConstraint("!=1.5.0, 1.x, ^1.0.0, >=1.0.0, =1.2.3 || 2.x")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var a []string
+	for _, cc := range c.checks[0] {
+		a = append(a, cc.string())
+	}
+
+	e := []string{"=1.2.3", ">=1.0.0", "^1.0.0", "1.x", "!=1.5.0"}
+	if !reflect.DeepEqual(a, e) {
+		t.Errorf("Expected constraints to be checked in the order %v but got %v", e, a)
+	}
+
+	// The order the constraints are displayed in is not changed.
+	if s := c.String(); s != "!=1.5.0 1.x ^1.0.0 >=1.0.0 =1.2.3 || 2.x" {
+		t.Errorf("Unexpected string for constraints: %q", s)
+	}
+}
+
+func TestConstraintsPrereleasePolicy(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		check      bool
+		sameTuple  bool
+	}{
+		{">=1.2.3-alpha", "1.2.3-beta", true, true},
+		{">=1.2.3-alpha", "2.0.0-beta", true, false},
+		{">=1.2.3-alpha", "2.0.0", true, true},
+		{">=1.2.3-alpha, <2.0.0", "1.2.3-beta", false, true},
+		{">=1.2.3-alpha, <2.0.0", "1.5.0-beta", false, false},
+		{">=1.2.3-alpha, <2.0.0", "1.5.0", true, true},
+		{">=1.2.3", "1.2.4-beta", false, false},
+		{"^1.2.3-rc.1", "1.2.3-rc.2", true, true},
+		{"^1.2.3-rc.1", "1.2.3-alpha", false, false},
+		{"1.2.3-alpha - 1.3.0", "1.2.3-beta", false, true},
+		{"<1.2.3 || >=2.0.0-0 <3", "2.0.0-beta", false, true},
+		{"<1.2.3 || >=2.0.0-0 <3", "2.1.0-beta", false, false},
+		{"!=1.2.3", "1.2.4-beta", true, false},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Errorf("err: %s", err)
+			continue
+		}
+		v := MustParse(tc.version)
+
+		if a := c.Check(v); a != tc.check {
+			t.Errorf("Constraint %q with %q expected %t but got %t", tc.constraint, tc.version, tc.check, a)
+		}
+
+		c.PrereleasePolicy = PrereleaseSameTuple
+		if a := c.Check(v); a != tc.sameTuple {
+			t.Errorf("Same tuple constraint %q with %q expected %t but got %t", tc.constraint, tc.version, tc.sameTuple, a)
+		}
+		a, errs := c.Validate(v)
+		if a != tc.sameTuple {
+			t.Errorf("Same tuple constraint %q with %q expected Validate %t but got %t", tc.constraint, tc.version, tc.sameTuple, a)
+		}
+		if a == (len(errs) > 0) {
+			t.Errorf("Same tuple constraint %q with %q returned %t with errors %v", tc.constraint, tc.version, a, errs)
+		}
+	}
+
+	c, _ := NewConstraint(">=1.2.3-alpha")
+	c.PrereleasePolicy = PrereleaseSameTuple
+	_, errs := c.Validate(MustParse("2.0.0-beta"))
+	e := "2.0.0-beta is a prerelease version and no constraint has a prerelease on the same major, minor, and patch version"
+	if len(errs) != 1 || errs[0].Error() != e {
+		t.Errorf("Expected error %q but got %v", e, errs)
+	}
+}
+
+func TestConstraintsDeny(t *testing.T) {
+	c, err := NewConstraint("^1.2.0")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	c.Deny = []*Version{MustParse("1.3.0"), MustParse("1.4.1+build.2")}
+
+	tests := []struct {
+		version string
+		check   bool
+	}{
+		{"1.2.0", true},
+		{"1.3.0", false},
+		{"1.3.0+build.9", false},
+		{"1.4.1", false},
+		{"1.4.2", true},
+		{"2.0.0", false},
+	}
+
+	vs := make([]*Version, len(tests))
+	for i, tc := range tests {
+		vs[i] = MustParse(tc.version)
+	}
+	all := c.CheckAll(vs)
+	cc := c.Compile()
+
+	for i, tc := range tests {
+		v := vs[i]
+		if a := c.Check(v); a != tc.check {
+			t.Errorf("Constraint %q with deny list and %q expected %t but got %t", c, tc.version, tc.check, a)
+		}
+		if all[i] != tc.check {
+			t.Errorf("CheckAll with deny list and %q expected %t but got %t", tc.version, tc.check, all[i])
+		}
+		if a := cc.Check(v); a != tc.check {
+			t.Errorf("Compiled check with deny list and %q expected %t but got %t", tc.version, tc.check, a)
+		}
+		if a := c.Explain(v).Satisfied; a != tc.check {
+			t.Errorf("Explain with deny list and %q expected %t but got %t", tc.version, tc.check, a)
+		}
+		a, errs := c.Validate(v)
+		if a != tc.check {
+			t.Errorf("Validate with deny list and %q expected %t but got %t", tc.version, tc.check, a)
+		}
+		if !a && len(errs) == 0 {
+			t.Errorf("Validate with deny list and %q expected errors", tc.version)
+		}
+	}
+
+	_, errs := c.Validate(MustParse("1.3.0"))
+	if len(errs) != 1 || errs[0].Error() != "1.3.0 is denied" {
+		t.Errorf("Expected a denied error but got %v", errs)
 	}
 }
 
-func TestRewriteRange(t *testing.T) {
+func TestConstraintsFilterVersions(t *testing.T) {
 	tests := []struct {
-		c  string
-		nc string
+		constraint string
+		versions   []string
+		expected   []string
 	}{
-		{"2 - 3", ">= 2, <= 3 "},
-		{"2 - 3, 2 - 3", ">= 2, <= 3 ,>= 2, <= 3 "},
-		{"2 - 3, 4.0.0 - 5.1", ">= 2, <= 3 ,>= 4.0.0, <= 5.1 "},
-		{"2 - 3 4.0.0 - 5.1", ">= 2, <= 3 >= 4.0.0, <= 5.1 "},
-		{"1.0.0 - 2.0.0 <=2.0.0", ">= 1.0.0, <= 2.0.0 <=2.0.0"},
+		{">=1.2", []string{"1.0.0", "1.2.0", "1.3.0-beta", "2.0.0"}, []string{"1.2.0", "2.0.0"}},
+		{"^1.2.0-0", []string{"1.2.0-beta", "1.3.0-beta", "2.0.0-beta", "1.9.0"}, []string{"1.2.0-beta", "1.3.0-beta", "1.9.0"}},
+		{"!=1.2.3", []string{"1.2.3", "1.2.4-alpha", "1.2.4"}, []string{"1.2.4-alpha", "1.2.4"}},
+		{"<1 || >=2.0.0-0", []string{"0.9.0", "1.5.0", "2.0.0-rc.1", "0.9.1-rc.1"}, []string{"0.9.0", "2.0.0-rc.1"}},
+		{"~3", []string{"1.0.0", "2.0.0"}, nil},
 	}
 
 	for _, tc := range tests {
-		o := rewriteRange(tc.c)
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Errorf("err: %s", err)
+			continue
+		}
 
-		if o != tc.nc {
-			t.Errorf("Range %s rewritten incorrectly as %q instead of expected %q", tc.c, o, tc.nc)
+		vs := make([]*Version, len(tc.versions))
+		for i, r := range tc.versions {
+			vs[i] = MustParse(r)
+		}
+
+		var a []string
+		for _, v := range c.FilterVersions(vs) {
+			a = append(a, v.Original())
+		}
+
+		if !reflect.DeepEqual(a, tc.expected) {
+			t.Errorf("Filtering with constraint '%s' returned %v instead of %v", tc.constraint, a, tc.expected)
+		}
+	}
+}
+
+func TestHyphenRange(t *testing.T) {
+	tests := []struct {
+		c   string
+		nc  string
+		err bool
+	}{
+		{"2 - 3", ">=2 <=3", false},
+		{"2 - 3, 2 - 3", ">=2 <=3 >=2 <=3", false},
+		{"2 - 3, 4.0.0 - 5.1", ">=2 <=3 >=4.0.0 <=5.1", false},
+		{"2 - 3 4.0.0 - 5.1", ">=2 <=3 >=4.0.0 <=5.1", false},
+		{"1.0.0 - 2.0.0 <=2.0.0", ">=1.0.0 <=2.0.0 <=2.0.0", false},
+		{"1.0.0 - 2.0.0, <=2.0.0", ">=1.0.0 <=2.0.0 <=2.0.0", false},
+		{">1, 1.0.0 - 2.0.0", ">1 >=1.0.0 <=2.0.0", false},
+		{"  1.0.0\t-\t2.0.0  ", ">=1.0.0 <=2.0.0", false},
+		{"v1.2.3-beta.1 - 2.x", ">=v1.2.3-beta.1 <=2.x", false},
+		{"1 - 2 || 3 - 4", ">=1 <=2 || >=3 <=4", false},
+		{"1.1-3", "1.1-3", false},
+		{"1 - 2 - 3", "", true},
+		{">= 1 - 2", "", true},
+		{">=1 - 2", "", true},
+		{"1 - >2", "", true},
+		{"1 -", "", true},
+		{", 1 - 2", "", true},
+		{"1 - 2,", "", true},
+		{"1 - 2,, 3 - 4", "", true},
+		{"1 - 2 ,, <3", "", true},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.c)
+		if tc.err {
+			if err == nil {
+				t.Errorf("Expected error for range %q", tc.c)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error for range %q: %s", tc.c, err)
+			continue
+		}
+
+		if o := c.String(); o != tc.nc {
+			t.Errorf("Range %s parsed incorrectly as %q instead of expected %q", tc.c, o, tc.nc)
 		}
 	}
 }
@@ -562,6 +860,13 @@ func TestConstraintsValidate(t *testing.T) {
 			t.Errorf("%q failed with %q but no errors returned", tc.constraint, tc.version)
 		}
 
+		f, err := c.ValidateFirst(v)
+		if f != tc.check || (err == nil) != f {
+			t.Errorf("Constraint %q with %q: ValidateFirst returned %t and %v", tc.constraint, tc.version, f, err)
+		} else if !f && err.Error() != msgs[0].Error() {
+			t.Errorf("Constraint %q with %q: expected the first error %q but got %q", tc.constraint, tc.version, msgs[0], err)
+		}
+
 		// if a == false {
 		// 	for _, m := range msgs {
 		// 		t.Errorf("%s", m)
@@ -569,6 +874,17 @@ func TestConstraintsValidate(t *testing.T) {
 		// }
 	}
 
+	// ValidateFirst follows Validate rather than Check for a prerelease and
+	// an exact != constraint.
+	ne := mustConstraint(t, "!=1.2.3")
+	pre := MustParse("1.2.4-beta")
+	if ok, _ := ne.Validate(pre); ok || !ne.Check(pre) {
+		t.Errorf("Expected !=1.2.3 with 1.2.4-beta to fail Validate but pass Check")
+	}
+	if ok, err := ne.ValidateFirst(pre); ok || err == nil {
+		t.Errorf("Expected !=1.2.3 with 1.2.4-beta to fail ValidateFirst like Validate but got %t and %v", ok, err)
+	}
+
 	v, err := StrictNewVersion("1.2.3")
 	if err != nil {
 		t.Errorf("err: %s", err)
@@ -654,6 +970,9 @@ func TestConstraintsValidate(t *testing.T) {
 			if e != tc.msg {
 				t.Errorf("Did not get expected message. Expected %q, got %q", tc.msg, e)
 			}
+			if _, err := c.ValidateFirst(v); err == nil || err.Error() != tc.msg {
+				t.Errorf("Did not get expected first message. Expected %q, got %v", tc.msg, err)
+			}
 		}
 	}
 }
@@ -669,6 +988,10 @@ func TestConstraintString(t *testing.T) {
 		{"2.x,   >=1.2.3 || >4.5.6, < 5.7", "2.x >=1.2.3 || >4.5.6 <5.7"},
 		{"2.x,   >=1.2.3 || >4.5.6, < 5.7 || >40.50.60, < 50.70", "2.x >=1.2.3 || >4.5.6 <5.7 || >40.50.60 <50.70"},
 		{"1.2", "1.2"},
+		{">40.50.60, < 50.70 || 2.x,   >=1.2.3 || >4.5.6, < 5.7", "2.x >=1.2.3 || >4.5.6 <5.7 || >40.50.60 <50.70"},
+		{"^2 || 1.10 || 1.9", "1.9 || 1.10 || ^2"},
+		{">=1.2.3 || =1.2.3 || 1.2.3 <2 || 1.2.3", "1.2.3 || 1.2.3 <2 || =1.2.3 || >=1.2.3"},
+		{"1.2.3-beta || 1.2.3-alpha", "1.2.3-alpha || 1.2.3-beta"},
 	}
 
 	for _, tc := range tests {
@@ -685,6 +1008,51 @@ func TestConstraintString(t *testing.T) {
 		if _, err = NewConstraint(c.String()); err != nil {
 			t.Errorf("expected string from constrint %q to parse as valid but got err: %s", tc.constraint, err)
 		}
+
+		allocs := testing.AllocsPerRun(10, func() {
+			_ = c.String()
+		})
+		if allocs != 0 {
+			t.Errorf("expected string for constraint %q to be cached but got %v allocations", tc.constraint, allocs)
+		}
+	}
+
+	if s := (Constraints{}).String(); s != "" {
+		t.Errorf("expected empty constraints to be an empty string but got %q", s)
+	}
+}
+
+func TestVerifyRoundTrip(t *testing.T) {
+	tests := []string{
+		"*",
+		"1.2.x",
+		">= 1.2.3, < 2",
+		"=> 1.2.3, =< 2",
+		"~> 1.2",
+		"^1.2.3-beta.1 || ~2.x-0",
+		"1.2 - 1.4.5",
+		"v1.2.3-alpha - v2.0.0+build.1",
+		"!=4.x, X",
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc)
+		if err != nil {
+			t.Errorf("cannot create constraint for %q, err: %s", tc, err)
+			continue
+		}
+
+		if err := VerifyRoundTrip(c); err != nil {
+			t.Errorf("expected constraint %q to round trip but got err: %s", tc, err)
+		}
+	}
+
+	// Constraints that do not render the same as they were parsed are
+	// reported.
+	c, _ := NewConstraint(">=1.2.3")
+	c.str = ">=1.2.4"
+	if err := VerifyRoundTrip(c); err == nil {
+		t.Error("expected constraint with a different string not to round trip")
 	}
 }
 
@@ -777,6 +1145,36 @@ func TestTextUnmarshalConstraints(t *testing.T) {
 	}
 }
 
+func TestConstraintsFlag(t *testing.T) {
+	c := &Constraints{PrereleasePolicy: PrereleaseSameTuple}
+	var v Constraints
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(c, "range", "versions to allow")
+	fs.TextVar(&v, "text", &Constraints{}, "versions to allow")
+
+	if err := fs.Parse([]string{"-range", ">= 1.2, < 2", "-text", "^3.1"}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if c.String() != ">=1.2 <2" || !c.Check(MustParse("1.5.0")) {
+		t.Errorf("Expected the flag to be parsed as >=1.2 <2 but got %q", c)
+	}
+	if c.PrereleasePolicy != PrereleaseSameTuple {
+		t.Error("Expected the prerelease policy to be kept")
+	}
+	if v.String() != "^3.1" {
+		t.Errorf("Expected the text flag to be parsed as ^3.1 but got %q", v.String())
+	}
+
+	err := fs.Parse([]string{"-range", ">=1.2.x.y"})
+	if err == nil || !strings.Contains(err.Error(), "improper constraint") {
+		t.Errorf("Expected an improper constraint error but got %v", err)
+	}
+	if c.String() != ">=1.2 <2" {
+		t.Errorf("Expected an invalid flag to leave the constraints unchanged but got %q", c)
+	}
+}
+
 func FuzzNewConstraint(f *testing.F) {
 	testcases := []string{
 		"v1.2.3",
@@ -805,7 +1203,99 @@ func FuzzNewConstraint(f *testing.F) {
 		f.Add(tc)
 	}
 
-	f.Fuzz(func(_ *testing.T, a string) {
-		_, _ = NewConstraint(a)
+	f.Fuzz(func(t *testing.T, a string) {
+		c, err := NewConstraint(a)
+		if err != nil {
+			return
+		}
+		if err := VerifyRoundTrip(c); err != nil {
+			t.Errorf("constraint %q does not round trip: %s", a, err)
+		}
 	})
 }
+
+func TestConstraintsTrace(t *testing.T) {
+	c, err := NewConstraint("^1.2 || >=2.1 <3")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	var events []TraceEvent
+	c.Trace = func(e TraceEvent) { events = append(events, e) }
+
+	if !c.Check(MustParse("2.4.0")) {
+		t.Fatal("Expected 2.4.0 to satisfy the constraints")
+	}
+
+	var got []string
+	for _, e := range events {
+		got = append(got, fmt.Sprintf("%d %s %t %s", e.Group, e.Constraint, e.Satisfied, e.Reason))
+		if e.Version.String() != "2.4.0" {
+			t.Errorf("Expected the traced version to be 2.4.0 but got %s", e.Version)
+		}
+	}
+	expected := []string{
+		"0 ^1.2 false 2.4.0 does not have same major version as 1.2",
+		"1 >=2.1 true ",
+		"1 <3 true ",
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected trace %q but got %q", expected, got)
+	}
+
+	events = nil
+	c.Validate(MustParse("2.0.0-beta.1"))
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events validating a prerelease but got %v", events)
+	}
+	for _, e := range events {
+		if e.Satisfied || e.Reason != "2.0.0-beta.1 is a prerelease version and the constraint is only looking for release versions" {
+			t.Errorf("Expected the prerelease to fail each constraint but got %+v", e)
+		}
+	}
+
+	events = nil
+	if !c.Compile().Check(MustParse("1.5.0")) || len(events) != 1 {
+		t.Errorf("Expected the compiled constraints to call the hook once but got %v", events)
+	}
+}
+
+// TestConcurrentReaders shares parsed versions and constraints between
+// goroutines. Run it with -race, see make test-race, to find data races.
+func TestConcurrentReaders(t *testing.T) {
+	c, err := NewConstraint("^1.2 || >=2.1 <3 !=2.2.0")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	c.Deny = []*Version{MustParse("1.4.0")}
+	vs := []*Version{MustParse("1.2.3"), MustParse("1.4.0"), MustParse("2.2.0"), MustParse("v2.5.0-beta.1+b1")}
+	expected := c.CheckAll(vs)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := 0; k < 100; k++ {
+				if got := c.CheckAll(vs); !reflect.DeepEqual(got, expected) {
+					t.Errorf("Expected %v but got %v", expected, got)
+					return
+				}
+				c.Validate(vs[k%len(vs)])
+				_ = c.String()
+				for _, v := range vs {
+					n := v.IncPatch()
+					if _, err := n.SetPrerelease("rc.1"); err != nil {
+						t.Errorf("err: %s", err)
+					}
+					_ = v.String() + v.Original()
+					v.Compare(&n)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if vs[0].String() != "1.2.3" || vs[3].Original() != "v2.5.0-beta.1+b1" {
+		t.Errorf("Expected the shared versions to be unchanged but got %s and %s", vs[0], vs[3].Original())
+	}
+}
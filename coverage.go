@@ -0,0 +1,115 @@
+package semver
+
+import (
+	"errors"
+	"strings"
+)
+
+// CoverageReport describes which versions from a catalog are admitted by
+// constraints and why the others are excluded. It is returned by Coverage.
+type CoverageReport struct {
+	// Admitted are the versions that satisfy the constraints.
+	Admitted Collection
+
+	// Excluded are the versions that do not satisfy the constraints.
+	Excluded Collection
+
+	// Exclusions group the excluded versions by the reason they were
+	// excluded, in the order the reasons were first found.
+	Exclusions []CoverageExclusion
+}
+
+// CoverageExclusion is a group of versions excluded for the same reason.
+type CoverageExclusion struct {
+	// Reason describes why the versions were excluded. It is either that
+	// the versions are denied, that prereleases are not allowed, or it names
+	// the constraints that exclude the versions, such as "excluded by >=1.3
+	// and ^2.0.0" where the constraint has the groups >=1.3 <1.4 || ^2.0.0.
+	Reason string
+
+	// Versions are the excluded versions in the order they were passed in.
+	Versions Collection
+}
+
+// Coverage checks each of the versions against the constraints and reports
+// which are admitted and which are excluded and why. This supports audits
+// such as finding a range that excludes the only patched versions. Nil
+// versions are ignored.
+func Coverage(versions Collection, cs *Constraints) *CoverageReport {
+	r := &CoverageReport{}
+	idx := make(map[string]int)
+	for _, v := range versions {
+		if v == nil {
+			continue
+		}
+
+		ex := cs.Explain(v)
+		if ex.Satisfied {
+			r.Admitted = append(r.Admitted, v)
+			continue
+		}
+		r.Excluded = append(r.Excluded, v)
+
+		reason := exclusionReason(ex)
+		i, ok := idx[reason]
+		if !ok {
+			i = len(r.Exclusions)
+			idx[reason] = i
+			r.Exclusions = append(r.Exclusions, CoverageExclusion{Reason: reason})
+		}
+		r.Exclusions[i].Versions = append(r.Exclusions[i].Versions, v)
+	}
+
+	return r
+}
+
+// exclusionReason describes why a version failed the explained constraints
+// using the first failure in each group.
+func exclusionReason(ex *Explanation) string {
+	if ex.Denied {
+		return "denied"
+	}
+	if len(ex.Groups) == 0 {
+		return "no versions satisfy the constraints"
+	}
+
+	var by []string
+	seen := make(map[string]bool)
+	pre := true
+	for _, g := range ex.Groups {
+		s := "prerelease"
+		if !isPrereleaseReason(g.Reason) {
+			for _, c := range g.Comparators {
+				if !c.Satisfied {
+					if !isPrereleaseReason(c.Reason) {
+						s = c.Constraint
+					}
+					break
+				}
+			}
+		}
+
+		if s != "prerelease" {
+			pre = false
+		}
+		if !seen[s] {
+			seen[s] = true
+			by = append(by, s)
+		}
+	}
+
+	if pre {
+		return "prereleases are not allowed"
+	}
+	return "excluded by " + strings.Join(by, " and ")
+}
+
+// isPrereleaseReason reports if the error is a failure because the version
+// is a prerelease.
+func isPrereleaseReason(err error) bool {
+	var ce *constraintError
+	if !errors.As(err, &ce) {
+		return false
+	}
+	return ce.reason == reasonPrerelease || ce.reason == reasonPrereleaseTuple
+}
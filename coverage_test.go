@@ -0,0 +1,71 @@
+package semver
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCoverage(t *testing.T) {
+	raw := []string{
+		"1.2.0",
+		"1.3.0",
+		"1.3.5",
+		"1.3.6-beta",
+		"1.4.0",
+		"1.5.1",
+		"2.0.0",
+		"2.1.0",
+		"3.0.0",
+	}
+	vs := make(Collection, len(raw))
+	for i, r := range raw {
+		vs[i] = MustParse(r)
+	}
+
+	c, err := NewConstraint(">=1.3, <1.4 || ^2.0.0")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	r := Coverage(append(vs, nil), c)
+
+	strs := func(c Collection) []string {
+		s := make([]string, len(c))
+		for i, v := range c {
+			s[i] = v.String()
+		}
+		return s
+	}
+
+	if e, a := []string{"1.3.0", "1.3.5", "2.0.0", "2.1.0"}, strs(r.Admitted); !reflect.DeepEqual(e, a) {
+		t.Errorf("Expected admitted versions %v but got %v", e, a)
+	}
+	if e, a := []string{"1.2.0", "1.3.6-beta", "1.4.0", "1.5.1", "3.0.0"}, strs(r.Excluded); !reflect.DeepEqual(e, a) {
+		t.Errorf("Expected excluded versions %v but got %v", e, a)
+	}
+
+	exclusions := []struct {
+		reason   string
+		versions []string
+	}{
+		{"excluded by >=1.3 and ^2.0.0", []string{"1.2.0"}},
+		{"prereleases are not allowed", []string{"1.3.6-beta"}},
+		{"excluded by <1.4 and ^2.0.0", []string{"1.4.0", "1.5.1", "3.0.0"}},
+	}
+	if len(r.Exclusions) != len(exclusions) {
+		t.Fatalf("Expected %d exclusions but got %d", len(exclusions), len(r.Exclusions))
+	}
+	for i, e := range exclusions {
+		if r.Exclusions[i].Reason != e.reason {
+			t.Errorf("Expected exclusion %d to be %q but got %q", i, e.reason, r.Exclusions[i].Reason)
+		}
+		if a := strs(r.Exclusions[i].Versions); !reflect.DeepEqual(e.versions, a) {
+			t.Errorf("Expected versions excluded by %q to be %v but got %v", e.reason, e.versions, a)
+		}
+	}
+
+	empty := Intersection(mustConstraint(t, "^1"), mustConstraint(t, "^2"))
+	r = Coverage(Collection{MustParse("1.0.0"), MustParse("2.0.0")}, empty)
+	if len(r.Exclusions) != 1 || r.Exclusions[0].Reason != "no versions satisfy the constraints" {
+		t.Errorf("Expected empty constraints to exclude every version as not satisfiable but got %v", r.Exclusions)
+	}
+}
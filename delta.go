@@ -0,0 +1,165 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidDelta is returned when a delta can not be parsed or applied to
+// a version.
+var ErrInvalidDelta = errors.New("Invalid version delta")
+
+// Delta describes how to move a version rather than the version to move to,
+// such as two minor releases ahead with a beta prerelease. Declarative
+// release configuration can hold a Delta, see ParseDelta, and apply it to
+// the current version with ApplyDelta.
+type Delta struct {
+	// Major, Minor, and Patch are how much to increment each part by.
+	// Incrementing a part sets the parts after it to 0 before they are
+	// incremented, so major+1 minor+2 moves 1.2.3 to 2.2.0.
+	Major, Minor, Patch uint64
+
+	// Prerelease and Metadata replace those of the version when
+	// SetPrerelease and SetMetadata are true. An empty value removes them.
+	Prerelease, Metadata       string
+	SetPrerelease, SetMetadata bool
+}
+
+// ApplyDelta returns the version moved by the delta. When any part is
+// incremented the prerelease and metadata are removed, unless the delta sets
+// them. Unlike IncPatch, patch+1 moves a prerelease such as 1.2.3-beta to
+// 1.2.4 rather than releasing it as 1.2.3. A leading v on the version is
+// kept. An error wrapping ErrInvalidDelta is returned when a part would
+// overflow or the prerelease or metadata is not valid.
+func ApplyDelta(v *Version, d Delta) (*Version, error) {
+	vNext := *v
+	if d.Major > 0 || d.Minor > 0 || d.Patch > 0 {
+		vNext.pre = ""
+		vNext.metadata = ""
+	}
+	if d.Major > 0 {
+		if vNext.major > math.MaxUint64-d.Major {
+			return nil, fmt.Errorf("%w: major version %d overflows", ErrInvalidDelta, vNext.major)
+		}
+		vNext.major += d.Major
+		vNext.minor, vNext.patch = 0, 0
+	}
+	if d.Minor > 0 {
+		if vNext.minor > math.MaxUint64-d.Minor {
+			return nil, fmt.Errorf("%w: minor version %d overflows", ErrInvalidDelta, vNext.minor)
+		}
+		vNext.minor += d.Minor
+		vNext.patch = 0
+	}
+	if d.Patch > 0 {
+		if vNext.patch > math.MaxUint64-d.Patch {
+			return nil, fmt.Errorf("%w: patch version %d overflows", ErrInvalidDelta, vNext.patch)
+		}
+		vNext.patch += d.Patch
+	}
+
+	if d.SetPrerelease {
+		if d.Prerelease != "" {
+			if err := validatePrerelease(d.Prerelease); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidDelta, err)
+			}
+		}
+		vNext.pre = d.Prerelease
+	}
+	if d.SetMetadata {
+		if d.Metadata != "" {
+			if err := validateMetadata(d.Metadata); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidDelta, err)
+			}
+		}
+		vNext.metadata = d.Metadata
+	}
+
+	vNext.original = v.originalVPrefix() + vNext.String()
+	return &vNext, nil
+}
+
+// ParseDelta parses a delta written as space or comma separated terms, such
+// as "minor+2" or "major+1, pre=beta.1". The terms are:
+//
+//	major+N, minor+N, patch+N  increment the part by N
+//	major, minor, patch        increment the part by 1
+//	pre=ID                     set the prerelease, or remove it when ID is empty
+//	meta=ID                    set the metadata, or remove it when ID is empty
+//
+// The order of the terms does not matter and each can be given once. An
+// error wrapping ErrInvalidDelta is returned for other terms.
+func ParseDelta(s string) (Delta, error) {
+	var d Delta
+	seen := make(map[string]bool)
+	for _, t := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || strings.ContainsRune(spaceChars, r)
+	}) {
+		name, val, isSet := strings.Cut(t, "=")
+		var n uint64 = 1
+		if !isSet {
+			var inc string
+			var hasInc bool
+			name, inc, hasInc = strings.Cut(t, "+")
+			if hasInc {
+				var err error
+				if n, err = strconv.ParseUint(inc, 10, 64); err != nil || n == 0 {
+					return Delta{}, fmt.Errorf("%w: invalid increment in %q", ErrInvalidDelta, t)
+				}
+			}
+		}
+		if seen[name] {
+			return Delta{}, fmt.Errorf("%w: %s is given more than once", ErrInvalidDelta, name)
+		}
+		seen[name] = true
+
+		switch {
+		case name == "major" && !isSet:
+			d.Major = n
+		case name == "minor" && !isSet:
+			d.Minor = n
+		case name == "patch" && !isSet:
+			d.Patch = n
+		case name == "pre" && isSet:
+			if val != "" {
+				if err := validatePrerelease(val); err != nil {
+					return Delta{}, fmt.Errorf("%w: %w", ErrInvalidDelta, err)
+				}
+			}
+			d.Prerelease, d.SetPrerelease = val, true
+		case name == "meta" && isSet:
+			if val != "" {
+				if err := validateMetadata(val); err != nil {
+					return Delta{}, fmt.Errorf("%w: %w", ErrInvalidDelta, err)
+				}
+			}
+			d.Metadata, d.SetMetadata = val, true
+		default:
+			return Delta{}, fmt.Errorf("%w: unknown term %q", ErrInvalidDelta, t)
+		}
+	}
+	return d, nil
+}
+
+// String returns the delta in the form parsed by ParseDelta.
+func (d Delta) String() string {
+	var terms []string
+	for _, p := range []struct {
+		name string
+		n    uint64
+	}{{"major", d.Major}, {"minor", d.Minor}, {"patch", d.Patch}} {
+		if p.n > 0 {
+			terms = append(terms, p.name+"+"+strconv.FormatUint(p.n, 10))
+		}
+	}
+	if d.SetPrerelease {
+		terms = append(terms, "pre="+d.Prerelease)
+	}
+	if d.SetMetadata {
+		terms = append(terms, "meta="+d.Metadata)
+	}
+	return strings.Join(terms, " ")
+}
@@ -0,0 +1,62 @@
+package semver
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestApplyDelta(t *testing.T) {
+	tests := []struct {
+		v, delta, expected string
+	}{
+		{"1.2.3", "minor+2", "1.4.0"},
+		{"1.2.3", "major", "2.0.0"},
+		{"1.2.3", "major+1 minor+2", "2.2.0"},
+		{"v1.2.3", "patch+3", "v1.2.6"},
+		{"1.2.3-beta+b1", "patch", "1.2.4"},
+		{"1.2.3", "minor, pre=rc.1", "1.3.0-rc.1"},
+		{"1.3.0-rc.1+b2", "pre=", "1.3.0+b2"},
+		{"1.3.0-rc.1", "meta=build.7", "1.3.0-rc.1+build.7"},
+		{"1.2.3", "", "1.2.3"},
+	}
+
+	for _, tc := range tests {
+		d, err := ParseDelta(tc.delta)
+		if err != nil {
+			t.Errorf("Unexpected error parsing %q: %s", tc.delta, err)
+			continue
+		}
+		v, err := ApplyDelta(MustParse(tc.v), d)
+		if err != nil {
+			t.Errorf("Unexpected error applying %q to %s: %s", tc.delta, tc.v, err)
+			continue
+		}
+		if v.Original() != tc.expected {
+			t.Errorf("Expected %q applied to %s to be %s but got %s", tc.delta, tc.v, tc.expected, v.Original())
+		}
+
+		rd, err := ParseDelta(d.String())
+		if err != nil || rd != d {
+			t.Errorf("Expected %q to round trip through %q but got %+v", tc.delta, d, rd)
+		}
+	}
+
+	if _, err := ApplyDelta(New(1<<64-1, 0, 0, "", ""), Delta{Major: 1}); !errors.Is(err, ErrInvalidDelta) {
+		t.Errorf("Expected an overflow to be %q but got %v", ErrInvalidDelta, err)
+	}
+	if _, err := ApplyDelta(MustParse("1.2.3"), Delta{Prerelease: "01", SetPrerelease: true}); !errors.Is(err, ErrInvalidDelta) || !errors.Is(err, ErrSegmentStartsZero) {
+		t.Errorf("Expected %q and %q but got %v", ErrInvalidDelta, ErrSegmentStartsZero, err)
+	}
+	if _, err := ParseDelta("pre=beta..1"); !errors.Is(err, ErrInvalidPrerelease) {
+		t.Errorf("Expected %q but got %v", ErrInvalidPrerelease, err)
+	}
+	if _, err := ParseDelta("meta=a..b"); !errors.Is(err, ErrInvalidMetadata) {
+		t.Errorf("Expected %q but got %v", ErrInvalidMetadata, err)
+	}
+
+	for _, s := range []string{"minor+0", "minor+x", "minor+1 minor+2", "major=2", "pre+1", "build+1", "meta=a..b"} {
+		if _, err := ParseDelta(s); !errors.Is(err, ErrInvalidDelta) {
+			t.Errorf("Expected %q to be %q but got %v", s, ErrInvalidDelta, err)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrOperatorNotAllowed is returned when a constraint uses an operator
+	// that is not in the operators of the dialect it is parsed with.
+	ErrOperatorNotAllowed = errors.New("Operator is not allowed in the dialect")
+
+	// ErrDialectRegistered is returned by RegisterDialect when there is
+	// already a dialect with the same name.
+	ErrDialectRegistered = errors.New("Dialect is already registered")
+)
+
+// Dialect is a set of rules for parsing versions and constraints, such as
+// those of npm or Cargo. It brings together the options of this package
+// that differ between ecosystems so they can be selected by name, see
+// LookupDialect, rather than set one by one.
+//
+// A Dialect must not be changed once it is registered.
+type Dialect struct {
+	// Name identifies the dialect, such as npm.
+	Name string
+
+	// StrictVersions parses versions with StrictNewVersion rather than
+	// NewVersion. It does not apply to the versions in constraints, which
+	// may still be partial, such as >=1.2.
+	StrictVersions bool
+
+	// RejectVPrefix rejects a version with a leading v in constraints with
+	// an error wrapping ErrConstraintVPrefix, as RejectConstraintVPrefix
+	// does for NewConstraint.
+	RejectVPrefix bool
+
+	// PrereleasePolicy is set on the constraints the dialect parses.
+	PrereleasePolicy PrereleasePolicy
+
+	// Operators are the operators allowed in constraints, such as ^ and >=.
+	// The empty operator is a version on its own. Aliases, such as => for
+	// >=, are allowed with the operator they stand for. A hyphen range is
+	// allowed with >= and <=. When it is nil every operator is allowed.
+	Operators []string
+
+	// Rewrite, when set, returns the comparison with the operator and
+	// version as written in a constraint in the syntax of this package. The
+	// comparison is parsed in place of the original one. This is how
+	// operators that mean something else in the dialect are supported, such
+	// as a version on its own meaning ^ in Cargo.
+	Rewrite func(op, ver string) string
+}
+
+// NewVersion parses a version with the rules of the dialect.
+func (d *Dialect) NewVersion(v string) (*Version, error) {
+	if d.StrictVersions {
+		return StrictNewVersion(v)
+	}
+	return NewVersion(v)
+}
+
+// NewConstraint parses constraints with the rules of the dialect. They are
+// first parsed with NewConstraint and then checked against the operators of
+// the dialect and rewritten. The constraints have the prerelease policy of
+// the dialect.
+func (d *Dialect) NewConstraint(c string) (*Constraints, error) {
+	cs, err := NewConstraint(c)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([][]string, len(cs.constraints))
+	for k, o := range cs.constraints {
+		g := make([]string, len(o))
+		for i, cc := range o {
+			if !d.allows(cc.origfunc) {
+				return nil, fmt.Errorf("improper constraint: %s: %w: %q in %s", c, ErrOperatorNotAllowed, cc.origfunc, d.Name)
+			}
+			if d.RejectVPrefix && strings.HasPrefix(cc.orig, "v") {
+				return nil, fmt.Errorf("improper constraint: %s: %w", cc.string(), ErrConstraintVPrefix)
+			}
+			g[i] = cc.string()
+			if d.Rewrite != nil {
+				g[i] = d.Rewrite(cc.origfunc, cc.orig)
+			}
+		}
+		groups[k] = g
+	}
+
+	if d.Rewrite != nil {
+		if cs, err = rebuildConstraints(cs, groups); err != nil {
+			return nil, err
+		}
+	}
+	cs.PrereleasePolicy = d.PrereleasePolicy
+	return cs, nil
+}
+
+// allows reports if the operator is one of the operators of the dialect.
+func (d *Dialect) allows(op string) bool {
+	if d.Operators == nil {
+		return true
+	}
+	for _, o := range d.Operators {
+		if canonicalOp(o) == canonicalOp(op) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// DialectDefault is the default behavior of this package.
+	DialectDefault = &Dialect{Name: "default"}
+
+	// DialectStrict only accepts versions that follow the semantic version
+	// spec, without a leading v or missing parts. Constraints can not have a
+	// leading v but may have partial versions and wildcards, such as >=1.2
+	// or 1.x.
+	DialectStrict = &Dialect{
+		Name:           "strict",
+		StrictVersions: true,
+		RejectVPrefix:  true,
+	}
+
+	// DialectNPM is closer to the ranges of npm than the default. Prereleases
+	// follow PrereleaseSameTuple, the rule node-semver uses, and != is not
+	// allowed. It is not checked against the full node-semver test suite and
+	// npm ranges this package does not parse, such as an empty range, are
+	// errors.
+	DialectNPM = &Dialect{
+		Name:             "npm",
+		PrereleasePolicy: PrereleaseSameTuple,
+		Operators:        []string{"", "=", "<", "<=", ">", ">=", "~", "^"},
+	}
+
+	// DialectCargo follows the version requirements of Cargo. A version on
+	// its own is a ^ requirement, so 1.2.3 allows 1.9.0, and versions
+	// follow the semantic version spec. Prereleases follow
+	// PrereleaseSameTuple and != is not allowed.
+	DialectCargo = &Dialect{
+		Name:             "cargo",
+		StrictVersions:   true,
+		RejectVPrefix:    true,
+		PrereleasePolicy: PrereleaseSameTuple,
+		Operators:        []string{"", "=", "<", "<=", ">", ">=", "~", "^"},
+		Rewrite: func(op, ver string) string {
+			if op == "" && !isX(strings.SplitN(ver, ".", 2)[0]) {
+				return "^" + ver
+			}
+			return op + ver
+		},
+	}
+
+	// DialectRuby follows the version requirements of RubyGems. ~> with a
+	// major and minor version allows any later minor version, so ~>1.2 is
+	// >=1.2 <2, and ^ is not allowed. RubyGems prereleases, such as
+	// 1.2.3.beta, are not supported.
+	DialectRuby = &Dialect{
+		Name:      "ruby",
+		Operators: []string{"", "=", "!=", "<", "<=", ">", ">=", "~>"},
+		Rewrite:   rewriteRubyPessimistic,
+	}
+)
+
+// rewriteRubyPessimistic writes the RubyGems ~> operator with a major and
+// minor version as a range up to the next major version.
+func rewriteRubyPessimistic(op, ver string) string {
+	parts := strings.Split(strings.TrimPrefix(ver, "v"), ".")
+	if canonicalOp(op) != "~" || len(parts) != 2 {
+		return op + ver
+	}
+	major, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil || major == math.MaxUint64 {
+		return op + ver
+	}
+	return ">=" + ver + " <" + strconv.FormatUint(major+1, 10)
+}
+
+var dialectsMu sync.RWMutex
+var dialects = map[string]*Dialect{}
+
+func init() {
+	for _, d := range []*Dialect{DialectDefault, DialectStrict, DialectNPM, DialectCargo, DialectRuby} {
+		dialects[d.Name] = d
+	}
+}
+
+// RegisterDialect makes a dialect available by its name with LookupDialect.
+// An error wrapping ErrDialectRegistered is returned when there is already a
+// dialect with the name. It is safe for concurrent use.
+func RegisterDialect(d *Dialect) error {
+	if d == nil || d.Name == "" {
+		return errors.New("a dialect needs a name to be registered")
+	}
+
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+	if _, ok := dialects[d.Name]; ok {
+		return fmt.Errorf("%w: %s", ErrDialectRegistered, d.Name)
+	}
+	dialects[d.Name] = d
+	return nil
+}
+
+// LookupDialect returns the registered dialect with the name, such as npm.
+func LookupDialect(name string) (*Dialect, bool) {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+	d, ok := dialects[name]
+	return d, ok
+}
+
+// Dialects returns the registered dialects ordered by name.
+func Dialects() []*Dialect {
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+	ds := make([]*Dialect, 0, len(dialects))
+	for _, d := range dialects {
+		ds = append(ds, d)
+	}
+	sort.Slice(ds, func(i, j int) bool {
+		return ds[i].Name < ds[j].Name
+	})
+	return ds
+}
@@ -0,0 +1,103 @@
+package semver
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDialects(t *testing.T) {
+	tests := []struct {
+		dialect    string
+		constraint string
+		version    string
+		check      bool
+		err        error
+	}{
+		{"default", "1.2.3", "1.2.3", true, nil},
+		{"default", "1.2.3", "v1.2.3", true, nil},
+		{"default", ">=1.2.3-beta.1", "1.5.0-alpha", true, nil},
+		{"strict", "1.2.3", "v1.2.3", false, ErrInvalidCharacters},
+		{"strict", ">=v1.2.3", "1.2.3", false, ErrConstraintVPrefix},
+		{"strict", ">=1.2 <2", "1.2.3", true, nil},
+		{"npm", ">=1.2.3-beta.1", "1.5.0-alpha", false, nil},
+		{"npm", ">=1.2.3-beta.1 <2", "1.2.3-beta.2", true, nil},
+		{"npm", "!=1.2.3", "1.2.4", false, ErrOperatorNotAllowed},
+		{"cargo", "1.2.3", "1.9.0", true, nil},
+		{"cargo", "1.2.3", "2.0.0", false, nil},
+		{"cargo", "=1.2.3", "1.9.0", false, nil},
+		{"cargo", "*", "4.1.0", true, nil},
+		{"cargo", ">=1.2, <1.5", "1.4.0", true, nil},
+		{"cargo", "!=1.2.3", "1.2.0", false, ErrOperatorNotAllowed},
+		{"cargo", "1.2.3", "v1.2.3", false, ErrInvalidCharacters},
+		{"ruby", "~>1.2", "1.9.0", true, nil},
+		{"ruby", "~>1.2", "2.0.0", false, nil},
+		{"ruby", "~> 1.2.3", "1.3.0", false, nil},
+		{"ruby", ">= 1.2, != 1.4.0", "1.4.0", false, nil},
+		{"ruby", "^1.2", "1.2.0", false, ErrOperatorNotAllowed},
+	}
+
+	for _, tc := range tests {
+		d, ok := LookupDialect(tc.dialect)
+		if !ok {
+			t.Fatalf("Expected the %s dialect to be registered", tc.dialect)
+		}
+
+		c, err := d.NewConstraint(tc.constraint)
+		var v *Version
+		if err == nil {
+			v, err = d.NewVersion(tc.version)
+		}
+		if tc.err != nil {
+			if !errors.Is(err, tc.err) {
+				t.Errorf("Expected %q with %q in %s to fail with %q but got %v", tc.constraint, tc.version, tc.dialect, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if c.PrereleasePolicy != d.PrereleasePolicy {
+			t.Errorf("Expected %q in %s to have the prerelease policy of the dialect", tc.constraint, tc.dialect)
+		}
+		if c.Check(v) != tc.check {
+			t.Errorf("Expected %q with %q in %s to be %t", tc.constraint, tc.version, tc.dialect, tc.check)
+		}
+	}
+}
+
+func TestRegisterDialect(t *testing.T) {
+	d := &Dialect{Name: "test-caret", Operators: []string{"^"}}
+	if err := RegisterDialect(d); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer func() {
+		dialectsMu.Lock()
+		delete(dialects, d.Name)
+		dialectsMu.Unlock()
+	}()
+
+	if got, ok := LookupDialect("test-caret"); !ok || got != d {
+		t.Errorf("Expected the registered dialect but got %v", got)
+	}
+	if err := RegisterDialect(&Dialect{Name: "npm"}); !errors.Is(err, ErrDialectRegistered) {
+		t.Errorf("Expected an error registering npm again but got %v", err)
+	}
+	if err := RegisterDialect(&Dialect{}); err == nil {
+		t.Error("Expected an error registering a dialect without a name")
+	}
+
+	var names []string
+	for _, d := range Dialects() {
+		names = append(names, d.Name)
+	}
+	expected := []string{"cargo", "default", "npm", "ruby", "strict", "test-caret"}
+	if len(names) != len(expected) {
+		t.Fatalf("Expected dialects %q but got %q", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Errorf("Expected dialects %q but got %q", expected, names)
+			break
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package semver
+
+import (
+	"strings"
+)
+
+// Range is a contiguous range of versions. A nil bound is unbounded.
+type Range struct {
+	// Min is the lowest version in the range and MinInclusive is if it is
+	// part of the range.
+	Min          *Version
+	MinInclusive bool
+
+	// Max is the highest version in the range and MaxInclusive is if it is
+	// part of the range.
+	Max          *Version
+	MaxInclusive bool
+}
+
+// String returns the range as constraints, such as >=1.2.0 <2.0.0, =1.4.0
+// for a single version, or * when the range is unbounded.
+func (r Range) String() string {
+	return interval{lo: r.Min, hi: r.Max, loInc: r.MinInclusive, hiInc: r.MaxInclusive}.String()
+}
+
+// ConstraintsDiff describes how the versions allowed by constraints changed.
+// It is returned by DiffConstraints.
+type ConstraintsDiff struct {
+	// Added are the ranges of versions only the new constraints allow.
+	Added []Range
+
+	// Removed are the ranges of versions only the old constraints allow.
+	Removed []Range
+
+	// Unchanged are the ranges of versions both constraints allow.
+	Unchanged []Range
+}
+
+// DiffConstraints compares the versions allowed by the constraints before and
+// after a change, such as an old and a new policy under review. ^1.2
+// changed to >=1.4 <2.5 removes >=1.2.0 <1.4.0, adds >=2.0.0 <2.5.0, and
+// leaves >=1.4.0 <2.0.0 unchanged. The ranges are sorted and do not overlap.
+//
+// Ranges are compared by their bounds. Which prereleases are allowed is not
+// considered.
+func DiffConstraints(before, after *Constraints) *ConstraintsDiff {
+	a, b := before.intervalSet(), after.intervalSet()
+	return &ConstraintsDiff{
+		Added:     toRanges(subtractIntervals(b, a)),
+		Removed:   toRanges(subtractIntervals(a, b)),
+		Unchanged: toRanges(intersectIntervals(a, b)),
+	}
+}
+
+// Changed reports if the constraints allow different versions.
+func (d *ConstraintsDiff) Changed() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0
+}
+
+// String returns a summary of the diff for review, with a line each for the
+// added, removed, and unchanged ranges that are not empty.
+func (d *ConstraintsDiff) String() string {
+	if !d.Changed() && len(d.Unchanged) == 0 {
+		return "no versions allowed before or after"
+	}
+
+	var sb strings.Builder
+	for _, l := range []struct {
+		name   string
+		ranges []Range
+	}{
+		{"added", d.Added},
+		{"removed", d.Removed},
+		{"unchanged", d.Unchanged},
+	} {
+		if len(l.ranges) == 0 {
+			continue
+		}
+		s := make([]string, len(l.ranges))
+		for i, r := range l.ranges {
+			s[i] = r.String()
+		}
+		sb.WriteString(l.name)
+		sb.WriteString(": ")
+		sb.WriteString(strings.Join(s, " || "))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// toRanges converts intervals to the exported Range form. Nil is returned
+// when there are no intervals so the fields of an unchanged diff are empty.
+func toRanges(ivs []interval) []Range {
+	if len(ivs) == 0 {
+		return nil
+	}
+	r := make([]Range, len(ivs))
+	for i, iv := range ivs {
+		r[i] = Range{Min: iv.lo, MinInclusive: iv.loInc, Max: iv.hi, MaxInclusive: iv.hiInc}
+	}
+	return r
+}
@@ -0,0 +1,69 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestDiffConstraints(t *testing.T) {
+	tests := []struct {
+		old, new  string
+		added     string
+		removed   string
+		unchanged string
+	}{
+		{"^1.2", ">=1.4 <2.5", ">=2.0.0 <2.5.0", ">=1.2.0 <1.4.0", ">=1.4.0 <2.0.0"},
+		{"^1.2", "^1.2.0", "", "", ">=1.2.0 <2.0.0"},
+		{"^1.2 || ^1.5", "1.2 - 1.9", "", ">=1.10.0 <2.0.0", ">=1.2.0 <1.10.0"},
+		{"^1.2 || ^1.5", "1.2.0 - 2.0.0", "=2.0.0", "", ">=1.2.0 <2.0.0"},
+		{"^1.2", "^1.2 !=1.4.1", "", "=1.4.1", ">=1.2.0 <1.4.1 || >1.4.1 <2.0.0"},
+		{"^1.2", "^1.2 !=1.4.x", "", ">=1.4.0 <1.5.0", ">=1.2.0 <1.4.0 || >=1.5.0 <2.0.0"},
+		{"^1", "^2", ">=2.0.0 <3.0.0", ">=1.0.0 <2.0.0", ""},
+		{"<1.0.0", "*", ">=1.0.0", "", "<1.0.0"},
+		{">1.2.3", ">=1.2.3", "=1.2.3", "", ">1.2.3"},
+	}
+
+	for _, tc := range tests {
+		o, err := NewConstraint(tc.old)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		n, err := NewConstraint(tc.new)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		d := DiffConstraints(o, n)
+		if s := rangesString(d.Added); s != tc.added {
+			t.Errorf("Expected %q to %q to add %q but got %q", tc.old, tc.new, tc.added, s)
+		}
+		if s := rangesString(d.Removed); s != tc.removed {
+			t.Errorf("Expected %q to %q to remove %q but got %q", tc.old, tc.new, tc.removed, s)
+		}
+		if s := rangesString(d.Unchanged); s != tc.unchanged {
+			t.Errorf("Expected %q to %q to leave %q unchanged but got %q", tc.old, tc.new, tc.unchanged, s)
+		}
+		if d.Changed() != (tc.added != "" || tc.removed != "") {
+			t.Errorf("Expected Changed for %q to %q to be %t", tc.old, tc.new, !d.Changed())
+		}
+	}
+
+	old, _ := NewConstraint("^1.2")
+	n, _ := NewConstraint("^1.2")
+	n.Deny = []*Version{MustParse("1.3.0")}
+	d := DiffConstraints(old, n)
+	expected := "removed: =1.3.0\nunchanged: >=1.2.0 <1.3.0 || >1.3.0 <2.0.0\n"
+	if d.String() != expected {
+		t.Errorf("Expected summary %q but got %q", expected, d.String())
+	}
+}
+
+func rangesString(r []Range) string {
+	s := ""
+	for i, x := range r {
+		if i > 0 {
+			s += " || "
+		}
+		s += x.String()
+	}
+	return s
+}
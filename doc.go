@@ -28,6 +28,18 @@ other versions, convert the version back into a string, and get the original
 string. For more details please see the documentation
 at https://godoc.org/github.com/Masterminds/semver.
 
+# Concurrency
+
+A Version is not changed after it is parsed. Methods such as IncPatch and
+SetPrerelease return a new Version rather than changing the one they are
+called on, so parsed versions can be shared, such as in a cache, and read
+from many goroutines. Only the decoding methods, such as UnmarshalJSON and
+Scan, change a Version and they should not be called on one that is shared.
+
+Constraints are not changed after they are parsed either and can be checked
+from many goroutines as long as the exported fields, such as Deny, are not
+changed while they are in use.
+
 # Sorting Semantic Versions
 
 A set of versions can be sorted using the `sort` package from the standard library.
@@ -0,0 +1,297 @@
+package semver
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// Explanation describes how a version was checked against constraints. It
+// has the result for each group of AND constraints separated by || and for
+// each constraint within the groups. It is returned by Constraints.Explain.
+type Explanation struct {
+	// Version is the version that was checked.
+	Version *Version
+
+	// Constraint is the string form of the constraints.
+	Constraint string
+
+	// Satisfied is true when the version satisfies the constraints. This is
+	// the same result as Check.
+	Satisfied bool
+
+	// Denied is true when the version is in the deny list of the
+	// constraints. A denied version never satisfies the constraints.
+	Denied bool
+
+	// Groups are the results for each group of AND constraints, in the order
+	// they are written by Constraints.String.
+	Groups []GroupExplanation
+}
+
+// GroupExplanation describes how a version was checked against a group of
+// AND constraints.
+type GroupExplanation struct {
+	// Constraint is the string form of the group.
+	Constraint string
+
+	// Satisfied is true when the version satisfies every constraint in the
+	// group.
+	Satisfied bool
+
+	// Reason is set when the group as a whole rejected the version, such as
+	// a prerelease with the PrereleaseSameTuple policy.
+	Reason error
+
+	// Comparators are the results for each constraint in the group.
+	Comparators []ComparatorExplanation
+}
+
+// ComparatorExplanation describes how a version was checked against a single
+// constraint.
+type ComparatorExplanation struct {
+	// Constraint is the string form of the constraint.
+	Constraint string
+
+	// Source is where the constraint came from, see
+	// Constraints.WithSource. It is empty when it was not recorded.
+	Source string
+
+	// Satisfied is true when the version satisfies the constraint.
+	Satisfied bool
+
+	// Reason describes why the version does not satisfy the constraint. It
+	// is nil when Satisfied is true.
+	Reason error
+}
+
+// Explain checks a version against the constraints and returns the result
+// of every group and constraint along with the reasons for any failures.
+// Unlike Validate, which stops at the first group that passes and reports a
+// flat list of errors, the explanation covers every constraint. This is
+// useful for showing people why a version was or was not accepted.
+func (cs Constraints) Explain(v *Version) *Explanation {
+	ex := &Explanation{
+		Version:    v,
+		Constraint: cs.String(),
+		Denied:     cs.denied(v),
+	}
+
+	for _, o := range cs.sortedGroups() {
+		g := GroupExplanation{
+			Satisfied:   true,
+			Comparators: make([]ComparatorExplanation, len(o)),
+		}
+
+		sameTuple := cs.PrereleasePolicy == PrereleaseSameTuple && v.pre != ""
+		if sameTuple && !hasSameTuple(o, v) {
+			g.Satisfied = false
+			g.Reason = &constraintError{v: v, reason: reasonPrereleaseTuple}
+		}
+
+		parts := make([]string, len(o))
+		for i, c := range o {
+			parts[i] = c.string()
+			ce := ComparatorExplanation{Constraint: parts[i], Source: c.source, Satisfied: true}
+			if ok, r := c.evaluate(v, !sameTuple); !ok {
+				ce.Satisfied = false
+				ce.Reason = &constraintError{v: v, orig: c.orig, reason: r}
+				g.Satisfied = false
+			}
+			g.Comparators[i] = ce
+		}
+		g.Constraint = strings.Join(parts, " ")
+
+		if g.Satisfied && !ex.Denied {
+			ex.Satisfied = true
+		}
+		ex.Groups = append(ex.Groups, g)
+	}
+
+	return ex
+}
+
+// String renders the explanation as an indented tree with a line for the
+// constraints, each group, and each constraint within the groups.
+func (ex *Explanation) String() string {
+	var sb strings.Builder
+	sb.WriteString(ex.Version.String())
+	if ex.Satisfied {
+		sb.WriteString(" satisfies ")
+	} else {
+		sb.WriteString(" does not satisfy ")
+	}
+	sb.WriteString(strconv.Quote(ex.Constraint))
+	if ex.Denied {
+		sb.WriteString(" (")
+		sb.WriteString(ex.Version.String())
+		sb.WriteString(" is denied)")
+	}
+
+	for k, g := range ex.Groups {
+		sb.WriteString("\n  group ")
+		sb.WriteString(strconv.Itoa(k + 1))
+		sb.WriteString(" ")
+		sb.WriteString(strconv.Quote(g.Constraint))
+		writeExplanationResult(&sb, g.Satisfied, g.Reason)
+
+		for _, c := range g.Comparators {
+			sb.WriteString("\n    ")
+			sb.WriteString(c.Constraint)
+			if c.Source != "" {
+				sb.WriteString(" from ")
+				sb.WriteString(c.Source)
+			}
+			writeExplanationResult(&sb, c.Satisfied, c.Reason)
+		}
+	}
+
+	return sb.String()
+}
+
+// writeExplanationResult writes the pass or fail result of a line of an
+// explanation along with the reason for a failure.
+func writeExplanationResult(sb *strings.Builder, ok bool, reason error) {
+	if ok {
+		sb.WriteString(": pass")
+		return
+	}
+
+	sb.WriteString(": fail")
+	if reason != nil {
+		sb.WriteString(" (")
+		sb.WriteString(reason.Error())
+		sb.WriteString(")")
+	}
+}
+
+// ExplanationSchema identifies the layout of the JSON form of an Explanation.
+// It is included in the JSON and changes if the layout ever changes in a way
+// that is not backwards compatible.
+const ExplanationSchema = "semver.explanation/v1"
+
+// explanationJSON is the JSON form of an Explanation. The fields are written
+// in the order they are declared.
+type explanationJSON struct {
+	Schema     string                 `json:"schema"`
+	Version    string                 `json:"version"`
+	Constraint string                 `json:"constraint"`
+	Satisfied  bool                   `json:"satisfied"`
+	Denied     bool                   `json:"denied"`
+	Groups     []groupExplanationJSON `json:"groups"`
+}
+
+type groupExplanationJSON struct {
+	Constraint  string                      `json:"constraint"`
+	Offset      int                         `json:"offset"`
+	Satisfied   bool                        `json:"satisfied"`
+	Reason      *reasonJSON                 `json:"reason,omitempty"`
+	Comparators []comparatorExplanationJSON `json:"comparators"`
+}
+
+type comparatorExplanationJSON struct {
+	Constraint string      `json:"constraint"`
+	Source     string      `json:"source,omitempty"`
+	Offset     int         `json:"offset"`
+	Satisfied  bool        `json:"satisfied"`
+	Reason     *reasonJSON `json:"reason,omitempty"`
+}
+
+type reasonJSON struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// MarshalJSON implements the json.Marshaler interface. The JSON is stable
+// across releases so programs, such as CI systems reporting why a version
+// was rejected, can rely on it:
+//
+//	{
+//	  "schema": "semver.explanation/v1",
+//	  "version": "2.4.0",
+//	  "constraint": "^1.2 || >=2.1 <2.3",
+//	  "satisfied": false,
+//	  "denied": false,
+//	  "groups": [
+//	    {
+//	      "constraint": "^1.2",
+//	      "offset": 0,
+//	      "satisfied": false,
+//	      "comparators": [
+//	        {"constraint": "^1.2", "offset": 0, "satisfied": false,
+//	         "reason": {"code": "major_mismatch", "message": "2.4.0 does not have same major version as 1.2"}}
+//	      ]
+//	    },
+//	    {
+//	      "constraint": ">=2.1 <2.3",
+//	      "offset": 8,
+//	      "satisfied": false,
+//	      "comparators": [
+//	        {"constraint": ">=2.1", "offset": 8, "satisfied": true},
+//	        {"constraint": "<2.3", "offset": 14, "satisfied": false,
+//	         "reason": {"code": "not_less", "message": "2.4.0 is greater than or equal to 2.3"}}
+//	      ]
+//	    }
+//	  ]
+//	}
+//
+// The offsets are the byte offsets of the groups and constraints in the
+// constraint string. The example is written with json.Encoder.SetEscapeHTML
+// set to false, otherwise < and > are written as \u003c and \u003e.
+// Comparators have a source when one was recorded with
+// Constraints.WithSource. Reason codes are
+// described by ReasonCode, while the messages are for people and may change.
+func (ex *Explanation) MarshalJSON() ([]byte, error) {
+	out := explanationJSON{
+		Schema:     ExplanationSchema,
+		Version:    ex.Version.String(),
+		Constraint: ex.Constraint,
+		Satisfied:  ex.Satisfied,
+		Denied:     ex.Denied,
+		Groups:     make([]groupExplanationJSON, len(ex.Groups)),
+	}
+
+	offset := 0
+	for k, g := range ex.Groups {
+		gj := groupExplanationJSON{
+			Constraint:  g.Constraint,
+			Offset:      offset,
+			Satisfied:   g.Satisfied,
+			Reason:      newReasonJSON(g.Reason),
+			Comparators: make([]comparatorExplanationJSON, len(g.Comparators)),
+		}
+
+		co := offset
+		for i, c := range g.Comparators {
+			gj.Comparators[i] = comparatorExplanationJSON{
+				Constraint: c.Constraint,
+				Source:     c.Source,
+				Offset:     co,
+				Satisfied:  c.Satisfied,
+				Reason:     newReasonJSON(c.Reason),
+			}
+			co += len(c.Constraint) + len(" ")
+		}
+
+		out.Groups[k] = gj
+		offset += len(g.Constraint) + len(" || ")
+	}
+
+	// Operators such as < and > are left as they are rather than escaped
+	// for HTML. Encoders that escape HTML still escape them.
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(out); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func newReasonJSON(err error) *reasonJSON {
+	if err == nil {
+		return nil
+	}
+	return &reasonJSON{Code: ReasonCode(err), Message: err.Error()}
+}
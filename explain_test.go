@@ -0,0 +1,187 @@
+package semver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConstraintsExplain(t *testing.T) {
+	constraints := []string{
+		"*",
+		"1.2.3",
+		"!=1.2.3",
+		">=1.2.3-alpha",
+		"~1.2.3",
+		"^0.2.3",
+		"1.2.3 - 2.0.0",
+		">=1.2.3, <2.0.0 || >=3.0.0-0",
+		"1.2.x || 2.x",
+	}
+
+	versions := []string{
+		"0.2.4",
+		"1.2.3",
+		"1.2.3-beta.1",
+		"1.3.0",
+		"2.0.0",
+		"3.0.0-beta",
+	}
+
+	for _, cr := range constraints {
+		c, err := NewConstraint(cr)
+		if err != nil {
+			t.Errorf("err: %s", err)
+			continue
+		}
+
+		for _, p := range []PrereleasePolicy{PrereleaseDefault, PrereleaseSameTuple} {
+			c.PrereleasePolicy = p
+			for _, vr := range versions {
+				v := MustParse(vr)
+				ex := c.Explain(v)
+				if e := c.Check(v); ex.Satisfied != e {
+					t.Errorf("Explain of '%s' (policy %d) with '%s' returned %t but Check returned %t", cr, p, vr, ex.Satisfied, e)
+				}
+
+				for _, g := range ex.Groups {
+					sat := g.Reason == nil
+					for _, cm := range g.Comparators {
+						if cm.Satisfied == (cm.Reason != nil) {
+							t.Errorf("Explain of '%s' with '%s' has comparator %q satisfied %t with reason %v", cr, vr, cm.Constraint, cm.Satisfied, cm.Reason)
+						}
+						sat = sat && cm.Satisfied
+					}
+					if sat != g.Satisfied {
+						t.Errorf("Explain of '%s' with '%s' has group %q satisfied %t but its comparators say %t", cr, vr, g.Constraint, g.Satisfied, sat)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestExplanationString(t *testing.T) {
+	c, err := NewConstraint(">=1.3, <1.4 || ^2.0.0")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	e := `1.2.3 does not satisfy ">=1.3 <1.4 || ^2.0.0"
+  group 1 ">=1.3 <1.4": fail
+    >=1.3: fail (1.2.3 is less than 1.3)
+    <1.4: pass
+  group 2 "^2.0.0": fail
+    ^2.0.0: fail (1.2.3 is less than 2.0.0)`
+	if a := c.Explain(MustParse("1.2.3")).String(); a != e {
+		t.Errorf("Expected explanation:\n%s\nbut got:\n%s", e, a)
+	}
+
+	e = `1.3.5 satisfies ">=1.3 <1.4 || ^2.0.0"
+  group 1 ">=1.3 <1.4": pass
+    >=1.3: pass
+    <1.4: pass
+  group 2 "^2.0.0": fail
+    ^2.0.0: fail (1.3.5 is less than 2.0.0)`
+	if a := c.Explain(MustParse("1.3.5")).String(); a != e {
+		t.Errorf("Expected explanation:\n%s\nbut got:\n%s", e, a)
+	}
+
+	c, _ = NewConstraint(">=1.2.3-alpha")
+	c.PrereleasePolicy = PrereleaseSameTuple
+	e = `2.0.0-beta does not satisfy ">=1.2.3-alpha"
+  group 1 ">=1.2.3-alpha": fail (2.0.0-beta is a prerelease version and no constraint has a prerelease on the same major, minor, and patch version)
+    >=1.2.3-alpha: pass`
+	if a := c.Explain(MustParse("2.0.0-beta")).String(); a != e {
+		t.Errorf("Expected explanation:\n%s\nbut got:\n%s", e, a)
+	}
+}
+
+func TestExplanationMarshalJSON(t *testing.T) {
+	c, err := NewConstraint("^1.2 || >=2.1 <2.3")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(c.Explain(MustParse("2.4.0"))); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	b := bytes.TrimSpace(buf.Bytes())
+	expected := `{"schema":"semver.explanation/v1","version":"2.4.0","constraint":"^1.2 || >=2.1 <2.3","satisfied":false,"denied":false,"groups":[` +
+		`{"constraint":"^1.2","offset":0,"satisfied":false,"comparators":[{"constraint":"^1.2","offset":0,"satisfied":false,"reason":{"code":"major_mismatch","message":"2.4.0 does not have same major version as 1.2"}}]},` +
+		`{"constraint":">=2.1 <2.3","offset":8,"satisfied":false,"comparators":[{"constraint":">=2.1","offset":8,"satisfied":true},{"constraint":"<2.3","offset":14,"satisfied":false,"reason":{"code":"not_less","message":"2.4.0 is greater than or equal to 2.3"}}]}]}`
+	if string(b) != expected {
+		t.Errorf("Expected JSON\n%s\nbut got\n%s", expected, b)
+	}
+
+	var out struct {
+		Groups []struct {
+			Constraint  string
+			Offset      int
+			Comparators []struct {
+				Constraint string
+				Offset     int
+			}
+		}
+		Constraint string
+	}
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, g := range out.Groups {
+		if !strings.HasPrefix(out.Constraint[g.Offset:], g.Constraint) {
+			t.Errorf("Expected group %q at offset %d of %q", g.Constraint, g.Offset, out.Constraint)
+		}
+		for _, cc := range g.Comparators {
+			if !strings.HasPrefix(out.Constraint[cc.Offset:], cc.Constraint) {
+				t.Errorf("Expected constraint %q at offset %d of %q", cc.Constraint, cc.Offset, out.Constraint)
+			}
+		}
+	}
+
+	c.PrereleasePolicy = PrereleaseSameTuple
+	c.Deny = []*Version{MustParse("2.2.0-beta.1")}
+	b, err = json.Marshal(c.Explain(MustParse("2.2.0-beta.1")))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !strings.Contains(string(b), `"denied":true`) || !strings.Contains(string(b), `"reason":{"code":"prerelease_tuple"`) {
+		t.Errorf("Expected a denied version with group reasons but got %s", b)
+	}
+}
+
+func TestReasonCode(t *testing.T) {
+	c, err := NewConstraint(">=1.2 <2 || ~3.1")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	_, errs := c.Validate(MustParse("2.5.0"))
+	var codes []string
+	for _, err := range errs {
+		codes = append(codes, ReasonCode(err))
+	}
+	if e := []string{"not_less", "less"}; !reflect.DeepEqual(codes, e) {
+		t.Errorf("Expected codes %q but got %q", e, codes)
+	}
+
+	_, errs = c.Validate(MustParse("1.5.0-beta.1"))
+	if len(errs) == 0 || ReasonCode(errs[0]) != "prerelease" {
+		t.Errorf("Expected a prerelease code but got %v", errs)
+	}
+
+	if code := ReasonCode(errors.New("other")); code != "" {
+		t.Errorf("Expected no code for other errors but got %q", code)
+	}
+	for r, code := range constraintReasonCodes {
+		if r != int(reasonNone) && code == "" {
+			t.Errorf("Expected a code for reason %d", r)
+		}
+	}
+}
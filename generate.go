@@ -0,0 +1,163 @@
+package semver
+
+import (
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Generate returns a random valid *Version. It implements the
+// testing/quick.Generator interface so *Version can be used as an argument
+// of functions checked with quick.Check. The size limits the major, minor,
+// and patch versions, except for an occasional very large one, along with
+// the number of prerelease and metadata identifiers.
+func (*Version) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(MustParse(GenerateVersionString(r, size, false)))
+}
+
+// Generate returns a random valid *Constraints. It implements the
+// testing/quick.Generator interface so *Constraints can be used as an
+// argument of functions checked with quick.Check.
+func (*Constraints) Generate(r *rand.Rand, size int) reflect.Value {
+	for {
+		c, err := NewConstraint(GenerateConstraintString(r, size, false))
+		if err == nil {
+			return reflect.ValueOf(c)
+		}
+	}
+}
+
+// GenerateVersionString returns a random semantic version, such as
+// 3.0.12-rc.1+b7. When nearValid is true the version is changed in a small way
+// that usually makes it invalid, such as adding a leading zero or an empty
+// identifier, which is useful for testing how invalid input is handled.
+func GenerateVersionString(r *rand.Rand, size int, nearValid bool) string {
+	var sb strings.Builder
+	sb.WriteString(genSegment(r, size))
+	sb.WriteByte('.')
+	sb.WriteString(genSegment(r, size))
+	sb.WriteByte('.')
+	sb.WriteString(genSegment(r, size))
+	if r.Intn(3) == 0 {
+		sb.WriteByte('-')
+		sb.WriteString(genIdentifiers(r, size, true))
+	}
+	if r.Intn(4) == 0 {
+		sb.WriteByte('+')
+		sb.WriteString(genIdentifiers(r, size, false))
+	}
+
+	if nearValid {
+		return mutate(r, sb.String())
+	}
+	return sb.String()
+}
+
+// GenerateConstraintString returns random constraints with one to three
+// groups of comparisons using each of the operators, wildcards, and hyphen
+// ranges, such as ^1.2 <1.9.0 || 2.x. When nearValid is true the constraints
+// are changed in a small way that usually makes them invalid.
+func GenerateConstraintString(r *rand.Rand, size int, nearValid bool) string {
+	ops := []string{"", "=", "!=", ">", "<", ">=", "<=", "=>", "=<", "~", "~>", "^"}
+	groups := make([]string, 1+r.Intn(3))
+	for k := range groups {
+		if r.Intn(6) == 0 {
+			groups[k] = genConstraintVersion(r, size) + " - " + genConstraintVersion(r, size)
+			continue
+		}
+
+		cs := make([]string, 1+r.Intn(3))
+		for i := range cs {
+			cs[i] = ops[r.Intn(len(ops))] + genConstraintVersion(r, size)
+		}
+		sep := " "
+		if r.Intn(2) == 0 {
+			sep = ", "
+		}
+		groups[k] = strings.Join(cs, sep)
+	}
+
+	s := strings.Join(groups, " || ")
+	if nearValid {
+		return mutate(r, s)
+	}
+	return s
+}
+
+// genConstraintVersion returns a version as written in a constraint, which
+// may be partial or use wildcards.
+func genConstraintVersion(r *rand.Rand, size int) string {
+	switch r.Intn(8) {
+	case 0:
+		return "*"
+	case 1:
+		return genSegment(r, size) + ".x"
+	case 2:
+		return genSegment(r, size) + "." + genSegment(r, size) + ".x"
+	case 3:
+		return genSegment(r, size) + "." + genSegment(r, size)
+	}
+	return GenerateVersionString(r, size, false)
+}
+
+// genSegment returns a random major, minor, or patch version.
+func genSegment(r *rand.Rand, size int) string {
+	if r.Intn(16) == 0 {
+		return strconv.FormatUint(r.Uint64(), 10)
+	}
+	return strconv.Itoa(r.Intn(size + 1))
+}
+
+// genIdentifiers returns dot separated prerelease or metadata identifiers.
+// Numeric prerelease identifiers do not have leading zeros.
+func genIdentifiers(r *rand.Rand, size int, pre bool) string {
+	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ-"
+	ids := make([]string, 1+r.Intn(1+size/10))
+	for i := range ids {
+		if r.Intn(2) == 0 {
+			ids[i] = strconv.Itoa(r.Intn(size + 1))
+			if !pre && r.Intn(4) == 0 {
+				ids[i] = "0" + ids[i]
+			}
+			continue
+		}
+
+		b := make([]byte, 1+r.Intn(8))
+		for j := range b {
+			if j > 0 && r.Intn(3) == 0 {
+				b[j] = num[r.Intn(len(num))]
+			} else {
+				b[j] = letters[r.Intn(len(letters))]
+			}
+		}
+		ids[i] = string(b)
+	}
+	return strings.Join(ids, ".")
+}
+
+// mutate makes a small change to a string that usually makes it invalid.
+func mutate(r *rand.Rand, s string) string {
+	i := r.Intn(len(s) + 1)
+	switch r.Intn(5) {
+	case 0:
+		// Remove a character.
+		if i == len(s) {
+			i--
+		}
+		return s[:i] + s[i+1:]
+	case 1:
+		// Insert a character that is not allowed.
+		bad := "_!@#$%&*()/\\?'\"é "
+		c := []rune(bad)[r.Intn(len([]rune(bad)))]
+		return s[:i] + string(c) + s[i:]
+	case 2:
+		// Add a leading zero.
+		return "0" + s
+	case 3:
+		// Add an empty identifier or segment.
+		return s[:i] + "." + s[i:]
+	}
+	// Repeat the string.
+	return s + s
+}
@@ -0,0 +1,70 @@
+package semver
+
+import (
+	"math/rand"
+	"testing"
+	"testing/quick"
+)
+
+func TestCompareLaws(t *testing.T) {
+	antisymmetric := func(a, b *Version) bool {
+		return a.Compare(b) == -b.Compare(a)
+	}
+	if err := quick.Check(antisymmetric, nil); err != nil {
+		t.Error(err)
+	}
+
+	reflexive := func(a *Version) bool {
+		return a.Compare(a) == 0 && a.Equal(a)
+	}
+	if err := quick.Check(reflexive, nil); err != nil {
+		t.Error(err)
+	}
+
+	transitive := func(a, b, c *Version) bool {
+		if a.Compare(b) <= 0 && b.Compare(c) <= 0 {
+			return a.Compare(c) <= 0
+		}
+		return true
+	}
+	if err := quick.Check(transitive, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Error(err)
+	}
+
+	sortKey := func(a, b *Version) bool {
+		return a.Compare(b) >= 0 || a.SortKey() <= b.SortKey()
+	}
+	if err := quick.Check(sortKey, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestConstraintsGenerate(t *testing.T) {
+	roundTrip := func(c *Constraints) bool {
+		return VerifyRoundTrip(c) == nil
+	}
+	if err := quick.Check(roundTrip, nil); err != nil {
+		t.Error(err)
+	}
+
+	compiled := func(c *Constraints, v *Version) bool {
+		return c.Check(v) == c.Compile().Check(v)
+	}
+	if err := quick.Check(compiled, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGenerateNearValid(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	invalid := 0
+	for i := 0; i < 1000; i++ {
+		if _, err := StrictNewVersion(GenerateVersionString(r, 10, true)); err != nil {
+			invalid++
+		}
+		_, _ = NewConstraint(GenerateConstraintString(r, 10, true))
+	}
+	if invalid == 0 {
+		t.Error("Expected near valid versions to be invalid at least some of the time")
+	}
+}
@@ -0,0 +1,114 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrModulePathMajor is returned when the major version suffix of a Go
+// module path does not match a version of the module.
+var ErrModulePathMajor = errors.New("Module path does not match the major version")
+
+// SplitModulePath splits a Go module path into the path without the major
+// version suffix and the suffix, such as example.com/mod and /v2 for
+// example.com/mod/v2. gopkg.in paths use a .vN suffix, such as .v3 for
+// gopkg.in/yaml.v3. The suffix is empty for paths without one. ok is false
+// when the path has an invalid suffix such as /v1, /v0, or /v02, or is a
+// gopkg.in path without a suffix.
+func SplitModulePath(path string) (prefix, pathMajor string, ok bool) {
+	if strings.HasPrefix(path, "gopkg.in/") {
+		i := strings.LastIndex(path, ".v")
+		if i < 0 {
+			return path, "", false
+		}
+		n := strings.TrimSuffix(path[i+2:], "-unstable")
+		if !isMajorSuffix(n, true) {
+			return path, "", false
+		}
+		return path[:i], path[i:], true
+	}
+
+	i := strings.LastIndex(path, "/")
+	if i < 0 || !strings.HasPrefix(path[i+1:], "v") {
+		return path, "", true
+	}
+	n := path[i+2:]
+	if n == "" || strings.Trim(n, "0123456789") != "" {
+		// Not a major version suffix, such as /vault.
+		return path, "", true
+	}
+	if !isMajorSuffix(n, false) {
+		return path, "", false
+	}
+	return path[:i], path[i:], true
+}
+
+// isMajorSuffix reports if n is a major version allowed in a suffix. Only
+// gopkg.in paths allow v0 and v1.
+func isMajorSuffix(n string, gopkg bool) bool {
+	if n == "" || strings.Trim(n, "0123456789") != "" || (len(n) > 1 && n[0] == '0') {
+		return false
+	}
+	return gopkg || (n != "0" && n != "1")
+}
+
+// CheckModulePath checks the major version suffix of a Go module path
+// against a version of the module following semantic import versioning.
+// Versions 2 and later need a /vN suffix matching the major version, such as
+// example.com/mod/v2 for 2.1.0, while versions 0 and 1 must not have one.
+// Versions with +incompatible metadata, which come from before the module
+// adopted Go modules, are allowed without a suffix and not allowed with one. gopkg.in paths need a
+// .vN suffix for every major version. An error wrapping ErrModulePathMajor
+// is returned when the path does not match.
+func CheckModulePath(path string, v *Version) error {
+	prefix, pathMajor, ok := SplitModulePath(path)
+	if !ok {
+		return fmt.Errorf("%w: %s has an invalid major version suffix", ErrModulePathMajor, path)
+	}
+
+	major := strconv.FormatUint(v.major, 10)
+	if pathMajor != "" && v.metadata == "incompatible" {
+		return fmt.Errorf("%w: %s has a major version suffix so %s can not be +incompatible", ErrModulePathMajor, path, v)
+	}
+	if strings.HasPrefix(prefix, "gopkg.in/") {
+		if strings.TrimSuffix(pathMajor, "-unstable") == ".v"+major {
+			return nil
+		}
+		return fmt.Errorf("%w: %s requires a .v%s suffix for %s", ErrModulePathMajor, path, major, v)
+	}
+
+	switch {
+	case pathMajor == "" && v.major < 2:
+		return nil
+	case pathMajor == "" && v.metadata == "incompatible":
+		return nil
+	case pathMajor == "/v"+major:
+		return nil
+	case v.major < 2:
+		return fmt.Errorf("%w: %s must not have a major version suffix for %s", ErrModulePathMajor, path, v)
+	}
+	return fmt.Errorf("%w: %s requires a /v%s suffix for %s", ErrModulePathMajor, path, major, v)
+}
+
+// ModulePath returns the Go module path for a version of the module, replacing
+// any major version suffix of path with the one the version requires. For
+// example, the path for 3.0.0 of example.com/mod/v2 is example.com/mod/v3 and
+// for 1.4.0 it is example.com/mod. An error is returned when the path has an
+// invalid suffix.
+func ModulePath(path string, v *Version) (string, error) {
+	prefix, _, ok := SplitModulePath(path)
+	if !ok {
+		return "", fmt.Errorf("%w: %s has an invalid major version suffix", ErrModulePathMajor, path)
+	}
+
+	major := strconv.FormatUint(v.major, 10)
+	switch {
+	case strings.HasPrefix(prefix, "gopkg.in/"):
+		return prefix + ".v" + major, nil
+	case v.major < 2:
+		return prefix, nil
+	}
+	return prefix + "/v" + major, nil
+}
@@ -0,0 +1,99 @@
+package semver
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSplitModulePath(t *testing.T) {
+	tests := []struct {
+		path      string
+		prefix    string
+		pathMajor string
+		ok        bool
+	}{
+		{"example.com/mod", "example.com/mod", "", true},
+		{"example.com/mod/v2", "example.com/mod", "/v2", true},
+		{"example.com/mod/v10", "example.com/mod", "/v10", true},
+		{"example.com/vault", "example.com/vault", "", true},
+		{"example.com/mod/v1", "example.com/mod/v1", "", false},
+		{"example.com/mod/v0", "example.com/mod/v0", "", false},
+		{"example.com/mod/v02", "example.com/mod/v02", "", false},
+		{"gopkg.in/yaml.v3", "gopkg.in/yaml", ".v3", true},
+		{"gopkg.in/yaml.v1", "gopkg.in/yaml", ".v1", true},
+		{"gopkg.in/check.v1-unstable", "gopkg.in/check", ".v1-unstable", true},
+		{"gopkg.in/yaml", "gopkg.in/yaml", "", false},
+		{"mod", "mod", "", true},
+	}
+
+	for _, tc := range tests {
+		prefix, pathMajor, ok := SplitModulePath(tc.path)
+		if prefix != tc.prefix || pathMajor != tc.pathMajor || ok != tc.ok {
+			t.Errorf("Expected %q to split into %q, %q, %t but got %q, %q, %t", tc.path, tc.prefix, tc.pathMajor, tc.ok, prefix, pathMajor, ok)
+		}
+	}
+}
+
+func TestCheckModulePath(t *testing.T) {
+	tests := []struct {
+		path    string
+		version string
+		ok      bool
+	}{
+		{"example.com/mod", "1.4.0", true},
+		{"example.com/mod", "0.3.0", true},
+		{"example.com/mod", "2.0.0", false},
+		{"example.com/mod", "2.0.0+incompatible", true},
+		{"example.com/mod/v2", "2.0.0+incompatible", false},
+		{"gopkg.in/yaml.v2", "2.0.0+incompatible", false},
+		{"example.com/mod/v2", "2.1.0", true},
+		{"example.com/mod/v2", "2.1.0-rc.1", true},
+		{"example.com/mod/v2", "3.0.0", false},
+		{"example.com/mod/v2", "1.4.0", false},
+		{"example.com/mod/v1", "1.4.0", false},
+		{"gopkg.in/yaml.v3", "3.0.1", true},
+		{"gopkg.in/yaml.v3", "2.4.0", false},
+		{"gopkg.in/check.v1", "1.0.0", true},
+		{"gopkg.in/check.v1-unstable", "1.0.0", true},
+	}
+
+	for _, tc := range tests {
+		err := CheckModulePath(tc.path, MustParse(tc.version))
+		if tc.ok && err != nil {
+			t.Errorf("Unexpected error for %s at %s: %s", tc.path, tc.version, err)
+		}
+		if !tc.ok && !errors.Is(err, ErrModulePathMajor) {
+			t.Errorf("Expected ErrModulePathMajor for %s at %s but got %v", tc.path, tc.version, err)
+		}
+	}
+}
+
+func TestModulePath(t *testing.T) {
+	tests := []struct {
+		path     string
+		version  string
+		expected string
+	}{
+		{"example.com/mod", "1.4.0", "example.com/mod"},
+		{"example.com/mod", "2.0.0", "example.com/mod/v2"},
+		{"example.com/mod/v2", "3.0.0", "example.com/mod/v3"},
+		{"example.com/mod/v2", "1.4.0", "example.com/mod"},
+		{"example.com/vault", "2.0.0", "example.com/vault/v2"},
+		{"gopkg.in/yaml.v2", "3.0.0", "gopkg.in/yaml.v3"},
+	}
+
+	for _, tc := range tests {
+		p, err := ModulePath(tc.path, MustParse(tc.version))
+		if err != nil {
+			t.Errorf("Unexpected error for %s at %s: %s", tc.path, tc.version, err)
+			continue
+		}
+		if p != tc.expected {
+			t.Errorf("Expected the path of %s at %s to be %s but got %s", tc.path, tc.version, tc.expected, p)
+		}
+	}
+
+	if _, err := ModulePath("example.com/mod/v1", MustParse("2.0.0")); !errors.Is(err, ErrModulePathMajor) {
+		t.Errorf("Expected ErrModulePathMajor for an invalid suffix but got %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package semver
+
+import (
+	"fmt"
+)
+
+// HistoryProblem identifies the kind of problem CheckHistory found.
+type HistoryProblem uint8
+
+const (
+	// Republished is a version that was already published, ignoring build
+	// metadata.
+	Republished HistoryProblem = iota + 1
+
+	// PrereleaseAfterRelease is a prerelease published after the release it
+	// leads up to, such as 1.2.0-rc.2 after 1.2.0.
+	PrereleaseAfterRelease
+
+	// NotIncreasing is a version lower than one published earlier in the
+	// same minor release line, such as 1.2.3 after 1.2.4.
+	NotIncreasing
+)
+
+func (p HistoryProblem) String() string {
+	switch p {
+	case Republished:
+		return "republished"
+	case PrereleaseAfterRelease:
+		return "prerelease after release"
+	case NotIncreasing:
+		return "not increasing"
+	}
+	return "unknown"
+}
+
+// HistoryViolation is a version in a publish history that breaks the order
+// versions are expected to be published in.
+type HistoryViolation struct {
+	// Index is the position of the version in the history.
+	Index int
+
+	// Version is the version that was published out of order.
+	Version *Version
+
+	// Earlier is the version published before it that it conflicts with.
+	Earlier *Version
+
+	// Problem is the kind of problem.
+	Problem HistoryProblem
+}
+
+func (h HistoryViolation) String() string {
+	return fmt.Sprintf("%s at %d: %s after %s", h.Problem, h.Index, h.Version, h.Earlier)
+}
+
+// CheckHistory checks the versions of a package in the order they were
+// published and returns the versions published out of order, for use in a
+// registry deciding if a version can be published. A version is out of
+// order when it was already published, when it is a prerelease of a release
+// that was already published, or when it is lower than an earlier version in
+// the same minor release line. Versions in older release lines, such as a
+// 1.2.5 fix after 1.3.0, are allowed. Nil versions are skipped.
+func CheckHistory(history []*Version) []HistoryViolation {
+	type line struct{ major, minor uint64 }
+	var out []HistoryViolation
+	highest := make(map[line]*Version)
+	// published has the index of the first version with each major, minor,
+	// patch, and prerelease. A release has an empty prerelease, so it is
+	// also the set of published releases.
+	published := make(map[historyKey]int)
+	for i, v := range history {
+		if v == nil {
+			continue
+		}
+
+		l := line{v.major, v.minor}
+		if p, earlier := historyProblem(history, published, v); p != 0 {
+			out = append(out, HistoryViolation{Index: i, Version: v, Earlier: earlier, Problem: p})
+		} else if h := highest[l]; h != nil && v.LessThan(h) {
+			out = append(out, HistoryViolation{Index: i, Version: v, Earlier: h, Problem: NotIncreasing})
+		}
+
+		if h := highest[l]; h == nil || v.GreaterThan(h) {
+			highest[l] = v
+		}
+		k := historyKey{v.major, v.minor, v.patch, v.pre}
+		if _, ok := published[k]; !ok {
+			published[k] = i
+		}
+	}
+	return out
+}
+
+// historyKey identifies a published version ignoring its build metadata.
+type historyKey struct {
+	major, minor, patch uint64
+	pre                 string
+}
+
+// historyProblem returns if v was already published or is a prerelease of a
+// published release, along with the earlier version. When both apply the
+// problem with the earliest version is returned.
+func historyProblem(history []*Version, published map[historyKey]int, v *Version) (HistoryProblem, *Version) {
+	same, republished := published[historyKey{v.major, v.minor, v.patch, v.pre}]
+	rel, released := published[historyKey{v.major, v.minor, v.patch, ""}]
+	released = released && v.pre != ""
+	switch {
+	case republished && (!released || same < rel):
+		return Republished, history[same]
+	case released:
+		return PrereleaseAfterRelease, history[rel]
+	}
+	return 0, nil
+}
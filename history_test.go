@@ -0,0 +1,42 @@
+package semver
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckHistory(t *testing.T) {
+	tests := []struct {
+		history  []string
+		expected []string
+	}{
+		{[]string{"1.0.0", "1.1.0-beta.1", "1.1.0", "1.1.1", "2.0.0"}, nil},
+		{[]string{"1.2.0", "1.3.0", "1.2.1", "2.0.0", "1.3.1"}, nil},
+		{[]string{"1.0.0", "1.1.0", "1.0.0"}, []string{"republished at 2: 1.0.0 after 1.0.0"}},
+		{[]string{"1.0.0+b1", "1.0.0+b2"}, []string{"republished at 1: 1.0.0+b2 after 1.0.0+b1"}},
+		{[]string{"1.2.0-rc.1", "1.2.0", "1.2.0-rc.2"}, []string{"prerelease after release at 2: 1.2.0-rc.2 after 1.2.0"}},
+		{[]string{"1.2.0-rc.1", "1.2.0", "1.2.0-rc.1"}, []string{"republished at 2: 1.2.0-rc.1 after 1.2.0-rc.1"}},
+		{[]string{"1.2.0", "1.2.0-rc.1", "1.2.0-rc.1"}, []string{"prerelease after release at 1: 1.2.0-rc.1 after 1.2.0", "prerelease after release at 2: 1.2.0-rc.1 after 1.2.0"}},
+		{[]string{"1.2.4", "1.2.3", "1.2.5"}, []string{"not increasing at 1: 1.2.3 after 1.2.4"}},
+		{[]string{"1.2.0-beta.2", "1.2.0-beta.1"}, []string{"not increasing at 1: 1.2.0-beta.1 after 1.2.0-beta.2"}},
+	}
+
+	for _, tc := range tests {
+		h := make([]*Version, len(tc.history))
+		for i, v := range tc.history {
+			h[i] = MustParse(v)
+		}
+
+		var got []string
+		for _, v := range CheckHistory(h) {
+			got = append(got, v.String())
+		}
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("Expected %v to have violations %q but got %q", tc.history, tc.expected, got)
+		}
+	}
+
+	if v := CheckHistory([]*Version{nil, MustParse("1.0.0"), nil}); len(v) != 0 {
+		t.Errorf("Expected nil versions to be skipped but got %v", v)
+	}
+}
@@ -0,0 +1,128 @@
+package semver
+
+import "sync"
+
+// An Interner hands out a single shared Version for each distinct version
+// string it sees. When the same versions are parsed over and over, such as
+// when reading a registry where many packages have the same versions, this
+// keeps only one copy of each in memory.
+//
+// Versions returned by an Interner are shared and must not be modified, for
+// example by calling UnmarshalJSON or Scan on them. An Interner is safe for
+// concurrent use and the zero value is ready to use. Entries are never
+// removed so an Interner grows with the number of distinct versions it sees.
+type Interner struct {
+	versions sync.Map
+}
+
+var defaultInterner Interner
+
+// Intern returns the shared Version for the original string of v from a
+// package wide Interner. The first Version seen for an original string is
+// the one that is shared.
+func Intern(v *Version) *Version {
+	return defaultInterner.Intern(v)
+}
+
+// Intern returns the shared Version with the same original string as v. If
+// there is not one yet v becomes the shared Version.
+func (in *Interner) Intern(v *Version) *Version {
+	if v == nil {
+		return nil
+	}
+
+	sv, _ := in.versions.LoadOrStore(v.original, v)
+	return sv.(*Version)
+}
+
+// NewVersion parses a version in the same manner as the package level
+// NewVersion function but returns the shared Version when the same string
+// has been parsed before. Only versions that parse successfully are shared.
+func (in *Interner) NewVersion(v string) (*Version, error) {
+	sv, ok := in.versions.Load(v)
+	if m := loadMetrics(); m != nil {
+		m.CacheLookup(CacheVersionInterner, ok)
+	}
+	if ok {
+		return sv.(*Version), nil
+	}
+
+	nv, err := NewVersion(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return in.Intern(nv), nil
+}
+
+// A ConstraintInterner hands out a single shared Constraints for each
+// distinct set of constraints it sees. Policy engines that parse the same
+// constraints from thousands of manifests keep only one copy of each in
+// memory, and constraints from the same interner can be compared by
+// pointer, such as for keys of caches of Check results.
+//
+// Constraints are the same when they have the same String form and
+// PrereleasePolicy, so ^1.2 || ~1.0 and ~1.0 || ^1.2 are shared but ^1.2 and
+// >=1.2 <2 are not. The first Constraints seen is the one shared, including
+// its source, see Constraints.WithSource. Constraints with a Deny list or a
+// Trace function are not shared.
+//
+// Constraints returned by a ConstraintInterner are shared and must not be
+// modified, for example by setting PrereleasePolicy or calling
+// UnmarshalText on them. A ConstraintInterner is safe for concurrent use and
+// the zero value is ready to use. Entries are never removed so it grows with
+// the number of distinct constraints it sees.
+type ConstraintInterner struct {
+	// Shared constraints by constraintKey.
+	constraints sync.Map
+
+	// Shared constraints by the string they were parsed from.
+	parsed sync.Map
+}
+
+// constraintKey identifies the constraints shared by a ConstraintInterner.
+type constraintKey struct {
+	str    string
+	policy PrereleasePolicy
+}
+
+var defaultConstraintInterner ConstraintInterner
+
+// InternConstraints returns the shared Constraints for cs from a package
+// wide ConstraintInterner.
+func InternConstraints(cs *Constraints) *Constraints {
+	return defaultConstraintInterner.Intern(cs)
+}
+
+// Intern returns the shared Constraints that are the same as cs. If there
+// are not any yet cs become the shared Constraints.
+func (in *ConstraintInterner) Intern(cs *Constraints) *Constraints {
+	if cs == nil || len(cs.Deny) > 0 || cs.Trace != nil {
+		return cs
+	}
+
+	sc, _ := in.constraints.LoadOrStore(constraintKey{cs.String(), cs.PrereleasePolicy}, cs)
+	return sc.(*Constraints)
+}
+
+// NewConstraint parses constraints in the same manner as the package level
+// NewConstraint function but returns the shared Constraints when the same,
+// or the same once parsed, constraints have been seen before. Strings that
+// have been parsed before are not parsed again.
+func (in *ConstraintInterner) NewConstraint(c string) (*Constraints, error) {
+	sc, ok := in.parsed.Load(c)
+	if m := loadMetrics(); m != nil {
+		m.CacheLookup(CacheConstraintInterner, ok)
+	}
+	if ok {
+		return sc.(*Constraints), nil
+	}
+
+	cs, err := NewConstraint(c)
+	if err != nil {
+		return nil, err
+	}
+
+	sc, _ = in.parsed.LoadOrStore(c, in.Intern(cs))
+	return sc.(*Constraints), nil
+}
@@ -0,0 +1,122 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestInterner(t *testing.T) {
+	var in Interner
+
+	v1, err := in.NewVersion("1.2.3-beta.1")
+	if err != nil {
+		t.Fatalf("Error parsing version: %s", err)
+	}
+
+	v2, err := in.NewVersion("1.2.3-beta.1")
+	if err != nil {
+		t.Fatalf("Error parsing version: %s", err)
+	}
+
+	if v1 != v2 {
+		t.Error("Expected the same version string to return a shared Version")
+	}
+
+	if v3 := in.Intern(MustParse("1.2.3-beta.1")); v3 != v1 {
+		t.Error("Expected Intern to return the shared Version")
+	}
+
+	// The original string is preserved so a leading v is a different entry.
+	v4, err := in.NewVersion("v1.2.3-beta.1")
+	if err != nil {
+		t.Fatalf("Error parsing version: %s", err)
+	}
+	if v4 == v1 {
+		t.Error("Expected versions with different original strings not to be shared")
+	}
+	if v4.Original() != "v1.2.3-beta.1" {
+		t.Errorf("Expected original to be preserved but got %q", v4.Original())
+	}
+
+	if _, err := in.NewVersion("foo"); err == nil {
+		t.Error("Expected error parsing invalid version")
+	}
+
+	if in.Intern(nil) != nil {
+		t.Error("Expected interning nil to return nil")
+	}
+}
+
+func TestIntern(t *testing.T) {
+	v1 := Intern(MustParse("4.5.6"))
+	v2 := Intern(MustParse("4.5.6"))
+
+	if v1 != v2 {
+		t.Error("Expected the same version string to return a shared Version")
+	}
+}
+
+func TestConstraintInterner(t *testing.T) {
+	var in ConstraintInterner
+
+	c1, err := in.NewConstraint("^1.2 || ~1.0")
+	if err != nil {
+		t.Fatalf("Error parsing constraint: %s", err)
+	}
+	c2, err := in.NewConstraint("^1.2 || ~1.0")
+	if err != nil {
+		t.Fatalf("Error parsing constraint: %s", err)
+	}
+	if c1 != c2 {
+		t.Error("Expected the same constraint string to return shared Constraints")
+	}
+
+	// The groups are in the same order once parsed.
+	c3, err := in.NewConstraint("~1.0 || ^1.2")
+	if err != nil {
+		t.Fatalf("Error parsing constraint: %s", err)
+	}
+	if c3 != c1 {
+		t.Error("Expected the same constraints to be shared")
+	}
+
+	if c4 := in.Intern(mustConstraint(t, ">=1.2 <2 || ~1.0")); c4 == c1 {
+		t.Error("Expected constraints written differently not to be shared")
+	}
+
+	c5 := mustConstraint(t, "^1.2 || ~1.0")
+	c5.PrereleasePolicy = PrereleaseSameTuple
+	if in.Intern(c5) != c5 {
+		t.Error("Expected constraints with a different prerelease policy not to be shared")
+	}
+
+	c6 := mustConstraint(t, "^1.2 || ~1.0")
+	c6.Deny = []*Version{MustParse("1.2.5")}
+	if in.Intern(c6) != c6 {
+		t.Error("Expected constraints with a deny list not to be shared")
+	}
+
+	if _, err := in.NewConstraint("foo"); err == nil {
+		t.Error("Expected error parsing invalid constraint")
+	}
+	if in.Intern(nil) != nil {
+		t.Error("Expected interning nil to return nil")
+	}
+}
+
+func TestInternConstraints(t *testing.T) {
+	c1 := InternConstraints(mustConstraint(t, ">=4.5.6"))
+	c2 := InternConstraints(mustConstraint(t, ">=4.5.6"))
+
+	if c1 != c2 {
+		t.Error("Expected the same constraints to be shared")
+	}
+}
+
+func mustConstraint(t *testing.T, c string) *Constraints {
+	t.Helper()
+	cs, err := NewConstraint(c)
+	if err != nil {
+		t.Fatalf("Error parsing constraint: %s", err)
+	}
+	return cs
+}
@@ -0,0 +1,269 @@
+package semver
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Intersection returns constraints allowing only the versions that both a
+// and b allow. Each group of AND constraints in a is combined with each group
+// in b. Combined groups whose bounds can not be met together, such as
+// >=2.1.0 with <2.0.0, are left out. When nothing is left the result has no
+// groups and does not allow any version, see IsEmpty.
+//
+// The bounds used to leave out groups are the lowest and highest versions the
+// constraints in a group allow. Groups that are empty for other reasons,
+// such as =1.2.3 with !=1.2.3, are kept and simply never match.
+//
+// The result has the prerelease policy of a and the deny lists of both.
+func Intersection(a, b *Constraints) *Constraints {
+	return IntersectionAll([]*Constraints{a, b})
+}
+
+// IntersectionAll returns constraints allowing only the versions that all of
+// the constraints allow, such as the requirements dependency resolution has
+// for one package. It is the same as calling Intersection on each of them in
+// turn but the groups are only combined, rather than parsed and written, for
+// each one, groups that are the same are combined once, and it stops early
+// when nothing is left.
+//
+// The result has the prerelease policy of the first constraints and the deny
+// lists of all of them. Nil constraints are skipped. When there are none the
+// result is *.
+func IntersectionAll(cs []*Constraints) *Constraints {
+	res, _ := intersectAll(cs, false)
+	return res
+}
+
+// IntersectionConflict describes bounds that can not be met together, such
+// as the upper bound <2.0.0 of ^1 and the lower bound >=2.1.0. It is
+// returned by ExplainIntersection.
+type IntersectionConflict struct {
+	// Lower is the lower bound, such as >=2.1.0.
+	Lower string
+
+	// LowerSource is where the lower bound came from, see
+	// Constraints.WithSource. It is empty when it was not recorded.
+	LowerSource string
+
+	// LowerConstraint is the constraint the lower bound comes from, such as
+	// ^2.1, and LowerIndex the index of its constraints in those passed to
+	// ExplainIntersection.
+	LowerConstraint string
+	LowerIndex      int
+
+	// Upper is the upper bound, such as <2.0.0.
+	Upper string
+
+	// UpperSource is where the upper bound came from, see
+	// Constraints.WithSource. It is empty when it was not recorded.
+	UpperSource string
+
+	// UpperConstraint is the constraint the upper bound comes from, such as
+	// ^1, and UpperIndex the index of its constraints in those passed to
+	// ExplainIntersection.
+	UpperConstraint string
+	UpperIndex      int
+}
+
+// String describes the conflict, such as "upper bound <2.0.0 (^1) from
+// constraints 0 excludes lower bound >=2.1.0 from constraints 1". The
+// sources of the bounds are used in place of the indexes when they were
+// recorded, such as "upper bound <2.0.0 (^1) from serviceB".
+func (c IntersectionConflict) String() string {
+	return "upper bound " + conflictBound(c.Upper, c.UpperConstraint) + " from " + conflictSource(c.UpperSource, c.UpperIndex) +
+		" excludes lower bound " + conflictBound(c.Lower, c.LowerConstraint) + " from " + conflictSource(c.LowerSource, c.LowerIndex)
+}
+
+// conflictSource writes where a bound came from.
+func conflictSource(source string, index int) string {
+	if source != "" {
+		return source
+	}
+	return "constraints " + strconv.Itoa(index)
+}
+
+// conflictBound writes a bound along with the constraint it comes from when
+// they are written differently.
+func conflictBound(bound, constraint string) string {
+	if bound == constraint {
+		return bound
+	}
+	return bound + " (" + constraint + ")"
+}
+
+// ExplainIntersection is IntersectionAll that also describes why the result
+// is empty. When it is, there is a conflict for each combination of groups
+// of AND constraints that was left out, such as ^1 with ^2.1. This helps
+// resolvers report which requirements can not be met together rather than
+// just that none can. When the result is not empty there are no conflicts.
+//
+// Only conflicts between bounds are described. Like IntersectionAll, groups
+// that are empty for other reasons are kept and do not cause a conflict.
+func ExplainIntersection(cs []*Constraints) (*Constraints, []IntersectionConflict) {
+	return intersectAll(cs, true)
+}
+
+// intersectAll is IntersectionAll that also returns the conflicts of the
+// groups it left out when the result is empty and explain is true.
+func intersectAll(cs []*Constraints, explain bool) (*Constraints, []IntersectionConflict) {
+	// The index of the constraints each constraint is from.
+	var from map[*constraint]int
+	if explain {
+		from = make(map[*constraint]int)
+		for i, c := range cs {
+			if c == nil {
+				continue
+			}
+			for _, o := range c.constraints {
+				for _, cc := range o {
+					if _, ok := from[cc]; !ok {
+						from[cc] = i
+					}
+				}
+			}
+		}
+	}
+
+	var conflicts []IntersectionConflict
+	conflictSeen := make(map[IntersectionConflict]bool)
+	var or [][]*constraint
+	var first *Constraints
+	var deny []*Version
+	for _, c := range cs {
+		if c == nil {
+			continue
+		}
+		deny = append(deny, c.Deny...)
+		if first == nil {
+			first = c
+			or = c.constraints
+			continue
+		}
+		if len(or) == 0 {
+			continue
+		}
+
+		var next [][]*constraint
+		seen := make(map[string]bool)
+		for _, ga := range or {
+			for _, gb := range c.constraints {
+				g := mergeGroups(ga, gb)
+				if !groupSatisfiable(g) {
+					if explain {
+						if c := groupConflict(g, from); !conflictSeen[c] {
+							conflictSeen[c] = true
+							conflicts = append(conflicts, c)
+						}
+					}
+					continue
+				}
+				if k := groupKey(g); !seen[k] {
+					seen[k] = true
+					next = append(next, g)
+				}
+			}
+		}
+		or = next
+	}
+
+	if first == nil {
+		res, _ := NewConstraint("*")
+		return res, nil
+	}
+
+	res := newConstraints(or)
+	res.PrereleasePolicy = first.PrereleasePolicy
+	if len(deny) > 0 {
+		res.Deny = deny
+	}
+	if len(or) > 0 {
+		conflicts = nil
+	}
+	return res, conflicts
+}
+
+// groupConflict describes why the bounds of a group can not be met, see
+// groupSatisfiable. from has the index of the constraints each constraint
+// is from.
+func groupConflict(o []*constraint, from map[*constraint]int) IntersectionConflict {
+	var lc, hc *constraint
+	var lo, hi *Version
+	var loInc, hiInc bool
+	for _, c := range o {
+		l, h, linc, hinc := c.bounds()
+		if l != nil && (lo == nil || l.GreaterThan(lo) || (l.Equal(lo) && !linc)) {
+			lo, loInc, lc = l, linc, c
+		}
+		if h != nil && (hi == nil || h.LessThan(hi) || (h.Equal(hi) && !hinc)) {
+			hi, hiInc, hc = h, hinc, c
+		}
+	}
+
+	lower := ">" + lo.String()
+	if loInc {
+		lower = ">=" + lo.String()
+	}
+	upper := "<" + hi.String()
+	if hiInc {
+		upper = "<=" + hi.String()
+	}
+	return IntersectionConflict{
+		Lower:           lower,
+		LowerSource:     lc.source,
+		LowerConstraint: lc.string(),
+		LowerIndex:      from[lc],
+		Upper:           upper,
+		UpperSource:     hc.source,
+		UpperConstraint: hc.string(),
+		UpperIndex:      from[hc],
+	}
+}
+
+// IsEmpty reports if the constraints do not have any groups, such as the
+// result of an Intersection of constraints that do not overlap. Empty
+// constraints do not allow any version.
+func (cs Constraints) IsEmpty() bool {
+	return len(cs.constraints) == 0
+}
+
+// mergeGroups returns the AND of two groups leaving out constraints that are
+// written the same way as one already in the group, see constraint.key.
+func mergeGroups(a, b []*constraint) []*constraint {
+	g := make([]*constraint, 0, len(a)+len(b))
+	seen := make(map[string]bool, len(a)+len(b))
+	for _, o := range [][]*constraint{a, b} {
+		for _, c := range o {
+			s := c.key()
+			if seen[s] {
+				continue
+			}
+			seen[s] = true
+			g = append(g, c)
+		}
+	}
+	return g
+}
+
+// groupKey identifies a group by its constraints regardless of their order.
+func groupKey(o []*constraint) string {
+	s := make([]string, len(o))
+	for i, c := range o {
+		s[i] = c.key()
+	}
+	sort.Strings(s)
+	return strings.Join(s, " ")
+}
+
+// groupSatisfiable reports if the bounds of a group leave room for a version.
+func groupSatisfiable(o []*constraint) bool {
+	lo, hi, loInclusive, hiInclusive := groupBounds(o)
+	if lo == nil || hi == nil {
+		return true
+	}
+	if d := lo.Compare(hi); d > 0 || (d == 0 && !(loInclusive && hiInclusive)) {
+		return false
+	}
+	return true
+}
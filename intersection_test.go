@@ -0,0 +1,188 @@
+package semver
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIntersection(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected string
+		empty    bool
+	}{
+		{"^2", ">=2.3 <2.6", "^2 >=2.3 <2.6", false},
+		{"^1.2.3", "^1.2.3", "^1.2.3", false},
+		{"^1 || ^2", "~1.4 || >=2.5", "^1 ~1.4 || ^2 >=2.5", false},
+		{"<2.0.0", ">=2.1.0", "", true},
+		{"<=2.0.0", ">=2.0.0", "<=2.0.0 >=2.0.0", false},
+		{"<2.0.0", ">=2.0.0", "", true},
+		{"1.x", "1.0.0", "1.x 1.0.0", false},
+		{">1.0.0", "<=1.0.0", "", true},
+		{">=1.0.0", "<1.0.0", "", true},
+		{">1.x", "<2.0.0", "", true},
+	}
+
+	for _, tc := range tests {
+		a, err := NewConstraint(tc.a)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		b, err := NewConstraint(tc.b)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		c := Intersection(a, b)
+		if c.String() != tc.expected {
+			t.Errorf("Expected %q and %q to intersect as %q but got %q", tc.a, tc.b, tc.expected, c)
+		}
+		if c.IsEmpty() != tc.empty {
+			t.Errorf("Expected intersection of %q and %q empty to be %t", tc.a, tc.b, tc.empty)
+		}
+
+		for _, vr := range []string{"0.9.0", "1.0.0", "1.4.2", "1.5.0", "2.0.0", "2.3.1", "2.5.0", "2.7.0", "3.0.0"} {
+			v := MustParse(vr)
+			if e := a.Check(v) && b.Check(v); c.Check(v) != e {
+				t.Errorf("Expected intersection of %q and %q with %s to be %t", tc.a, tc.b, vr, e)
+			}
+		}
+	}
+}
+
+func TestIntersectionAll(t *testing.T) {
+	tests := []struct {
+		constraints []string
+		expected    string
+		empty       bool
+	}{
+		{[]string{"^1.2", ">=1.4", "<1.8 || >=2.5", "!=1.5.0"}, "^1.2 >=1.4 <1.8 !=1.5.0", false},
+		{[]string{"^1 || ^2", "^1 || ^2", "^1 || ^2"}, "^1 || ^2", false},
+		{[]string{"^1", "^2", "^1.2"}, "", true},
+		{[]string{"~1.4"}, "~1.4", false},
+		{nil, "*", false},
+	}
+
+	for _, tc := range tests {
+		var cs []*Constraints
+		for _, s := range tc.constraints {
+			c, err := NewConstraint(s)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			cs = append(cs, c, nil)
+		}
+
+		c := IntersectionAll(cs)
+		if c.String() != tc.expected {
+			t.Errorf("Expected %q to intersect as %q but got %q", tc.constraints, tc.expected, c)
+		}
+		if c.IsEmpty() != tc.empty {
+			t.Errorf("Expected intersection of %q empty to be %t", tc.constraints, tc.empty)
+		}
+
+		for _, vr := range []string{"1.0.0", "1.4.2", "1.5.0", "1.7.0", "1.9.0", "2.0.0", "2.6.0", "3.0.0"} {
+			v := MustParse(vr)
+			e := true
+			for _, o := range cs {
+				if o != nil && !o.Check(v) {
+					e = false
+				}
+			}
+			if c.Check(v) != e {
+				t.Errorf("Expected intersection of %q with %s to be %t", tc.constraints, vr, e)
+			}
+		}
+	}
+
+	a, _ := NewConstraint("^1")
+	a.PrereleasePolicy = PrereleaseSameTuple
+	a.Deny = []*Version{MustParse("1.2.0")}
+	b, _ := NewConstraint("^1.1")
+	b.Deny = []*Version{MustParse("1.3.0")}
+	c := IntersectionAll([]*Constraints{a, b})
+	if c.PrereleasePolicy != PrereleaseSameTuple || len(c.Deny) != 2 {
+		t.Errorf("Expected the policy of the first and both deny lists but got %v and %v", c.PrereleasePolicy, c.Deny)
+	}
+}
+
+func TestExplainIntersection(t *testing.T) {
+	tests := []struct {
+		constraints []string
+		conflicts   []string
+	}{
+		{[]string{"^1", ">=2.1.0"}, []string{"upper bound <2.0.0 (^1) from constraints 0 excludes lower bound >=2.1.0 from constraints 1"}},
+		{[]string{">1.6.0", "<=1.5.0"}, []string{"upper bound <=1.5.0 from constraints 1 excludes lower bound >1.6.0 from constraints 0"}},
+		{[]string{"^1 || ^3", "^2"}, []string{
+			"upper bound <2.0.0 (^1) from constraints 0 excludes lower bound >=2.0.0 (^2) from constraints 1",
+			"upper bound <3.0.0 (^2) from constraints 1 excludes lower bound >=3.0.0 (^3) from constraints 0",
+		}},
+		{[]string{"^1 || ^2", "^2"}, nil},
+		{[]string{"=1.2.3", "!=1.2.3"}, nil},
+	}
+
+	for _, tc := range tests {
+		var cs []*Constraints
+		for _, s := range tc.constraints {
+			c, err := NewConstraint(s)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			cs = append(cs, c)
+		}
+
+		c, conflicts := ExplainIntersection(cs)
+		if e := IntersectionAll(cs); c.String() != e.String() {
+			t.Errorf("Expected %q to intersect as %q but got %q", tc.constraints, e, c)
+		}
+		if len(conflicts) != len(tc.conflicts) {
+			t.Fatalf("Expected %d conflicts for %q but got %v", len(tc.conflicts), tc.constraints, conflicts)
+		}
+		for i, cf := range conflicts {
+			if cf.String() != tc.conflicts[i] {
+				t.Errorf("Expected conflict %q but got %q", tc.conflicts[i], cf)
+			}
+		}
+	}
+}
+
+func TestIntersectionSources(t *testing.T) {
+	a, _ := NewConstraint("^2")
+	b, _ := NewConstraint(">=1.5 <2 || ^3")
+	a = a.WithSource("serviceA")
+	b = b.WithSource("serviceB")
+
+	c, conflicts := ExplainIntersection([]*Constraints{a, b})
+	if !c.IsEmpty() {
+		t.Errorf("Expected the intersection to be empty but got %q", c)
+	}
+	expected := []string{
+		"upper bound <2.0.0 (<2) from serviceB excludes lower bound >=2.0.0 (^2) from serviceA",
+		"upper bound <3.0.0 (^2) from serviceA excludes lower bound >=3.0.0 (^3) from serviceB",
+	}
+	if len(conflicts) != len(expected) {
+		t.Fatalf("Expected %q but got %v", expected, conflicts)
+	}
+	for i, cf := range conflicts {
+		if cf.String() != expected[i] {
+			t.Errorf("Expected conflict %q but got %q", expected[i], cf)
+		}
+	}
+
+	u := Union(a, b)
+	if s := u.Sources(); len(s) != 2 || s[0] != "serviceA" || s[1] != "serviceB" {
+		t.Errorf("Expected the union to keep both sources but got %q", s)
+	}
+	if cs := u.Comparators(); cs[0].Source != "serviceA" {
+		t.Errorf("Expected the comparator to have its source but got %+v", cs[0])
+	}
+	if ex := u.Explain(MustParse("1.0.0")).String(); !strings.Contains(ex, "^2 from serviceA: fail") {
+		t.Errorf("Expected the explanation to show the source but got %s", ex)
+	}
+	if s := (Constraints{}).Sources(); s != nil {
+		t.Errorf("Expected no sources but got %q", s)
+	}
+	if i := Intersection(a, a); i.String() != "^2" || i.Sources()[0] != "serviceA" {
+		t.Errorf("Expected the intersection to keep the source but got %q", i.Sources())
+	}
+}
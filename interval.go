@@ -0,0 +1,315 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrInvalidRange is returned when a vulnerability range, such as OSV
+	// events or a vers range, can not be parsed.
+	ErrInvalidRange = errors.New("Invalid range")
+
+	// ErrUnsupportedRange is returned when constraints can not be expressed
+	// in a range format, such as a != constraint as OSV events.
+	ErrUnsupportedRange = errors.New("Constraints can not be expressed in the range format")
+)
+
+// interval is a contiguous range of versions. A nil bound is unbounded.
+type interval struct {
+	lo, hi       *Version
+	loInc, hiInc bool
+}
+
+// contains reports if the version is between the bounds of the interval.
+func (iv interval) contains(v *Version) bool {
+	if iv.lo != nil {
+		if d := v.Compare(iv.lo); d < 0 || (d == 0 && !iv.loInc) {
+			return false
+		}
+	}
+	if iv.hi != nil {
+		if d := v.Compare(iv.hi); d > 0 || (d == 0 && !iv.hiInc) {
+			return false
+		}
+	}
+	return true
+}
+
+// empty reports if the bounds of the interval leave no room for a version.
+func (iv interval) empty() bool {
+	if iv.lo == nil || iv.hi == nil {
+		return false
+	}
+	d := iv.lo.Compare(iv.hi)
+	return d > 0 || (d == 0 && !(iv.loInc && iv.hiInc))
+}
+
+// point reports if the interval holds a single version.
+func (iv interval) point() bool {
+	return iv.lo != nil && iv.hi != nil && iv.loInc && iv.hiInc && iv.lo.Equal(iv.hi)
+}
+
+func (iv interval) String() string {
+	switch {
+	case iv.point():
+		return "=" + iv.lo.String()
+	case iv.lo == nil && iv.hi == nil:
+		return "*"
+	}
+
+	var parts []string
+	if iv.lo != nil {
+		op := ">="
+		if !iv.loInc {
+			op = ">"
+		}
+		parts = append(parts, op+iv.lo.String())
+	}
+	if iv.hi != nil {
+		op := "<"
+		if iv.hiInc {
+			op = "<="
+		}
+		parts = append(parts, op+iv.hi.String())
+	}
+	return strings.Join(parts, " ")
+}
+
+// intervals returns the constraints as sorted intervals that do not overlap
+// along with the versions excluded from them by != constraints and the deny
+// list. A != constraint is only allowed when every group whose bounds
+// include the version also excludes it, so the excluded versions apply to
+// the intervals as a whole. Which prereleases are allowed is not captured.
+func (cs Constraints) intervals() ([]interval, []*Version, error) {
+	ivs := make([]interval, 0, len(cs.constraints))
+	nes := make([][]*Version, 0, len(cs.constraints))
+	for _, o := range cs.constraints {
+		iv, ex := groupInterval(o)
+		if iv.empty() {
+			continue
+		}
+		var ne []*Version
+		for _, e := range ex {
+			if !e.point() {
+				return nil, nil, fmt.Errorf("%w: excluding %s", ErrUnsupportedRange, e)
+			}
+			ne = append(ne, e.lo)
+		}
+		ivs = append(ivs, iv)
+		nes = append(nes, ne)
+	}
+
+	excluded := append([]*Version(nil), cs.Deny...)
+	for i, ne := range nes {
+		for _, v := range ne {
+			for j, iv := range ivs {
+				if j != i && iv.contains(v) && !containsVersion(nes[j], v) {
+					return nil, nil, fmt.Errorf("%w: != %s only applies to some of %s", ErrUnsupportedRange, v, cs)
+				}
+			}
+			excluded = append(excluded, v)
+		}
+	}
+
+	merged := mergeIntervals(ivs)
+
+	// Excluded versions at the bounds of an interval become exclusive bounds
+	// and those outside of every interval are not needed.
+	sort.Sort(Collection(excluded))
+	var ne []*Version
+	for _, v := range excluded {
+		if len(ne) > 0 && ne[len(ne)-1].Equal(v) {
+			continue
+		}
+		inside := false
+		for k := 0; k < len(merged); k++ {
+			iv := &merged[k]
+			if !iv.contains(v) {
+				continue
+			}
+			switch {
+			case iv.point():
+				merged = append(merged[:k], merged[k+1:]...)
+			case iv.lo != nil && iv.lo.Equal(v):
+				iv.loInc = false
+			case iv.hi != nil && iv.hi.Equal(v):
+				iv.hiInc = false
+			default:
+				inside = true
+			}
+			break
+		}
+		if inside {
+			ne = append(ne, v)
+		}
+	}
+
+	return merged, ne, nil
+}
+
+// intervalSet returns the constraints as sorted intervals that do not
+// overlap with the versions excluded by != constraints and the deny list
+// left out of them. Which prereleases are allowed is not captured.
+func (cs Constraints) intervalSet() []interval {
+	var ivs []interval
+	for _, o := range cs.constraints {
+		iv, ex := groupInterval(o)
+		ivs = append(ivs, subtractIntervals([]interval{iv}, ex)...)
+	}
+
+	deny := make([]interval, len(cs.Deny))
+	for i, v := range cs.Deny {
+		deny[i] = interval{lo: v, hi: v, loInc: true, hiInc: true}
+	}
+	return subtractIntervals(mergeIntervals(ivs), deny)
+}
+
+// groupInterval returns the interval of versions allowed by a group of AND
+// constraints and the intervals it excludes with != constraints.
+func groupInterval(o []*constraint) (interval, []interval) {
+	var iv interval
+	var ex []interval
+	for _, c := range o {
+		if c.origfunc == "!=" {
+			ex = append(ex, c.excluded())
+			continue
+		}
+
+		lo, hi, loInc, hiInc := c.bounds()
+		iv = intersectInterval(iv, interval{lo: lo, hi: hi, loInc: loInc, hiInc: hiInc})
+	}
+	return iv, ex
+}
+
+// excluded returns the interval of versions a != constraint does not allow,
+// such as 1.0.0 up to 2.0.0 for !=1.x.
+func (c *constraint) excluded() interval {
+	switch {
+	case c.minorDirty:
+		return interval{lo: c.con, hi: New(c.con.major+1, 0, 0, "", ""), loInc: true}
+	case c.patchDirty:
+		return interval{lo: c.con, hi: New(c.con.major, c.con.minor+1, 0, "", ""), loInc: true}
+	case c.dirty:
+		return interval{}
+	}
+	return interval{lo: c.con, hi: c.con, loInc: true, hiInc: true}
+}
+
+// intersectInterval returns the versions in both intervals. The result may
+// be empty.
+func intersectInterval(a, b interval) interval {
+	if b.lo != nil {
+		if a.lo == nil {
+			a.lo, a.loInc = b.lo, b.loInc
+		} else if d := b.lo.Compare(a.lo); d > 0 || (d == 0 && !b.loInc) {
+			a.lo, a.loInc = b.lo, b.loInc
+		}
+	}
+	if b.hi != nil {
+		if a.hi == nil {
+			a.hi, a.hiInc = b.hi, b.hiInc
+		} else if d := b.hi.Compare(a.hi); d < 0 || (d == 0 && !b.hiInc) {
+			a.hi, a.hiInc = b.hi, b.hiInc
+		}
+	}
+	return a
+}
+
+// intersectIntervals returns the versions in both sets of intervals as
+// sorted intervals that do not overlap.
+func intersectIntervals(a, b []interval) []interval {
+	var out []interval
+	for _, x := range a {
+		for _, y := range b {
+			if iv := intersectInterval(x, y); !iv.empty() {
+				out = append(out, iv)
+			}
+		}
+	}
+	return mergeIntervals(out)
+}
+
+// subtractIntervals returns the versions in a that are not in b as sorted
+// intervals that do not overlap.
+func subtractIntervals(a, b []interval) []interval {
+	out := make([]interval, 0, len(a))
+	for _, x := range a {
+		if !x.empty() {
+			out = append(out, x)
+		}
+	}
+
+	for _, y := range b {
+		var next []interval
+		for _, x := range out {
+			// The parts of x below and above y.
+			if y.lo != nil {
+				if iv := intersectInterval(x, interval{hi: y.lo, hiInc: !y.loInc}); !iv.empty() {
+					next = append(next, iv)
+				}
+			}
+			if y.hi != nil {
+				if iv := intersectInterval(x, interval{lo: y.hi, loInc: !y.hiInc}); !iv.empty() {
+					next = append(next, iv)
+				}
+			}
+		}
+		out = next
+	}
+	return mergeIntervals(out)
+}
+
+// mergeIntervals sorts the intervals by their lower bound and joins those
+// that overlap or touch.
+func mergeIntervals(ivs []interval) []interval {
+	sort.SliceStable(ivs, func(i, j int) bool {
+		a, b := ivs[i], ivs[j]
+		if a.lo == nil || b.lo == nil {
+			return a.lo == nil && b.lo != nil
+		}
+		if d := a.lo.Compare(b.lo); d != 0 {
+			return d < 0
+		}
+		return a.loInc && !b.loInc
+	})
+
+	var out []interval
+	for _, iv := range ivs {
+		if len(out) == 0 {
+			out = append(out, iv)
+			continue
+		}
+
+		cur := &out[len(out)-1]
+		if cur.hi != nil && iv.lo != nil {
+			d := iv.lo.Compare(cur.hi)
+			if d > 0 || (d == 0 && !cur.hiInc && !iv.loInc) {
+				out = append(out, iv)
+				continue
+			}
+		}
+
+		switch {
+		case cur.hi == nil:
+		case iv.hi == nil:
+			cur.hi, cur.hiInc = nil, false
+		default:
+			if d := iv.hi.Compare(cur.hi); d > 0 || (d == 0 && iv.hiInc) {
+				cur.hi, cur.hiInc = iv.hi, iv.hiInc
+			}
+		}
+	}
+	return out
+}
+
+func containsVersion(vs []*Version, v *Version) bool {
+	for _, o := range vs {
+		if o.Equal(v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package semver
+
+// AdmitsPrereleases reports if any prerelease version can satisfy the
+// constraints with their prerelease policy, such as for >=1.2.0-beta.1. With
+// the default policy a group of AND constraints only admits prereleases when
+// all of its constraints are looking for them.
+func (cs Constraints) AdmitsPrereleases() bool {
+	for _, o := range cs.constraints {
+		if groupSatisfiable(o) && groupAcceptsPrerelease(o, cs.PrereleasePolicy) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExact reports if the constraints allow a single version, such as =1.2.3
+// or >=1.2.3 <=1.2.3, and returns the version when they do.
+func (cs Constraints) IsExact() (bool, *Version) {
+	ivs := cs.intervalSet()
+	if len(ivs) != 1 || !ivs[0].point() {
+		return false, nil
+	}
+	v := *ivs[0].lo
+	return true, &v
+}
+
+// IsWildcard reports if the constraints allow every release version, such as
+// *, x, or >=0.0.0. Prereleases may still be rejected.
+func (cs Constraints) IsWildcard() bool {
+	ivs := cs.intervalSet()
+	if len(ivs) != 1 || ivs[0].hi != nil {
+		return false
+	}
+	lo := ivs[0].lo
+	return lo == nil || (ivs[0].loInc && lo.Equal(New(0, 0, 0, "", "")))
+}
+
+// HasUpperBound reports if there is a version above which the constraints
+// allow nothing, such as for ^1.2 or <2 but not for >=1.2. Empty constraints
+// have an upper bound as they do not allow any version.
+func (cs Constraints) HasUpperBound() bool {
+	ivs := cs.intervalSet()
+	return len(ivs) == 0 || ivs[len(ivs)-1].hi != nil
+}
@@ -0,0 +1,69 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestIntrospection(t *testing.T) {
+	tests := []struct {
+		constraint string
+		pre        bool
+		exact      string
+		wildcard   bool
+		upper      bool
+	}{
+		{"^1.2", false, "", false, true},
+		{">=1.2.0-beta.1", true, "", false, false},
+		{">=1.2.0-beta.1 <2", false, "", false, true},
+		{"^1 || >=2.0.0-0 <2.1.0-0", true, "", false, true},
+		{"=1.2.3", false, "1.2.3", false, true},
+		{"1.2.3-beta", true, "1.2.3-beta", false, true},
+		{">=1.2.3 <=1.2.3", false, "1.2.3", false, true},
+		{"1.2.3 || 1.2.3", false, "1.2.3", false, true},
+		{"1.2.3 || 1.2.4", false, "", false, true},
+		{"*", false, "", true, false},
+		{"x || ^1", false, "", true, false},
+		{">=0.0.0", false, "", true, false},
+		{">=0.1.0", false, "", false, false},
+		{"* !=1.2.3", false, "", false, false},
+		{"<2 || >=3", false, "", false, false},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		if a := c.AdmitsPrereleases(); a != tc.pre {
+			t.Errorf("Expected %q to admit prereleases to be %t", tc.constraint, tc.pre)
+		}
+		exact, v := c.IsExact()
+		if exact != (tc.exact != "") || (exact && v.String() != tc.exact) {
+			t.Errorf("Expected %q exact to be %q but got %t and %v", tc.constraint, tc.exact, exact, v)
+		}
+		if w := c.IsWildcard(); w != tc.wildcard {
+			t.Errorf("Expected %q wildcard to be %t", tc.constraint, tc.wildcard)
+		}
+		if u := c.HasUpperBound(); u != tc.upper {
+			t.Errorf("Expected %q upper bound to be %t", tc.constraint, tc.upper)
+		}
+	}
+
+	c, _ := NewConstraint(">=1.2.0-beta.1")
+	c.PrereleasePolicy = PrereleaseSameTuple
+	if !c.AdmitsPrereleases() {
+		t.Error("Expected same tuple policy to admit prereleases")
+	}
+	c, _ = NewConstraint(">=1.2.0")
+	c.PrereleasePolicy = PrereleaseSameTuple
+	if c.AdmitsPrereleases() {
+		t.Error("Expected same tuple policy without a prerelease to not admit them")
+	}
+
+	c, _ = NewConstraint("1.2.3 || 1.2.4")
+	c.Deny = []*Version{MustParse("1.2.4")}
+	if exact, v := c.IsExact(); !exact || v.String() != "1.2.3" {
+		t.Errorf("Expected deny list to leave 1.2.3 exact but got %t and %v", exact, v)
+	}
+}
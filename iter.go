@@ -0,0 +1,89 @@
+//go:build go1.23
+
+package semver
+
+import (
+	"iter"
+)
+
+// FilterSeq returns an iterator over the versions from seq that satisfy the
+// constraints, in the order seq yields them. Versions are checked as they
+// are requested so the matches are never collected into a slice.
+func (cs Constraints) FilterSeq(seq iter.Seq[*Version]) iter.Seq[*Version] {
+	return func(yield func(*Version) bool) {
+		pre := cs.prereleaseGroups()
+		for v := range seq {
+			if v != nil && cs.checkGroups(v, pre) && !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Versions returns an iterator over the versions of a sorted collection that
+// satisfy the constraints, in order. This is useful with constraints such as
+// 1.2.x and a very large catalog of versions. Rather than checking every
+// version, binary search is used to skip to the start of each range of
+// versions the constraints allow, and the iteration stops at the end of the
+// last one. The collection must be sorted, such as with sort.Sort, and must
+// not contain nil versions.
+func (cs Constraints) Versions(sorted Collection) iter.Seq[*Version] {
+	return func(yield func(*Version) bool) {
+		pre := cs.prereleaseGroups()
+		i := 0
+		for _, iv := range cs.intervalSet() {
+			if iv.lo != nil {
+				if start := sorted.Search(iv.lo); start > i {
+					i = start
+				}
+			}
+
+			for ; i < len(sorted); i++ {
+				v := sorted[i]
+				if iv.hi != nil {
+					if d := v.Compare(iv.hi); d > 0 || (d == 0 && !iv.hiInc) {
+						break
+					}
+				}
+				if cs.checkGroups(v, pre) && !yield(v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Classify returns an iterator over the versions from seq paired with the
+// names of the components whose constraints they satisfy, in the order the
+// components are given. This classifies a stream of versions, such as the
+// tags of a repository, against many named constraints in a single pass.
+// Versions that satisfy none of the constraints are yielded with a nil
+// slice. Nil versions and components without constraints are skipped. The
+// constraints are compiled once, see Constraints.Compile.
+func Classify(seq iter.Seq[*Version], components ...Component) iter.Seq2[*Version, []string] {
+	return func(yield func(*Version, []string) bool) {
+		names := make([]string, 0, len(components))
+		compiled := make([]*CompiledConstraints, 0, len(components))
+		for _, c := range components {
+			if c.Constraint != nil {
+				names = append(names, c.Name)
+				compiled = append(compiled, c.Constraint.Compile())
+			}
+		}
+
+		for v := range seq {
+			if v == nil {
+				continue
+			}
+			var matched []string
+			for i, cc := range compiled {
+				if cc.Check(v) {
+					matched = append(matched, names[i])
+				}
+			}
+			if !yield(v, matched) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+//go:build go1.23
+
+package semver
+
+import (
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestConstraintsFilterSeq(t *testing.T) {
+	c, err := NewConstraint("1.2.x")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	vs := Collection{
+		MustParse("1.3.0"),
+		MustParse("1.2.4"),
+		nil,
+		MustParse("1.2.0-beta.1"),
+		MustParse("1.2.0"),
+		MustParse("1.2.9"),
+	}
+
+	var got []string
+	for v := range c.FilterSeq(slices.Values(vs)) {
+		got = append(got, v.String())
+		if len(got) == 2 {
+			break
+		}
+	}
+	if e := []string{"1.2.4", "1.2.0"}; !reflect.DeepEqual(got, e) {
+		t.Errorf("Expected %q but got %q", e, got)
+	}
+}
+
+func TestConstraintsVersions(t *testing.T) {
+	var catalog Collection
+	for _, s := range []string{
+		"0.9.0", "1.0.0", "1.1.5", "1.2.0-beta.1", "1.2.0", "1.2.3", "1.2.10",
+		"1.3.0", "1.4.1", "2.0.0-rc.1", "2.0.0", "2.1.0", "3.0.0",
+	} {
+		catalog = append(catalog, MustParse(s))
+	}
+	sort.Sort(catalog)
+
+	tests := []struct {
+		constraint string
+		expected   []string
+	}{
+		{"1.2.x", []string{"1.2.0", "1.2.3", "1.2.10"}},
+		{"1.2.x || >=2.1", []string{"1.2.0", "1.2.3", "1.2.10", "2.1.0", "3.0.0"}},
+		{"^1.2 !=1.3.0", []string{"1.2.0", "1.2.3", "1.2.10", "1.4.1"}},
+		{">=1.2.0-0 <1.2.3-0", []string{"1.2.0-beta.1", "1.2.0"}},
+		{"<1.1", []string{"0.9.0", "1.0.0"}},
+		{"^4", nil},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		var got []string
+		for v := range c.Versions(catalog) {
+			got = append(got, v.String())
+		}
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("Expected %q to yield %q but got %q", tc.constraint, tc.expected, got)
+		}
+
+		var filtered []string
+		for _, v := range c.FilterVersions(catalog) {
+			filtered = append(filtered, v.String())
+		}
+		if !reflect.DeepEqual(got, filtered) {
+			t.Errorf("Expected %q to yield the same versions as FilterVersions %q but got %q", tc.constraint, filtered, got)
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	stable, _ := NewConstraint(">=1.0.0")
+	lts, _ := NewConstraint("~1.4 || ~2.2")
+	next, _ := NewConstraint(">=3.0.0-0")
+	vs := Collection{
+		MustParse("0.9.0"),
+		MustParse("1.4.2"),
+		nil,
+		MustParse("2.2.0"),
+		MustParse("3.0.0-rc.1"),
+		MustParse("3.1.0"),
+	}
+
+	var got []string
+	for v, names := range Classify(slices.Values(vs), Component{"stable", stable}, Component{"lts", lts}, Component{"none", nil}, Component{"next", next}) {
+		got = append(got, v.String()+":"+strings.Join(names, ","))
+	}
+	e := []string{"0.9.0:", "1.4.2:stable,lts", "2.2.0:stable,lts", "3.0.0-rc.1:next", "3.1.0:stable,next"}
+	if !reflect.DeepEqual(got, e) {
+		t.Errorf("Expected %q but got %q", e, got)
+	}
+
+	n := 0
+	for range Classify(slices.Values(vs), Component{"stable", stable}) {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("Expected iteration to stop after 1 version but got %d", n)
+	}
+}
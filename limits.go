@@ -0,0 +1,50 @@
+package semver
+
+import "fmt"
+
+// Limits on the size of the input that will be parsed. Services parsing
+// untrusted input, such as manifests uploaded by users, can set these to
+// bound the work done for a single version or constraint. A value of 0, the
+// default, means there is no limit. The limits are read on every parse and
+// should be set before parsing begins.
+var (
+	// MaxVersionLength is the maximum length in bytes of a version string
+	// passed to NewVersion or StrictNewVersion.
+	MaxVersionLength = 0
+
+	// MaxConstraintLength is the maximum length in bytes of a constraint
+	// string passed to NewConstraint.
+	MaxConstraintLength = 0
+
+	// MaxConstraintGroups is the maximum number of || separated groups in a
+	// constraint passed to NewConstraint.
+	MaxConstraintGroups = 0
+
+	// MaxConstraintComparators is the maximum number of comparisons, across
+	// all of the groups, in a constraint passed to NewConstraint. A hyphen
+	// range counts as two comparisons.
+	MaxConstraintComparators = 0
+)
+
+// LimitError is returned when parsing input that exceeds one of the limits
+// above.
+type LimitError struct {
+	// Limit is the name of the limit that was exceeded, such as
+	// "MaxVersionLength".
+	Limit string
+
+	// Max is the value of the limit when it was exceeded.
+	Max int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("input exceeds %s of %d", e.Limit, e.Max)
+}
+
+// checkLimit returns a LimitError when n exceeds the limit max.
+func checkLimit(name string, max, n int) error {
+	if max > 0 && n > max {
+		return &LimitError{Limit: name, Max: max}
+	}
+	return nil
+}
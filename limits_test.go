@@ -0,0 +1,55 @@
+package semver
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLimits(t *testing.T) {
+	defer func(v, c, g, n int) {
+		MaxVersionLength = v
+		MaxConstraintLength = c
+		MaxConstraintGroups = g
+		MaxConstraintComparators = n
+	}(MaxVersionLength, MaxConstraintLength, MaxConstraintGroups, MaxConstraintComparators)
+
+	MaxVersionLength = 10
+	MaxConstraintLength = 40
+	MaxConstraintGroups = 3
+	MaxConstraintComparators = 4
+
+	tests := []struct {
+		name  string
+		parse func() error
+		limit string
+	}{
+		{"short version", func() error { _, err := NewVersion("1.2.3-beta"); return err }, ""},
+		{"long version", func() error { _, err := NewVersion("1.2.3-beta.1"); return err }, "MaxVersionLength"},
+		{"long strict version", func() error { _, err := StrictNewVersion("1.2.3-beta.1"); return err }, "MaxVersionLength"},
+		{"short constraint", func() error { _, err := NewConstraint(">=1.2.3 <2 || 3.x || ~4.1"); return err }, ""},
+		{"long constraint", func() error { _, err := NewConstraint(strings.Repeat(" ", 40) + "1.2.3"); return err }, "MaxConstraintLength"},
+		{"too many groups", func() error { _, err := NewConstraint("1 || 2 || 3 || 4"); return err }, "MaxConstraintGroups"},
+		{"long constraint version", func() error { _, err := NewConstraint("1.2.3-beta.1"); return err }, "MaxVersionLength"},
+		{"too many comparators", func() error { _, err := NewConstraint(">1 <5 || 2 - 3 || !=4"); return err }, "MaxConstraintComparators"},
+	}
+
+	for _, tc := range tests {
+		err := tc.parse()
+		if tc.limit == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %s", tc.name, err)
+			}
+			continue
+		}
+
+		var le *LimitError
+		if !errors.As(err, &le) {
+			t.Errorf("%s: expected LimitError but got %v", tc.name, err)
+			continue
+		}
+		if le.Limit != tc.limit {
+			t.Errorf("%s: expected limit %s to be exceeded but got %s", tc.name, tc.limit, le.Limit)
+		}
+	}
+}
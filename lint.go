@@ -0,0 +1,174 @@
+package semver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WarningKind identifies the kind of issue a Warning reports.
+type WarningKind int
+
+const (
+	// WarnLeadingV is reported for a leading v (e.g., v1.2.3). The v is not
+	// part of the semantic version spec.
+	WarnLeadingV WarningKind = iota
+
+	// WarnMissingMinor is reported when there is no minor version (e.g., 1).
+	WarnMissingMinor
+
+	// WarnMissingPatch is reported when there is no patch version (e.g., 1.2).
+	WarnMissingPatch
+
+	// WarnLeadingZero is reported for a numeric identifier with a leading
+	// zero (e.g., 1.02.3 or 1.2.3-alpha.01).
+	WarnLeadingZero
+
+	// WarnEmptyMetadataIdentifier is reported when the build metadata has an
+	// empty identifier (e.g., 1.2.3+build..1).
+	WarnEmptyMetadataIdentifier
+
+	// WarnUppercaseWildcard is reported for an X used as a wildcard in a
+	// constraint (e.g., 1.X). The lowercase x is the more common form.
+	WarnUppercaseWildcard
+)
+
+// Warning describes an issue found in a version or constraint string that
+// does not comply with the semantic version spec, or with the common form of
+// constraints, but that may still be accepted by the parsing functions.
+type Warning struct {
+	Kind WarningKind
+
+	// Offset is the byte offset in the string where the issue was found.
+	Offset int
+
+	// Message is a human readable description of the issue.
+	Message string
+}
+
+// Lint reports issues with a version string that are not fatal when it is
+// parsed with NewVersion, such as a leading v or missing minor and patch
+// versions, along with issues that StrictNewVersion would reject. It is
+// intended for user interfaces validating versions, for example when they are
+// uploaded to a registry.
+//
+// Lint accepts a superset of what NewVersion does and only reports the
+// issues described by the WarningKind values, so use NewVersion to find out
+// if a version is valid. WarnLeadingZero and WarnEmptyMetadataIdentifier are
+// only reported for versions NewVersion rejects, such as 1.02.3 and
+// 1.2.3+build..1.
+func Lint(v string) []Warning {
+	var w []Warning
+
+	off := 0
+	if strings.HasPrefix(v, "v") {
+		w = append(w, Warning{
+			Kind:    WarnLeadingV,
+			Offset:  0,
+			Message: `leading "v" is not part of a semantic version`,
+		})
+		off = 1
+	}
+
+	rest := v[off:]
+	core := rest
+	if i := strings.IndexAny(rest, "-+"); i >= 0 {
+		core = rest[:i]
+	}
+
+	parts := strings.Split(core, ".")
+	for i, p := range parts {
+		w = lintLeadingZero(w, p, off)
+		off += len(p)
+		if i < len(parts)-1 {
+			off++
+		}
+	}
+
+	if len(parts) < 2 {
+		w = append(w, Warning{
+			Kind:    WarnMissingMinor,
+			Offset:  off,
+			Message: "minor version is missing and will be treated as 0",
+		})
+	}
+	if len(parts) < 3 {
+		w = append(w, Warning{
+			Kind:    WarnMissingPatch,
+			Offset:  off,
+			Message: "patch version is missing and will be treated as 0",
+		})
+	}
+
+	rest = rest[len(core):]
+	if strings.HasPrefix(rest, "-") {
+		pre := rest[1:]
+		if i := strings.IndexByte(pre, '+'); i >= 0 {
+			pre = pre[:i]
+		}
+		poff := off + 1
+		for _, p := range strings.Split(pre, ".") {
+			w = lintLeadingZero(w, p, poff)
+			poff += len(p) + 1
+		}
+		off += 1 + len(pre)
+		rest = rest[1+len(pre):]
+	}
+
+	if strings.HasPrefix(rest, "+") {
+		moff := off + 1
+		for _, p := range strings.Split(rest[1:], ".") {
+			if p == "" {
+				w = append(w, Warning{
+					Kind:    WarnEmptyMetadataIdentifier,
+					Offset:  moff,
+					Message: "build metadata has an empty identifier",
+				})
+			}
+			moff += len(p) + 1
+		}
+	}
+
+	return w
+}
+
+// LintConstraint reports issues with a constraint string that are not fatal
+// when it is parsed with NewConstraint. Currently that is the use of an
+// uppercase X as a wildcard.
+func LintConstraint(c string) []Warning {
+	var w []Warning
+	for _, m := range findConstraintRegex.FindAllStringSubmatchIndex(c, -1) {
+		// The major, minor, and patch parts of the version are groups 3, 4,
+		// and 5. The minor and patch parts include their leading dot.
+		for g := 3; g <= 5; g++ {
+			start, end := m[2*g], m[2*g+1]
+			if start < 0 {
+				continue
+			}
+			if g > 3 {
+				start++
+			}
+			if c[start:end] == "X" {
+				w = append(w, Warning{
+					Kind:    WarnUppercaseWildcard,
+					Offset:  start,
+					Message: `uppercase "X" wildcard, use "x" instead`,
+				})
+			}
+		}
+	}
+
+	return w
+}
+
+// lintLeadingZero adds a warning to w when the numeric identifier p has a
+// leading zero.
+func lintLeadingZero(w []Warning, p string, off int) []Warning {
+	if len(p) > 1 && p[0] == '0' && containsOnly(p, num) {
+		w = append(w, Warning{
+			Kind:    WarnLeadingZero,
+			Offset:  off,
+			Message: fmt.Sprintf("numeric identifier %q has a leading zero", p),
+		})
+	}
+	return w
+}
@@ -0,0 +1,73 @@
+package semver
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		version string
+		kinds   []WarningKind
+		offsets []int
+	}{
+		{"1.2.3", nil, nil},
+		{"1.2.3-beta.1+build.01", nil, nil},
+		{"v1.2.3", []WarningKind{WarnLeadingV}, []int{0}},
+		{"1.2", []WarningKind{WarnMissingPatch}, []int{3}},
+		{"v1", []WarningKind{WarnLeadingV, WarnMissingMinor, WarnMissingPatch}, []int{0, 2, 2}},
+		{"1.02.003", []WarningKind{WarnLeadingZero, WarnLeadingZero}, []int{2, 5}},
+		{"1.2.3-alpha.01.0", []WarningKind{WarnLeadingZero}, []int{12}},
+		{"1.2-01+meta", []WarningKind{WarnMissingPatch, WarnLeadingZero}, []int{3, 4}},
+		{"1.2.3+build..1", []WarningKind{WarnEmptyMetadataIdentifier}, []int{12}},
+		{"1.2.3-rc.1+", []WarningKind{WarnEmptyMetadataIdentifier}, []int{11}},
+	}
+
+	for _, tc := range tests {
+		var kinds []WarningKind
+		var offsets []int
+		for _, w := range Lint(tc.version) {
+			kinds = append(kinds, w.Kind)
+			offsets = append(offsets, w.Offset)
+		}
+
+		if !reflect.DeepEqual(kinds, tc.kinds) {
+			t.Errorf("Expected warnings %v for %q but got %v", tc.kinds, tc.version, kinds)
+		}
+		if !reflect.DeepEqual(offsets, tc.offsets) {
+			t.Errorf("Expected warning offsets %v for %q but got %v", tc.offsets, tc.version, offsets)
+		}
+	}
+
+	w := Lint("1.02.3")
+	if len(w) != 1 || w[0].Message != `numeric identifier "02" has a leading zero` {
+		t.Errorf("Unexpected warnings for 1.02.3: %v", w)
+	}
+}
+
+func TestLintConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		offsets    []int
+	}{
+		{"1.2.x", nil},
+		{"1.2.X", []int{4}},
+		{">= X", []int{3}},
+		{"1.X.X || ~2.X-beta.X", []int{2, 4, 12}},
+		{"1.0.0-X - 2.X", []int{12}},
+	}
+
+	for _, tc := range tests {
+		var offsets []int
+		for _, w := range LintConstraint(tc.constraint) {
+			if w.Kind != WarnUppercaseWildcard {
+				t.Errorf("Unexpected warning %q for %q", w.Message, tc.constraint)
+			}
+			offsets = append(offsets, w.Offset)
+		}
+
+		if !reflect.DeepEqual(offsets, tc.offsets) {
+			t.Errorf("Expected uppercase wildcard warnings at %v for %q but got %v", tc.offsets, tc.constraint, offsets)
+		}
+	}
+}
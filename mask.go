@@ -0,0 +1,48 @@
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Mask returns the version with the parts below the segment replaced by x,
+// such as 1.2.x for SegmentMinor or 1.x for SegmentMajor. This is the release
+// line of the version, for displays that group versions, such as
+// deployments, by release line. The prerelease and metadata are dropped, so
+// the mask for SegmentPatch is the release, such as 1.2.3.
+func (v Version) Mask(s Segment) string {
+	major := strconv.FormatUint(v.major, 10)
+	switch s {
+	case SegmentMajor:
+		return major + ".x"
+	case SegmentMinor:
+		return major + "." + strconv.FormatUint(v.minor, 10) + ".x"
+	}
+	return major + "." + strconv.FormatUint(v.minor, 10) + "." + strconv.FormatUint(v.patch, 10)
+}
+
+// MaskToConstraint returns the constraints allowing the versions of a mask
+// written by Version.Mask, such as 1.2.x for >=1.2.0 <1.3.0. An error is
+// returned when the string is not a mask: a major version optionally
+// followed by the minor and patch versions, with an x in place of the
+// parts that are left out.
+func MaskToConstraint(mask string) (*Constraints, error) {
+	parts := strings.Split(mask, ".")
+	wildcard := false
+	for i, p := range parts {
+		switch {
+		case i > 2:
+			return nil, fmt.Errorf("invalid mask %q: %w", mask, ErrTooManySegments)
+		case p == "x":
+			if i == 0 {
+				return nil, fmt.Errorf("invalid mask %q: no major version", mask)
+			}
+			wildcard = true
+		case wildcard || p == "" || !containsOnly(p, num):
+			return nil, fmt.Errorf("invalid mask %q: %s version %q", mask, segmentNames[i], p)
+		}
+	}
+
+	return NewConstraint(mask)
+}
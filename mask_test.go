@@ -0,0 +1,48 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestMask(t *testing.T) {
+	v := MustParse("v1.2.3-beta.1+b42")
+	tests := []struct {
+		segment  Segment
+		expected string
+	}{
+		{SegmentMajor, "1.x"},
+		{SegmentMinor, "1.2.x"},
+		{SegmentPatch, "1.2.3"},
+	}
+
+	for _, tc := range tests {
+		m := v.Mask(tc.segment)
+		if m != tc.expected {
+			t.Errorf("Expected the %s mask of %s to be %q but got %q", tc.segment, v, tc.expected, m)
+		}
+
+		c, err := MaskToConstraint(m)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if !c.Check(MustParse("1.2.3")) {
+			t.Errorf("Expected %q to allow 1.2.3", m)
+		}
+	}
+
+	c, err := MaskToConstraint("1.2.x")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for vs, e := range map[string]bool{"1.2.0": true, "1.2.9": true, "1.3.0": false, "1.1.9": false} {
+		if c.Check(MustParse(vs)) != e {
+			t.Errorf("Expected 1.2.x with %s to be %t", vs, e)
+		}
+	}
+
+	for _, m := range []string{"x", "1.x.3", "1.2.3.x", "^1.2", "1..x", "a.x", ""} {
+		if _, err := MaskToConstraint(m); err == nil {
+			t.Errorf("Expected %q not to be a mask", m)
+		}
+	}
+}
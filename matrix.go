@@ -0,0 +1,101 @@
+package semver
+
+import (
+	"strings"
+)
+
+// Component is something with a range of supported versions, such as an
+// application or a plugin, used to compute a CompatibilityMatrix.
+type Component struct {
+	Name       string
+	Constraint *Constraints
+}
+
+// CompatibilityMatrix holds the versions supported by every pair of
+// components and by all of them together. It is created by Compatibility.
+type CompatibilityMatrix struct {
+	// Components are the names of the components in the order they were
+	// passed in.
+	Components []string
+
+	// Pairs holds the intersection of the constraints of each pair of
+	// components, indexed in the same order as Components. The diagonal
+	// holds the constraints of the component itself.
+	Pairs [][]*Constraints
+
+	// Overall is the intersection of the constraints of every component.
+	Overall *Constraints
+}
+
+// Compatibility computes the windows of versions supported by each pair of
+// components and by all of them. For example, an app requiring ^2 and a
+// plugin requiring >=2.3 <2.6 are both compatible with ^2 >=2.3 <2.6.
+// Windows that do not allow any version are empty, see IsEmpty.
+func Compatibility(components ...Component) *CompatibilityMatrix {
+	m := &CompatibilityMatrix{
+		Components: make([]string, len(components)),
+		Pairs:      make([][]*Constraints, len(components)),
+	}
+
+	for i, a := range components {
+		m.Components[i] = a.Name
+		m.Pairs[i] = make([]*Constraints, len(components))
+		for j, b := range components {
+			switch {
+			case i == j:
+				m.Pairs[i][j] = a.Constraint
+			case j < i:
+				m.Pairs[i][j] = m.Pairs[j][i]
+			default:
+				m.Pairs[i][j] = Intersection(a.Constraint, b.Constraint)
+			}
+		}
+
+		if i == 0 {
+			m.Overall = a.Constraint
+		} else {
+			m.Overall = Intersection(m.Overall, a.Constraint)
+		}
+	}
+
+	return m
+}
+
+// String renders the matrix as a Markdown table followed by the overall
+// window, for use in generated documentation. Empty windows are shown as
+// "none".
+func (m *CompatibilityMatrix) String() string {
+	var sb strings.Builder
+	sb.WriteString("|")
+	for _, n := range m.Components {
+		sb.WriteString(" | ")
+		sb.WriteString(n)
+	}
+	sb.WriteString(" |\n|---")
+	for range m.Components {
+		sb.WriteString("|---")
+	}
+	sb.WriteString("|\n")
+
+	for i, n := range m.Components {
+		sb.WriteString("| ")
+		sb.WriteString(n)
+		for _, c := range m.Pairs[i] {
+			sb.WriteString(" | ")
+			sb.WriteString(matrixCell(c))
+		}
+		sb.WriteString(" |\n")
+	}
+
+	sb.WriteString("\nOverall: ")
+	sb.WriteString(matrixCell(m.Overall))
+	return sb.String()
+}
+
+// matrixCell returns the text for a window in the rendered matrix.
+func matrixCell(c *Constraints) string {
+	if c == nil || c.IsEmpty() {
+		return "none"
+	}
+	return strings.ReplaceAll(c.String(), "|", `\|`)
+}
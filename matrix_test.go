@@ -0,0 +1,50 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestCompatibility(t *testing.T) {
+	mustConstraint := func(c string) *Constraints {
+		con, err := NewConstraint(c)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		return con
+	}
+
+	m := Compatibility(
+		Component{Name: "app", Constraint: mustConstraint("^2")},
+		Component{Name: "plugin", Constraint: mustConstraint(">=2.3 <2.6")},
+		Component{Name: "legacy", Constraint: mustConstraint("^1 || 2.4.x")},
+	)
+
+	if s := m.Pairs[0][1].String(); s != "^2 >=2.3 <2.6" {
+		t.Errorf("Unexpected app and plugin window %q", s)
+	}
+	if m.Pairs[1][0] != m.Pairs[0][1] {
+		t.Error("Expected the matrix to be symmetric")
+	}
+	if s := m.Overall.String(); s != "^2 >=2.3 <2.6 2.4.x" {
+		t.Errorf("Unexpected overall window %q", s)
+	}
+
+	e := `| | app | plugin | legacy |
+|---|---|---|---|
+| app | ^2 | ^2 >=2.3 <2.6 | ^2 2.4.x |
+| plugin | ^2 >=2.3 <2.6 | >=2.3 <2.6 | >=2.3 <2.6 2.4.x |
+| legacy | ^2 2.4.x | >=2.3 <2.6 2.4.x | ^1 \|\| 2.4.x |
+
+Overall: ^2 >=2.3 <2.6 2.4.x`
+	if m.String() != e {
+		t.Errorf("Expected matrix:\n%s\nbut got:\n%s", e, m)
+	}
+
+	m = Compatibility(
+		Component{Name: "a", Constraint: mustConstraint("^1")},
+		Component{Name: "b", Constraint: mustConstraint("^2")},
+	)
+	if !m.Overall.IsEmpty() {
+		t.Errorf("Expected no overall window but got %q", m.Overall)
+	}
+}
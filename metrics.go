@@ -0,0 +1,67 @@
+package semver
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// The names of the caches passed to Metrics.CacheLookup.
+const (
+	// CacheVersionInterner is the cache of parsed versions used by
+	// Interner.NewVersion.
+	CacheVersionInterner = "version-interner"
+
+	// CacheConstraintInterner is the cache of parsed constraints used by
+	// ConstraintInterner.NewConstraint.
+	CacheConstraintInterner = "constraint-interner"
+
+	// CacheCheck is the cache of the results of Constraints.Check used by
+	// CheckCache.
+	CacheCheck = "check"
+)
+
+// Metrics receives measurements of the work done by the package. Services
+// embedding the package can implement it to feed counters and histograms,
+// such as those of Prometheus, without wrapping every call. See SetMetrics.
+//
+// The methods are called on the hot paths of parsing and checking so they
+// must be fast, and they must be safe for concurrent use.
+type Metrics interface {
+	// VersionParsed is called after each version is parsed by NewVersion,
+	// StrictNewVersion, and ParseVersions, with the error when it did not
+	// parse.
+	VersionParsed(err error)
+
+	// ConstraintParsed is called after constraints are parsed by
+	// NewConstraint with the error when they did not parse.
+	ConstraintParsed(err error)
+
+	// Checked is called after each Constraints.Check with how long it took
+	// and whether the version satisfied the constraints.
+	Checked(d time.Duration, ok bool)
+
+	// CacheLookup is called for each lookup in a cache, such as
+	// CacheVersionInterner, with whether it was a hit.
+	CacheLookup(cache string, hit bool)
+}
+
+// metricsHolder wraps Metrics so values of different types can be stored in
+// the same atomic.Value.
+type metricsHolder struct {
+	m Metrics
+}
+
+var metrics atomic.Value
+
+// SetMetrics sets the Metrics the package reports to. Passing nil stops
+// reporting, which is the default. It is safe to call while other
+// goroutines are using the package.
+func SetMetrics(m Metrics) {
+	metrics.Store(metricsHolder{m})
+}
+
+// loadMetrics returns the Metrics the package reports to, or nil.
+func loadMetrics() Metrics {
+	h, _ := metrics.Load().(metricsHolder)
+	return h.m
+}
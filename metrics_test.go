@@ -0,0 +1,88 @@
+package semver
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type testMetrics struct {
+	mu          sync.Mutex
+	versions    map[bool]int
+	constraints map[bool]int
+	checks      map[bool]int
+	caches      map[string]map[bool]int
+}
+
+func newTestMetrics() *testMetrics {
+	return &testMetrics{
+		versions:    map[bool]int{},
+		constraints: map[bool]int{},
+		checks:      map[bool]int{},
+		caches:      map[string]map[bool]int{},
+	}
+}
+
+func (m *testMetrics) VersionParsed(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.versions[err == nil]++
+}
+
+func (m *testMetrics) ConstraintParsed(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.constraints[err == nil]++
+}
+
+func (m *testMetrics) Checked(d time.Duration, ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.checks[ok]++
+}
+
+func (m *testMetrics) CacheLookup(cache string, hit bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.caches[cache] == nil {
+		m.caches[cache] = map[bool]int{}
+	}
+	m.caches[cache][hit]++
+}
+
+func TestMetrics(t *testing.T) {
+	m := newTestMetrics()
+	SetMetrics(m)
+	t.Cleanup(func() { SetMetrics(nil) })
+
+	_, _ = NewVersion("1.2.3")
+	_, _ = StrictNewVersion("1.2")
+	_, _ = ParseVersions([]string{"1.0.0", "foo"})
+	c, _ := NewConstraint("^1.2")
+	_, _ = NewConstraint("^foo")
+	c.Check(MustParse("1.3.0"))
+	c.Check(MustParse("2.0.0"))
+
+	var in Interner
+	_, _ = in.NewVersion("4.5.6")
+	_, _ = in.NewVersion("4.5.6")
+
+	if m.versions[true] != 6 || m.versions[false] != 2 {
+		t.Errorf("Expected 6 versions parsed and 2 failures but got %v", m.versions)
+	}
+	if m.constraints[true] != 1 || m.constraints[false] != 1 {
+		t.Errorf("Expected 1 constraint parsed and 1 failure but got %v", m.constraints)
+	}
+	if m.checks[true] != 1 || m.checks[false] != 1 {
+		t.Errorf("Expected 1 passed and 1 failed check but got %v", m.checks)
+	}
+	if l := m.caches[CacheVersionInterner]; l[true] != 1 || l[false] != 1 {
+		t.Errorf("Expected 1 cache hit and 1 miss but got %v", l)
+	}
+
+	SetMetrics(nil)
+	_, _ = NewVersion("1.2.3")
+	if m.versions[true] != 6 {
+		t.Error("Expected no metrics after they are unset")
+	}
+}
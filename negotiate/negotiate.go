@@ -0,0 +1,140 @@
+/*
+Package negotiate selects the version of an API to serve from a request
+header holding semantic version constraints.
+
+A client sends the versions it can work with, such as Accept-Version: ^2.1,
+and the server answers with the highest version it supports that satisfies
+them:
+
+	n := &negotiate.Negotiator{
+		Supported: semver.Collection{
+			semver.MustParse("1.4.0"),
+			semver.MustParse("2.0.0"),
+			semver.MustParse("2.3.1"),
+		},
+	}
+	http.Handle("/", n.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v, _ := negotiate.FromContext(r.Context())
+		fmt.Fprintf(w, "serving API %s", v)
+	})))
+
+Requests whose constraints no supported version satisfies get a 406 Not
+Acceptable response and requests with invalid constraints get a 400 Bad
+Request response.
+*/
+package negotiate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// DefaultHeader is the request header read when Negotiator.Header is empty.
+const DefaultHeader = "Accept-Version"
+
+var (
+	// ErrNotAcceptable is returned by Negotiate when none of the supported
+	// versions satisfy the constraints in the request.
+	ErrNotAcceptable = errors.New("No supported version satisfies the requested versions")
+
+	// ErrInvalidHeader is returned by Negotiate when the header does not
+	// hold valid constraints.
+	ErrInvalidHeader = errors.New("Invalid version header")
+)
+
+// Negotiator selects a version from the supported ones for each request.
+type Negotiator struct {
+	// Header is the name of the request header holding the constraints.
+	// DefaultHeader is used when it is empty.
+	Header string
+
+	// Supported are the versions the server can serve.
+	Supported semver.Collection
+
+	// Default is the version used for requests without the header. When it
+	// is nil the highest supported version that is not a prerelease is used.
+	Default *semver.Version
+}
+
+// Negotiate returns the version to serve for a request. That is the highest
+// supported version satisfying the constraints in the header, preferring
+// releases over prereleases as semver.Constraints.Pin does. The error wraps
+// ErrInvalidHeader or ErrNotAcceptable when a version can not be selected.
+func (n *Negotiator) Negotiate(r *http.Request) (*semver.Version, error) {
+	h := strings.TrimSpace(r.Header.Get(n.header()))
+	if h == "" {
+		if n.Default != nil {
+			return n.Default, nil
+		}
+		h = "*"
+	}
+
+	c, err := semver.NewConstraint(h)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %s", ErrInvalidHeader, n.header(), err)
+	}
+
+	v, err := c.Pin(n.Supported)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotAcceptable, c)
+	}
+	return v, nil
+}
+
+// Middleware returns a handler that negotiates the version for each request
+// and calls next with the selected version in the request context, see
+// FromContext. When a version can not be selected a 400 or 406 response
+// listing the supported versions is written instead.
+func (n *Negotiator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v, err := n.Negotiate(r)
+		if err != nil {
+			code := http.StatusNotAcceptable
+			if errors.Is(err, ErrInvalidHeader) {
+				code = http.StatusBadRequest
+			}
+			http.Error(w, fmt.Sprintf("%s\nsupported versions: %s", err, n.supported()), code)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), v)))
+	})
+}
+
+func (n *Negotiator) header() string {
+	if n.Header == "" {
+		return DefaultHeader
+	}
+	return n.Header
+}
+
+// supported returns the supported versions as a comma separated list.
+func (n *Negotiator) supported() string {
+	s := make([]string, 0, len(n.Supported))
+	for _, v := range n.Supported {
+		if v != nil {
+			s = append(s, v.String())
+		}
+	}
+	return strings.Join(s, ", ")
+}
+
+// contextKey is the type of the key the selected version is stored under so
+// it does not collide with keys from other packages.
+type contextKey struct{}
+
+// NewContext returns a copy of ctx holding the version.
+func NewContext(ctx context.Context, v *semver.Version) context.Context {
+	return context.WithValue(ctx, contextKey{}, v)
+}
+
+// FromContext returns the version selected for a request by Middleware.
+func FromContext(ctx context.Context) (*semver.Version, bool) {
+	v, ok := ctx.Value(contextKey{}).(*semver.Version)
+	return v, ok
+}
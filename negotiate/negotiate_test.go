@@ -0,0 +1,109 @@
+package negotiate
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+func supported() semver.Collection {
+	return semver.Collection{
+		semver.MustParse("1.4.0"),
+		semver.MustParse("2.0.0"),
+		semver.MustParse("2.3.1"),
+		semver.MustParse("3.0.0-beta.1"),
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header   string
+		value    string
+		def      string
+		expected string
+		err      error
+	}{
+		{"", "^2", "", "2.3.1", nil},
+		{"", "~2.0", "", "2.0.0", nil},
+		{"", "1.x || 2.0.0", "", "2.0.0", nil},
+		{"", ">=3.0.0-0", "", "3.0.0-beta.1", nil},
+		{"", "", "", "2.3.1", nil},
+		{"", "", "1.4.0", "1.4.0", nil},
+		{"X-API-Version", "^1", "", "1.4.0", nil},
+		{"", "^4", "", "", ErrNotAcceptable},
+		{"", ">=1.x.y", "", "", ErrInvalidHeader},
+	}
+
+	for _, tc := range tests {
+		n := &Negotiator{Header: tc.header, Supported: supported()}
+		if tc.def != "" {
+			n.Default = semver.MustParse(tc.def)
+		}
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tc.value != "" {
+			r.Header.Set(n.header(), tc.value)
+		}
+
+		v, err := n.Negotiate(r)
+		if tc.err != nil {
+			if !errors.Is(err, tc.err) {
+				t.Errorf("Expected error %q negotiating %q but got %v", tc.err, tc.value, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error negotiating %q: %s", tc.value, err)
+			continue
+		}
+		if v.String() != tc.expected {
+			t.Errorf("Expected %q to negotiate %s but got %s", tc.value, tc.expected, v)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	n := &Negotiator{Supported: supported()}
+	h := n.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v, ok := FromContext(r.Context())
+		if !ok {
+			t.Error("Expected a version in the context")
+			return
+		}
+		w.Write([]byte(v.String()))
+	}))
+
+	tests := []struct {
+		value string
+		code  int
+		body  string
+	}{
+		{"^2", http.StatusOK, "2.3.1"},
+		{"", http.StatusOK, "2.3.1"},
+		{"^4", http.StatusNotAcceptable, "supported versions: 1.4.0, 2.0.0, 2.3.1, 3.0.0-beta.1"},
+		{"not a version", http.StatusBadRequest, "supported versions:"},
+	}
+
+	for _, tc := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tc.value != "" {
+			r.Header.Set(DefaultHeader, tc.value)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if w.Code != tc.code {
+			t.Errorf("Expected status %d for %q but got %d", tc.code, tc.value, w.Code)
+		}
+		if !strings.Contains(w.Body.String(), tc.body) {
+			t.Errorf("Expected body for %q to contain %q but got %q", tc.value, tc.body, w.Body.String())
+		}
+	}
+
+	if _, ok := FromContext(httptest.NewRequest(http.MethodGet, "/", nil).Context()); ok {
+		t.Error("Expected no version in a new context")
+	}
+}
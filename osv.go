@@ -0,0 +1,173 @@
+package semver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// OSVEvent is an event of a SEMVER range in the Open Source Vulnerability
+// (OSV) format. Exactly one of the fields is set. The JSON encoding matches
+// the OSV schema so advisories can be decoded directly into it.
+type OSVEvent struct {
+	// Introduced is the version the vulnerability was introduced in. "0"
+	// means every version before the next event.
+	Introduced string `json:"introduced,omitempty"`
+
+	// Fixed is the first version that is no longer affected.
+	Fixed string `json:"fixed,omitempty"`
+
+	// LastAffected is the last version that is affected.
+	LastAffected string `json:"last_affected,omitempty"`
+
+	// Limit is a version above which no version is affected.
+	Limit string `json:"limit,omitempty"`
+}
+
+// version returns the version of the event, checking that only one is set.
+func (e OSVEvent) version() (string, error) {
+	var s string
+	n := 0
+	for _, f := range []string{e.Introduced, e.Fixed, e.LastAffected, e.Limit} {
+		if f != "" {
+			s = f
+			n++
+		}
+	}
+	if n != 1 {
+		return "", fmt.Errorf("%w: an OSV event must have exactly one version", ErrInvalidRange)
+	}
+	return s, nil
+}
+
+// NewConstraintFromOSV returns constraints allowing the versions affected by
+// the events of an OSV SEMVER range. Each introduced event starts a group
+// that ends at the next fixed or last_affected event, so introduced 1.2.0 and
+// fixed 1.4.1 become >=1.2.0 <1.4.1. Limit events are an upper bound of every
+// group. When no version is affected the constraints are empty, see IsEmpty.
+//
+// OSV considers prereleases within a range to be affected while these
+// constraints follow the prerelease rules of this package, so a prerelease
+// is only allowed when the range starts with one.
+func NewConstraintFromOSV(events []OSVEvent) (*Constraints, error) {
+	type event struct {
+		OSVEvent
+		v *Version
+	}
+	evs := make([]event, 0, len(events))
+	var limit *Version
+	for _, e := range events {
+		s, err := e.version()
+		if err != nil {
+			return nil, err
+		}
+		if e.Introduced == "0" {
+			evs = append(evs, event{OSVEvent: e})
+			continue
+		}
+		v, err := NewVersion(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %w", ErrInvalidRange, s, err)
+		}
+		if e.Limit != "" {
+			if limit == nil || v.GreaterThan(limit) {
+				limit = v
+			}
+			continue
+		}
+		evs = append(evs, event{OSVEvent: e, v: v})
+	}
+
+	sort.SliceStable(evs, func(i, j int) bool {
+		if evs[i].v == nil || evs[j].v == nil {
+			return evs[i].v == nil && evs[j].v != nil
+		}
+		return evs[i].v.LessThan(evs[j].v)
+	})
+
+	var groups []string
+	var g []string
+	open := false
+	for _, e := range evs {
+		switch {
+		case e.Introduced != "":
+			if open {
+				continue
+			}
+			open = true
+			g = nil
+			if e.v != nil {
+				g = append(g, ">="+e.v.String())
+			}
+		case !open:
+			// A fixed or last affected version without an introduced
+			// version before it does not affect any version.
+		case e.Fixed != "":
+			groups = append(groups, osvGroup(append(g, "<"+e.v.String()), limit))
+			open = false
+		default:
+			groups = append(groups, osvGroup(append(g, "<="+e.v.String()), limit))
+			open = false
+		}
+	}
+	if open {
+		groups = append(groups, osvGroup(g, limit))
+	}
+
+	if len(groups) == 0 {
+		return newConstraints(nil), nil
+	}
+	return NewConstraint(strings.Join(groups, " || "))
+}
+
+// osvGroup joins the constraints of a group adding the limit.
+func osvGroup(g []string, limit *Version) string {
+	if limit != nil {
+		g = append(g, "<"+limit.String())
+	}
+	if len(g) == 0 {
+		return "*"
+	}
+	return strings.Join(g, " ")
+}
+
+// OSVEvents returns the events of an OSV SEMVER range affecting the versions
+// the constraints allow. Overlapping groups are joined. An error wrapping
+// ErrUnsupportedRange is returned when the constraints can not be expressed
+// as events, such as when they have a != constraint, a deny list, or a >
+// constraint for a full version.
+//
+// Which prereleases are allowed is not captured, see NewConstraintFromOSV.
+func (cs Constraints) OSVEvents() ([]OSVEvent, error) {
+	if len(cs.Deny) > 0 {
+		return nil, fmt.Errorf("%w: a deny list can not be expressed as OSV events", ErrUnsupportedRange)
+	}
+	ivs, ne, err := cs.intervals()
+	if err != nil {
+		return nil, err
+	}
+	if len(ne) > 0 {
+		return nil, fmt.Errorf("%w: != %s can not be expressed as OSV events", ErrUnsupportedRange, ne[0])
+	}
+
+	events := make([]OSVEvent, 0, 2*len(ivs))
+	for _, iv := range ivs {
+		switch {
+		case iv.lo == nil:
+			events = append(events, OSVEvent{Introduced: "0"})
+		case !iv.loInc:
+			return nil, fmt.Errorf("%w: >%s can not be expressed as OSV events", ErrUnsupportedRange, iv.lo)
+		default:
+			events = append(events, OSVEvent{Introduced: iv.lo.String()})
+		}
+
+		switch {
+		case iv.hi == nil:
+		case iv.hiInc:
+			events = append(events, OSVEvent{LastAffected: iv.hi.String()})
+		default:
+			events = append(events, OSVEvent{Fixed: iv.hi.String()})
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,110 @@
+package semver
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNewConstraintFromOSV(t *testing.T) {
+	tests := []struct {
+		events   string
+		expected string
+	}{
+		{`[{"introduced":"0"},{"fixed":"1.4.1"}]`, "<1.4.1"},
+		{`[{"introduced":"1.2.0"},{"fixed":"1.4.1"}]`, ">=1.2.0 <1.4.1"},
+		{`[{"introduced":"1.2.0"},{"last_affected":"1.4.0"}]`, ">=1.2.0 <=1.4.0"},
+		{`[{"introduced":"2.0.0"}]`, ">=2.0.0"},
+		{`[{"introduced":"0"}]`, "*"},
+		{`[{"introduced":"2.0.0"},{"fixed":"2.3.1"},{"introduced":"1.0.0"},{"fixed":"1.8.4"}]`, ">=1.0.0 <1.8.4 || >=2.0.0 <2.3.1"},
+		{`[{"introduced":"1.0.0"},{"introduced":"1.2.0"},{"fixed":"1.5.0"}]`, ">=1.0.0 <1.5.0"},
+		{`[{"introduced":"1.0.0"},{"limit":"1.5.0"}]`, ">=1.0.0 <1.5.0"},
+		{`[{"introduced":"1.0.0-beta.1"},{"fixed":"1.0.0"}]`, ">=1.0.0-beta.1 <1.0.0"},
+		{`[{"fixed":"1.0.0"}]`, ""},
+	}
+
+	for _, tc := range tests {
+		var events []OSVEvent
+		if err := json.Unmarshal([]byte(tc.events), &events); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		c, err := NewConstraintFromOSV(events)
+		if err != nil {
+			t.Errorf("Unexpected error for %s: %s", tc.events, err)
+			continue
+		}
+		if c.String() != tc.expected {
+			t.Errorf("Expected %s to be %q but got %q", tc.events, tc.expected, c)
+		}
+	}
+
+	invalid := [][]OSVEvent{
+		{{Introduced: "1.0.0", Fixed: "1.2.0"}},
+		{{}},
+		{{Introduced: "one"}},
+	}
+	for _, events := range invalid {
+		if _, err := NewConstraintFromOSV(events); !errors.Is(err, ErrInvalidRange) {
+			t.Errorf("Expected ErrInvalidRange for %v but got %v", events, err)
+		}
+	}
+}
+
+func TestConstraintsOSVEvents(t *testing.T) {
+	tests := []struct {
+		constraint string
+		expected   []OSVEvent
+	}{
+		{"<1.4.1", []OSVEvent{{Introduced: "0"}, {Fixed: "1.4.1"}}},
+		{"^1.2", []OSVEvent{{Introduced: "1.2.0"}, {Fixed: "2.0.0"}}},
+		{"~1.2.3 || 2.x", []OSVEvent{{Introduced: "1.2.3"}, {Fixed: "1.3.0"}, {Introduced: "2.0.0"}, {Fixed: "3.0.0"}}},
+		{">=1.2.0 <=1.4.0", []OSVEvent{{Introduced: "1.2.0"}, {LastAffected: "1.4.0"}}},
+		{"^1.2 || ^1.5 || >=1.9.0", []OSVEvent{{Introduced: "1.2.0"}}},
+		{"1.2 - 1.4 || 1.4 - 1.6", []OSVEvent{{Introduced: "1.2.0"}, {Fixed: "1.7.0"}}},
+		{"1.2.0 - 1.4.0 || 1.4.0 - 1.6.0", []OSVEvent{{Introduced: "1.2.0"}, {LastAffected: "1.6.0"}}},
+		{"*", []OSVEvent{{Introduced: "0"}}},
+		{">1.x <2.5", []OSVEvent{{Introduced: "2.0.0"}, {Fixed: "2.5.0"}}},
+		{"=1.2.3", []OSVEvent{{Introduced: "1.2.3"}, {LastAffected: "1.2.3"}}},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		events, err := c.OSVEvents()
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %s", tc.constraint, err)
+			continue
+		}
+		if !reflect.DeepEqual(events, tc.expected) {
+			t.Errorf("Expected %q to be %v but got %v", tc.constraint, tc.expected, events)
+		}
+
+		back, err := NewConstraintFromOSV(events)
+		if err != nil {
+			t.Errorf("Unexpected error parsing the events of %q: %s", tc.constraint, err)
+			continue
+		}
+		for _, v := range []string{"0.9.0", "1.2.0", "1.2.3", "1.3.0", "1.4.0", "1.6.0", "1.9.0", "2.0.0", "2.4.9", "2.5.0", "3.0.0"} {
+			if c.Check(MustParse(v)) != back.Check(MustParse(v)) {
+				t.Errorf("Expected %q and %q to agree on %s", tc.constraint, back, v)
+			}
+		}
+	}
+
+	for _, s := range []string{"^1.2 !=1.4.0", ">1.2.3"} {
+		c, _ := NewConstraint(s)
+		if _, err := c.OSVEvents(); !errors.Is(err, ErrUnsupportedRange) {
+			t.Errorf("Expected ErrUnsupportedRange for %q but got %v", s, err)
+		}
+	}
+	c, _ := NewConstraint("^1.2")
+	c.Deny = []*Version{MustParse("1.4.0")}
+	if _, err := c.OSVEvents(); !errors.Is(err, ErrUnsupportedRange) {
+		t.Errorf("Expected ErrUnsupportedRange for a deny list but got %v", err)
+	}
+}
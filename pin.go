@@ -0,0 +1,69 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoSatisfyingVersion is returned by Pin when none of the available
+// versions satisfy the constraints.
+var ErrNoSatisfyingVersion = errors.New("No version satisfies the constraints")
+
+// Pin returns the version a resolver would select from the available ones.
+// That is the highest version satisfying the constraints that is not a
+// prerelease. A prerelease is only selected when no release satisfies the
+// constraints. Nil versions are ignored. When nothing satisfies the
+// constraints an error wrapping ErrNoSatisfyingVersion is returned.
+//
+// This is useful for generating lock files. See PinnedConstraint for the
+// constraint that only allows the selected version.
+func (cs Constraints) Pin(available Collection) (*Version, error) {
+	var rel, pre *Version
+	for _, v := range available {
+		if v == nil || !cs.Check(v) {
+			continue
+		}
+		if v.pre == "" {
+			if rel == nil || v.GreaterThan(rel) {
+				rel = v
+			}
+		} else if pre == nil || v.GreaterThan(pre) {
+			pre = v
+		}
+	}
+
+	if rel != nil {
+		return rel, nil
+	}
+	if pre != nil {
+		return pre, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNoSatisfyingVersion, cs)
+}
+
+// PinnedConstraint returns constraints that only allow the given version,
+// such as =1.6.3. Build metadata is not part of the constraint as it is not
+// considered when comparing versions.
+func PinnedConstraint(v *Version) (*Constraints, error) {
+	return NewConstraint("=" + New(v.major, v.minor, v.patch, v.pre, "").String())
+}
+
+// Nearest returns the available versions closest to v that satisfy the
+// constraints, the highest one lower than v and the lowest one higher than
+// v. Either is nil when there is no such version. This is useful when v is
+// rejected to suggest alternatives, such as "1.9.0 is not allowed, the
+// nearest allowed versions are 1.8.4 and 2.0.1". Nil versions are ignored.
+func (cs Constraints) Nearest(v *Version, available Collection) (below, above *Version) {
+	for _, a := range available {
+		if a == nil || !cs.Check(a) {
+			continue
+		}
+		switch d := a.Compare(v); {
+		case d < 0 && (below == nil || a.GreaterThan(below)):
+			below = a
+		case d > 0 && (above == nil || a.LessThan(above)):
+			above = a
+		}
+	}
+	return below, above
+}
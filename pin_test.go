@@ -0,0 +1,126 @@
+package semver
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConstraintsPin(t *testing.T) {
+	available := Collection{
+		MustParse("1.5.0"),
+		MustParse("1.6.3"),
+		MustParse("1.7.0-beta.1"),
+		MustParse("1.6.2"),
+		nil,
+		MustParse("2.0.0"),
+		MustParse("3.0.0-rc.1"),
+	}
+
+	tests := []struct {
+		constraint string
+		expected   string
+	}{
+		{"^1.5.0", "1.6.3"},
+		{"^1.5.0-0", "1.6.3"},
+		{"~1.5", "1.5.0"},
+		{"*", "2.0.0"},
+		{">=3.0.0-0", "3.0.0-rc.1"},
+		{"1.7.0-beta.1 || 1.5.0", "1.5.0"},
+		{"=1.7.0-beta.1", "1.7.0-beta.1"},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Errorf("err: %s", err)
+			continue
+		}
+
+		v, err := c.Pin(available)
+		if err != nil {
+			t.Errorf("Unexpected error pinning %q: %s", tc.constraint, err)
+			continue
+		}
+		if v.String() != tc.expected {
+			t.Errorf("Expected %q to pin %s but got %s", tc.constraint, tc.expected, v)
+		}
+	}
+
+	c, _ := NewConstraint("^4")
+	if _, err := c.Pin(available); !errors.Is(err, ErrNoSatisfyingVersion) {
+		t.Errorf("Expected %q but got %v", ErrNoSatisfyingVersion, err)
+	}
+}
+
+func TestPinnedConstraint(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected string
+	}{
+		{"1.6.3", "=1.6.3"},
+		{"v1.2", "=1.2.0"},
+		{"1.7.0-beta.1+build.5", "=1.7.0-beta.1"},
+	}
+
+	for _, tc := range tests {
+		v := MustParse(tc.version)
+		c, err := PinnedConstraint(v)
+		if err != nil {
+			t.Errorf("Unexpected error pinning %q: %s", tc.version, err)
+			continue
+		}
+		if c.String() != tc.expected {
+			t.Errorf("Expected pinned constraint for %q to be %q but got %q", tc.version, tc.expected, c)
+		}
+		if !c.Check(v) || c.Check(MustParse("1.6.4")) {
+			t.Errorf("Expected pinned constraint %q to only allow %s", c, v)
+		}
+	}
+}
+
+func TestConstraintsNearest(t *testing.T) {
+	available := Collection{
+		MustParse("1.8.4"),
+		MustParse("1.8.2"),
+		MustParse("1.9.0"),
+		nil,
+		MustParse("2.0.1"),
+		MustParse("2.0.0-rc.1"),
+		MustParse("2.1.0"),
+	}
+
+	tests := []struct {
+		constraint string
+		version    string
+		below      string
+		above      string
+	}{
+		{">=1.8 !=1.9.0", "1.9.0", "1.8.4", "2.0.1"},
+		{"^1.8", "1.9.5", "1.9.0", ""},
+		{"^2", "1.9.0", "", "2.0.1"},
+		{"^3", "1.9.0", "", ""},
+		{">=1.8 <1.9 || >=2.1", "2.0.0", "1.8.4", "2.1.0"},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		below, above := c.Nearest(MustParse(tc.version), available)
+		if s := versionString(below); s != tc.below {
+			t.Errorf("Expected nearest below %s for %q to be %q but got %q", tc.version, tc.constraint, tc.below, s)
+		}
+		if s := versionString(above); s != tc.above {
+			t.Errorf("Expected nearest above %s for %q to be %q but got %q", tc.version, tc.constraint, tc.above, s)
+		}
+	}
+}
+
+func versionString(v *Version) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
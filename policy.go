@@ -0,0 +1,249 @@
+package semver
+
+import (
+	"strings"
+	"time"
+)
+
+// Policy is a set of rules that versions and constraints are evaluated
+// against, such as not allowing prereleases in production or denying
+// versions that have been yanked.
+type Policy struct {
+	Rules []PolicyRule
+}
+
+// PolicyRule is a single rule of a Policy.
+type PolicyRule interface {
+	// Name identifies the rule in violations.
+	Name() string
+
+	// CheckVersion returns why the version breaks the rule or an empty
+	// string if it does not.
+	CheckVersion(v *Version) string
+
+	// CheckConstraints returns why the constraints break the rule or an
+	// empty string if they do not.
+	CheckConstraints(c *Constraints) string
+}
+
+// Verdict is the result of evaluating a version or constraints against a
+// Policy.
+type Verdict struct {
+	// Allowed is true when none of the rules were broken.
+	Allowed bool
+
+	// Violations are the rules that were broken, in the order of the rules
+	// in the policy.
+	Violations []Violation
+}
+
+// Violation describes a broken rule.
+type Violation struct {
+	// Rule is the name of the rule.
+	Rule string
+
+	// Message describes why the rule was broken.
+	Message string
+}
+
+func (v Violation) String() string {
+	return v.Rule + ": " + v.Message
+}
+
+// EvaluateVersion checks the version against every rule in the policy.
+func (p *Policy) EvaluateVersion(v *Version) *Verdict {
+	return p.evaluate(func(r PolicyRule) string { return r.CheckVersion(v) })
+}
+
+// EvaluateConstraints checks the constraints against every rule in the
+// policy. Constraints break a rule when they allow versions the rule does not.
+func (p *Policy) EvaluateConstraints(c *Constraints) *Verdict {
+	return p.evaluate(func(r PolicyRule) string { return r.CheckConstraints(c) })
+}
+
+func (p *Policy) evaluate(check func(r PolicyRule) string) *Verdict {
+	vd := &Verdict{Allowed: true}
+	for _, r := range p.Rules {
+		if msg := check(r); msg != "" {
+			vd.Violations = append(vd.Violations, Violation{Rule: r.Name(), Message: msg})
+			vd.Allowed = false
+		}
+	}
+	return vd
+}
+
+// NoPrereleaseRule does not allow prerelease versions or constraints that
+// allow prereleases.
+type NoPrereleaseRule struct{}
+
+// Name returns "no-prerelease".
+func (NoPrereleaseRule) Name() string { return "no-prerelease" }
+
+// CheckVersion breaks the rule when the version is a prerelease.
+func (NoPrereleaseRule) CheckVersion(v *Version) string {
+	if v.pre != "" {
+		return v.String() + " is a prerelease"
+	}
+	return ""
+}
+
+// CheckConstraints breaks the rule when a group of the constraints may
+// allow prerelease versions with the prerelease policy of the constraints,
+// such as >=1.2.0-beta or !=1.2.3.
+func (NoPrereleaseRule) CheckConstraints(c *Constraints) string {
+	for _, o := range c.constraints {
+		if groupAcceptsPrerelease(o, c.PrereleasePolicy) {
+			return strings.Join(constraintStrings(o), " ") + " allows prereleases"
+		}
+	}
+	return ""
+}
+
+// AllowedRangeRule only allows versions satisfying the constraints, such as
+// >=1.0.0 <3.0.0 for the supported releases.
+type AllowedRangeRule struct {
+	Constraint *Constraints
+}
+
+// Name returns "allowed-range".
+func (AllowedRangeRule) Name() string { return "allowed-range" }
+
+// CheckVersion breaks the rule when the version does not satisfy the
+// constraints of the rule.
+func (r AllowedRangeRule) CheckVersion(v *Version) string {
+	if !r.Constraint.Check(v) {
+		return v.String() + " is not in the allowed range " + r.Constraint.String()
+	}
+	return ""
+}
+
+// CheckConstraints breaks the rule when the constraints allow versions
+// outside of the constraints of the rule. Which prereleases are allowed is
+// not compared.
+func (r AllowedRangeRule) CheckConstraints(c *Constraints) string {
+	if out := subtractIntervals(c.intervalSet(), r.Constraint.intervalSet()); len(out) > 0 {
+		return c.String() + " allows " + out[0].String() + " which is outside the allowed range " + r.Constraint.String()
+	}
+	return ""
+}
+
+// MinimumVersionRule sets the lowest allowed version in each major version.
+// For example, with the floors 1.4.2 and 2.1.0 the version 1.4.1 is not
+// allowed while 1.5.0 and 3.0.0 are.
+type MinimumVersionRule struct {
+	Floors []*Version
+}
+
+// Name returns "minimum-version".
+func (MinimumVersionRule) Name() string { return "minimum-version" }
+
+// CheckVersion breaks the rule when the version is lower than the floor for
+// its major version.
+func (r MinimumVersionRule) CheckVersion(v *Version) string {
+	if f := r.floor(v.major); f != nil && v.LessThan(f) {
+		return v.String() + " is below the minimum " + f.String()
+	}
+	return ""
+}
+
+// CheckConstraints breaks the rule when the constraints allow a version
+// below the floor of its major version.
+func (r MinimumVersionRule) CheckConstraints(c *Constraints) string {
+	ivs := c.intervalSet()
+	for _, f := range r.Floors {
+		if f != r.floor(f.major) {
+			continue
+		}
+		below := interval{lo: New(f.major, 0, 0, "0", ""), hi: f, loInc: true}
+		if out := intersectIntervals(ivs, []interval{below}); len(out) > 0 {
+			return c.String() + " allows " + out[0].String() + " which is below the minimum " + f.String()
+		}
+	}
+	return ""
+}
+
+// floor returns the highest floor for a major version.
+func (r MinimumVersionRule) floor(major uint64) *Version {
+	var f *Version
+	for _, v := range r.Floors {
+		if v.major == major && (f == nil || v.GreaterThan(f)) {
+			f = v
+		}
+	}
+	return f
+}
+
+// DenyRule does not allow specific versions, such as those that have been
+// yanked or retracted.
+type DenyRule struct {
+	Versions []*Version
+}
+
+// Name returns "deny".
+func (DenyRule) Name() string { return "deny" }
+
+// CheckVersion breaks the rule when the version is denied.
+func (r DenyRule) CheckVersion(v *Version) string {
+	for _, d := range r.Versions {
+		if v.Equal(d) {
+			return v.String() + " is denied"
+		}
+	}
+	return ""
+}
+
+// CheckConstraints breaks the rule when the constraints allow a denied
+// version.
+func (r DenyRule) CheckConstraints(c *Constraints) string {
+	for _, d := range r.Versions {
+		if c.Check(d) {
+			return c.String() + " allows the denied version " + d.String()
+		}
+	}
+	return ""
+}
+
+// MaxAgeRule does not allow versions built longer ago than MaxAge. The build
+// time is read from the metadata by Timestamp, which by default reads the ts
+// key in the form 20060102150405, such as 1.2.3+build.7.ts.20240115093000.
+type MaxAgeRule struct {
+	MaxAge time.Duration
+
+	// Timestamp reads the build time from the metadata of a version.
+	Timestamp MetadataTimestamp
+
+	// RequireTimestamp breaks the rule for versions without a timestamp in
+	// their metadata. By default they are allowed.
+	RequireTimestamp bool
+
+	// Now returns the current time. time.Now is used when it is nil.
+	Now func() time.Time
+}
+
+// Name returns "max-age".
+func (MaxAgeRule) Name() string { return "max-age" }
+
+// CheckVersion breaks the rule when the build time of the version is older
+// than the maximum age.
+func (r MaxAgeRule) CheckVersion(v *Version) string {
+	now := time.Now
+	if r.Now != nil {
+		now = r.Now
+	}
+
+	age, ok := r.Timestamp.Age(v, now())
+	if !ok {
+		if r.RequireTimestamp {
+			return v.String() + " does not have a build timestamp"
+		}
+		return ""
+	}
+	if age > r.MaxAge {
+		return v.String() + " was built " + age.Truncate(time.Second).String() + " ago which is more than " + r.MaxAge.String()
+	}
+	return ""
+}
+
+// CheckConstraints never breaks the rule as constraints do not consider
+// metadata.
+func (MaxAgeRule) CheckConstraints(*Constraints) string { return "" }
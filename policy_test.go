@@ -0,0 +1,166 @@
+package semver
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPolicyEvaluateVersion(t *testing.T) {
+	now := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	p := &Policy{Rules: []PolicyRule{
+		NoPrereleaseRule{},
+		MinimumVersionRule{Floors: []*Version{MustParse("1.4.2"), MustParse("2.1.0")}},
+		DenyRule{Versions: []*Version{MustParse("2.3.0")}},
+		MaxAgeRule{MaxAge: 30 * 24 * time.Hour, Now: func() time.Time { return now }},
+	}}
+
+	tests := []struct {
+		version string
+		rules   []string
+	}{
+		{"1.4.2", nil},
+		{"3.0.0", nil},
+		{"1.4.1", []string{"minimum-version"}},
+		{"2.0.9-beta", []string{"no-prerelease", "minimum-version"}},
+		{"2.3.0+build.7", []string{"deny"}},
+		{"2.4.0+ts.20240115093000", nil},
+		{"2.4.0+ts.20231201000000", []string{"max-age"}},
+		{"2.4.0+build.10230415", nil},
+	}
+
+	for _, tc := range tests {
+		vd := p.EvaluateVersion(MustParse(tc.version))
+		var rules []string
+		for _, v := range vd.Violations {
+			rules = append(rules, v.Rule)
+		}
+		if !reflect.DeepEqual(rules, tc.rules) {
+			t.Errorf("Expected %s to break %v but got %v", tc.version, tc.rules, vd.Violations)
+		}
+		if vd.Allowed != (len(tc.rules) == 0) {
+			t.Errorf("Expected %s allowed to be %t", tc.version, len(tc.rules) == 0)
+		}
+	}
+
+	vd := p.EvaluateVersion(MustParse("2.4.0+ts.20231201000000"))
+	if e := "max-age: 2.4.0+ts.20231201000000 was built 1488h0m0s ago which is more than 720h0m0s"; vd.Violations[0].String() != e {
+		t.Errorf("Expected violation %q but got %q", e, vd.Violations[0])
+	}
+
+	custom := &Policy{Rules: []PolicyRule{MaxAgeRule{
+		MaxAge:    30 * 24 * time.Hour,
+		Timestamp: MetadataTimestamp{Layout: "20060102"},
+		Now:       func() time.Time { return now },
+	}}}
+	if custom.EvaluateVersion(MustParse("2.4.0+20231201")).Allowed {
+		t.Error("Expected the timestamp layout of the rule to be used")
+	}
+
+	strict := &Policy{Rules: []PolicyRule{MaxAgeRule{MaxAge: time.Hour, RequireTimestamp: true}}}
+	for _, v := range []string{"1.0.0", "1.2.3+build.10230415"} {
+		if strict.EvaluateVersion(MustParse(v)).Allowed {
+			t.Errorf("Expected %s without a timestamp to break the rule", v)
+		}
+	}
+}
+
+func TestPolicyEvaluateConstraints(t *testing.T) {
+	p := &Policy{Rules: []PolicyRule{
+		NoPrereleaseRule{},
+		MinimumVersionRule{Floors: []*Version{MustParse("1.4.2")}},
+		DenyRule{Versions: []*Version{MustParse("1.6.0")}},
+		MaxAgeRule{MaxAge: time.Hour},
+	}}
+
+	tests := []struct {
+		constraint string
+		rules      []string
+	}{
+		{">=1.4.2, <1.6.0", nil},
+		{"^1.4.2 || ^2", []string{"deny"}},
+		{"^1.4.1, <1.6", []string{"minimum-version"}},
+		{"^1.0.0, <1.6", []string{"minimum-version"}},
+		{">=1.5.0-0, <1.6", nil},
+		{">=1.5.0-0, <1.6.0-0", []string{"no-prerelease"}},
+		{">=1.3.5 <1.4", []string{"minimum-version"}},
+		{"~1.3.7", []string{"minimum-version"}},
+		{"!=1.5.0", []string{"no-prerelease", "minimum-version", "deny"}},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Errorf("err: %s", err)
+			continue
+		}
+		vd := p.EvaluateConstraints(c)
+		var rules []string
+		for _, v := range vd.Violations {
+			rules = append(rules, v.Rule)
+		}
+		if !reflect.DeepEqual(rules, tc.rules) {
+			t.Errorf("Expected %q to break %v but got %v", tc.constraint, tc.rules, vd.Violations)
+		}
+	}
+}
+
+func TestNoPrereleaseRuleConstraints(t *testing.T) {
+	tests := []struct {
+		constraint string
+		policy     PrereleasePolicy
+		broken     bool
+	}{
+		{">=1.0.0 <2", PrereleaseDefault, false},
+		{">=1.0.0-0 <2", PrereleaseDefault, false},
+		{">=1.2.0-beta", PrereleaseDefault, true},
+		{"!=1.2.3", PrereleaseDefault, true},
+		{">=1.0.0-0 <2", PrereleaseSameTuple, true},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Errorf("err: %s", err)
+			continue
+		}
+		c.PrereleasePolicy = tc.policy
+		if got := (NoPrereleaseRule{}).CheckConstraints(c) != ""; got != tc.broken {
+			t.Errorf("Expected %q with policy %v to break the rule to be %t", tc.constraint, tc.policy, tc.broken)
+		}
+	}
+}
+
+func TestAllowedRangeRule(t *testing.T) {
+	allowed, err := NewConstraint(">=1.0.0 <3.0.0")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	r := AllowedRangeRule{Constraint: allowed}
+
+	for v, e := range map[string]string{
+		"1.0.0": "",
+		"2.9.9": "",
+		"0.9.0": "0.9.0 is not in the allowed range >=1.0.0 <3.0.0",
+		"3.0.0": "3.0.0 is not in the allowed range >=1.0.0 <3.0.0",
+	} {
+		if msg := r.CheckVersion(MustParse(v)); msg != e {
+			t.Errorf("Expected %s to give %q but got %q", v, e, msg)
+		}
+	}
+
+	for c, e := range map[string]string{
+		"^1 || ^2":   "",
+		"~2.5":       "",
+		"^2 || ^3":   "^2 || ^3 allows >=3.0.0 <4.0.0 which is outside the allowed range >=1.0.0 <3.0.0",
+		">=0.9 <1.5": ">=0.9 <1.5 allows >=0.9.0 <1.0.0 which is outside the allowed range >=1.0.0 <3.0.0",
+	} {
+		con, err := NewConstraint(c)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if msg := r.CheckConstraints(con); msg != e {
+			t.Errorf("Expected %q to give %q but got %q", c, e, msg)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package semver
+
+// PrecompiledComparator is a comparator of constraints parsed ahead of time,
+// such as by the constgen command of cmd/semver, so Precompiled can build
+// the constraints without parsing them. It is meant for generated code.
+type PrecompiledComparator struct {
+	// Operator and Version are the comparator as written, such as ^ and
+	// 1.2.x.
+	Operator, Version string
+
+	// Major, Minor, Patch, Prerelease, and Metadata are the parts of the
+	// version compared against. The parts written as a wildcard are 0.
+	Major, Minor, Patch uint64
+	Prerelease          string
+	Metadata            string
+
+	// AnyMajor, AnyMinor, and AnyPatch are set when the version is a
+	// wildcard from that part on, such as AnyMinor for 1.x.
+	AnyMajor, AnyMinor, AnyPatch bool
+
+	// Start and End are the byte range of the comparator in the string the
+	// constraints were parsed from, see Comparator.
+	Start, End int
+}
+
+// Precompile returns the groups of AND comparators in the constraints, in
+// the order they were written, for Precompiled to build the same
+// constraints from. The PrereleasePolicy, Deny, and Trace fields and the
+// sources of the constraints are not included.
+func (cs Constraints) Precompile() [][]PrecompiledComparator {
+	groups := make([][]PrecompiledComparator, len(cs.constraints))
+	for k, o := range cs.constraints {
+		g := make([]PrecompiledComparator, len(o))
+		for i, c := range o {
+			g[i] = PrecompiledComparator{
+				Operator:   c.origfunc,
+				Version:    c.orig,
+				Major:      c.con.major,
+				Minor:      c.con.minor,
+				Patch:      c.con.patch,
+				Prerelease: c.con.pre,
+				Metadata:   c.con.metadata,
+				AnyMajor:   c.dirty && !c.minorDirty && !c.patchDirty,
+				AnyMinor:   c.minorDirty,
+				AnyPatch:   c.patchDirty,
+				Start:      c.start,
+				End:        c.end,
+			}
+		}
+		groups[k] = g
+	}
+	return groups
+}
+
+// Precompiled returns the constraints with the groups of AND comparators
+// returned by Constraints.Precompile, without parsing them. Generated code
+// uses it so constraint literals are parsed, and found to be invalid, when
+// the code is generated rather than at run time. The comparators are
+// trusted to come from Precompile.
+func Precompiled(groups ...[]PrecompiledComparator) *Constraints {
+	or := make([][]*constraint, len(groups))
+	for k, g := range groups {
+		o := make([]*constraint, len(g))
+		for i, p := range g {
+			o[i] = &constraint{
+				con:        New(p.Major, p.Minor, p.Patch, p.Prerelease, p.Metadata),
+				orig:       p.Version,
+				origfunc:   p.Operator,
+				minorDirty: p.AnyMinor,
+				patchDirty: p.AnyPatch,
+				dirty:      p.AnyMajor || p.AnyMinor || p.AnyPatch,
+				start:      p.Start,
+				end:        p.End,
+			}
+		}
+		or[k] = o
+	}
+	return newConstraints(or)
+}
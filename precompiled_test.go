@@ -0,0 +1,41 @@
+package semver
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPrecompiled(t *testing.T) {
+	versions := Collection{
+		MustParse("0.9.0"),
+		MustParse("1.2.0"),
+		MustParse("1.2.5-beta.1"),
+		MustParse("1.5.0"),
+		MustParse("2.0.0"),
+		MustParse("2.3.1"),
+		MustParse("3.0.0"),
+	}
+
+	for _, s := range []string{
+		"^1.2 || ~2.3",
+		"1.x",
+		"*",
+		">=1.2.5-beta <2, !=1.5.0",
+		"1.2 - 2.3.1",
+		"=v1.2.0+b1",
+	} {
+		cs := mustConstraint(t, s)
+		pc := Precompiled(cs.Precompile()...)
+		if pc.String() != cs.String() {
+			t.Errorf("Expected the precompiled %q to be %q but got %q", s, cs, pc)
+		}
+		if !reflect.DeepEqual(pc.Comparators(), cs.Comparators()) {
+			t.Errorf("Expected the precompiled %q to have the comparators %v but got %v", s, cs.Comparators(), pc.Comparators())
+		}
+		for _, v := range versions {
+			if pc.Check(v) != cs.Check(v) {
+				t.Errorf("Expected the precompiled %q with %s to be %t", s, v, cs.Check(v))
+			}
+		}
+	}
+}
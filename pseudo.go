@@ -0,0 +1,91 @@
+package semver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// The pseudo constraints allowed by ResolveConstraint. Each is resolved to
+// one of the available versions.
+const (
+	// PseudoLatest is the highest available version, including
+	// prereleases.
+	PseudoLatest = "latest"
+
+	// PseudoStable is the highest available version that is not a
+	// prerelease.
+	PseudoStable = "stable"
+)
+
+// ResolveConstraint parses constraints that may use the pseudo constraints
+// latest and stable, resolving each to the version it names from the
+// available versions, such as stable to =1.4.2. This lets configuration
+// like "channel: stable" or "stable || ^2.0.0-beta" use the constraint
+// system rather than special cases around it. Constraints without pseudo
+// constraints are parsed as they are by NewConstraint.
+//
+// A pseudo constraint is a group of its own between || separators, so it
+// can not be combined with other constraints using AND. The constraints are
+// resolved once, against the versions passed in, so callers need to call
+// ResolveConstraint again when the available versions change. When there is
+// no version for a pseudo constraint an error wrapping
+// ErrNoSatisfyingVersion is returned. The byte ranges of the comparators are
+// those of the resolved string, see Constraints.Comparators.
+func ResolveConstraint(c string, available Collection) (*Constraints, error) {
+	groups := strings.Split(c, "||")
+	resolved := false
+	for k, g := range groups {
+		var v *Version
+		switch strings.Trim(g, spaceChars) {
+		case PseudoLatest:
+			if i := available.IndexOfMax(); i >= 0 {
+				v = available[i]
+			}
+		case PseudoStable:
+			rel := available.FilterChannel("")
+			if i := rel.IndexOfMax(); i >= 0 {
+				v = rel[i]
+			}
+		default:
+			continue
+		}
+		if v == nil {
+			return nil, fmt.Errorf("%w: %s", ErrNoSatisfyingVersion, strings.Trim(g, spaceChars))
+		}
+		groups[k] = " =" + New(v.major, v.minor, v.patch, v.pre, "").String() + " "
+		resolved = true
+	}
+
+	if !resolved {
+		return NewConstraint(c)
+	}
+	return NewConstraint(strings.TrimSpace(strings.Join(groups, "||")))
+}
+
+// ResolveConstraintSource is ResolveConstraint with the available versions
+// listed from the source for the named package. The source is only used
+// when the constraints have a pseudo constraint.
+func ResolveConstraintSource(ctx context.Context, c string, src VersionSource, name string) (*Constraints, error) {
+	if !hasPseudoConstraint(c) {
+		return NewConstraint(c)
+	}
+
+	available, err := src.List(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return ResolveConstraint(c, available)
+}
+
+// hasPseudoConstraint reports if a group of the constraints is a pseudo
+// constraint.
+func hasPseudoConstraint(c string) bool {
+	for _, g := range strings.Split(c, "||") {
+		switch strings.Trim(g, spaceChars) {
+		case PseudoLatest, PseudoStable:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package semver
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// collectionSource is a VersionSource listing the same versions for every
+// package and counting the calls to List.
+type collectionSource struct {
+	versions Collection
+	calls    int
+}
+
+func (s *collectionSource) List(ctx context.Context, name string) (Collection, error) {
+	s.calls++
+	return s.versions, nil
+}
+
+func TestResolveConstraint(t *testing.T) {
+	available := Collection{
+		MustParse("1.2.0"),
+		MustParse("1.4.2+b7"),
+		nil,
+		MustParse("2.0.0-beta.1"),
+		MustParse("1.3.0"),
+	}
+
+	tests := []struct {
+		constraint string
+		expected   string
+	}{
+		{"stable", "=1.4.2"},
+		{" latest ", "=2.0.0-beta.1"},
+		{"stable || ^2.0.0-beta", "=1.4.2 || ^2.0.0-beta"},
+		{"~1.2 || latest", "~1.2 || =2.0.0-beta.1"},
+		{">=1.2", ">=1.2"},
+	}
+	for _, tc := range tests {
+		c, err := ResolveConstraint(tc.constraint, available)
+		if err != nil {
+			t.Errorf("Unexpected error resolving %q: %s", tc.constraint, err)
+			continue
+		}
+		if c.String() != tc.expected {
+			t.Errorf("Expected %q to resolve to %q but got %q", tc.constraint, tc.expected, c)
+		}
+	}
+
+	if _, err := ResolveConstraint("stable", Collection{MustParse("1.0.0-rc.1")}); !errors.Is(err, ErrNoSatisfyingVersion) {
+		t.Errorf("Expected %q but got %v", ErrNoSatisfyingVersion, err)
+	}
+	for _, c := range []string{"stable >=1.2", "stable-ish"} {
+		if _, err := ResolveConstraint(c, available); err == nil {
+			t.Errorf("Expected %q not to parse", c)
+		}
+	}
+
+	src := &collectionSource{versions: available}
+	c, err := ResolveConstraintSource(context.Background(), "stable", src, "app")
+	if err != nil || c.String() != "=1.4.2" {
+		t.Errorf("Expected stable to resolve to =1.4.2 but got %v and %v", c, err)
+	}
+	if _, err := ResolveConstraintSource(context.Background(), "^1.2", src, "app"); err != nil || src.calls != 1 {
+		t.Errorf("Expected the source not to be used without a pseudo constraint but got %d calls and %v", src.calls, err)
+	}
+}
@@ -0,0 +1,173 @@
+/*
+Package release models release channels, such as dev, beta, and stable, that
+versions are published to and promoted through.
+
+Each channel has constraints its versions need to satisfy. Channels only move
+forward, so a version published or promoted to a channel needs to be higher
+than the version the channel already has. A channel can strip prereleases so
+that promoting 1.2.0-rc.2 to it releases 1.2.0.
+
+	t, err := release.NewTrain(
+		release.Channel{Name: "beta", Constraint: beta},
+		release.Channel{Name: "stable", Constraint: stable, StripPrerelease: true},
+	)
+	err = t.Publish("beta", semver.MustParse("1.2.0-rc.2"))
+	v, err := t.Promote("beta", "stable")
+*/
+package release
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+var (
+	// ErrUnknownChannel is returned when a channel is not part of the train.
+	ErrUnknownChannel = errors.New("unknown channel")
+
+	// ErrEmptyChannel is returned when promoting from a channel that does not
+	// have a version.
+	ErrEmptyChannel = errors.New("channel has no version")
+
+	// ErrNotMonotonic is returned when a version is not higher than the
+	// version the channel already has.
+	ErrNotMonotonic = errors.New("version is not higher than the current version")
+
+	// ErrConstraint is returned when a version does not satisfy the
+	// constraints of a channel.
+	ErrConstraint = errors.New("version does not satisfy the channel constraints")
+
+	// ErrPromotionOrder is returned when promoting to a channel that is not
+	// after the channel being promoted from.
+	ErrPromotionOrder = errors.New("channels can only be promoted forward")
+)
+
+// Channel is a release channel.
+type Channel struct {
+	// Name identifies the channel, such as beta or stable.
+	Name string
+
+	// Constraint is satisfied by every version in the channel. A nil
+	// constraint allows any version.
+	Constraint *semver.Constraints
+
+	// StripPrerelease removes the prerelease and metadata from versions
+	// promoted to the channel. Versions published directly to the channel
+	// are not changed.
+	StripPrerelease bool
+}
+
+// Train is an ordered set of channels, from the least to the most stable,
+// along with the current version of each. A Train is safe for concurrent use.
+type Train struct {
+	mu       sync.Mutex
+	channels []Channel
+	index    map[string]int
+	current  map[string]*semver.Version
+}
+
+// NewTrain creates a train with the channels in order from the least to the
+// most stable. Channel names need to be unique and not empty.
+func NewTrain(channels ...Channel) (*Train, error) {
+	t := &Train{
+		channels: append([]Channel(nil), channels...),
+		index:    make(map[string]int, len(channels)),
+		current:  make(map[string]*semver.Version, len(channels)),
+	}
+	for i, c := range channels {
+		if c.Name == "" {
+			return nil, fmt.Errorf("channel %d has no name", i)
+		}
+		if _, ok := t.index[c.Name]; ok {
+			return nil, fmt.Errorf("channel %s is listed more than once", c.Name)
+		}
+		t.index[c.Name] = i
+	}
+	return t, nil
+}
+
+// Channels returns the names of the channels in order.
+func (t *Train) Channels() []string {
+	n := make([]string, len(t.channels))
+	for i, c := range t.channels {
+		n[i] = c.Name
+	}
+	return n
+}
+
+// Current returns the version of the channel or nil if it does not have one.
+func (t *Train) Current(channel string) (*semver.Version, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.index[channel]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownChannel, channel)
+	}
+	return t.current[channel], nil
+}
+
+// Publish sets the version of a channel. The version needs to satisfy the
+// channel constraints and be higher than the current version.
+func (t *Train) Publish(channel string, v *semver.Version) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	i, ok := t.index[channel]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownChannel, channel)
+	}
+	return t.set(t.channels[i], v)
+}
+
+// Promote copies the version of a channel to a later channel and returns the
+// version the later channel now has. If the later channel strips
+// prereleases the prerelease and metadata are removed. The resulting version
+// needs to satisfy the constraints of the later channel and be higher than
+// its current version.
+func (t *Train) Promote(from, to string) (*semver.Version, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	fi, ok := t.index[from]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownChannel, from)
+	}
+	ti, ok := t.index[to]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownChannel, to)
+	}
+	if ti <= fi {
+		return nil, fmt.Errorf("%w: %s is not after %s", ErrPromotionOrder, to, from)
+	}
+
+	v := t.current[from]
+	if v == nil {
+		return nil, fmt.Errorf("%w: %s", ErrEmptyChannel, from)
+	}
+
+	dest := t.channels[ti]
+	if dest.StripPrerelease {
+		v = semver.New(v.Major(), v.Minor(), v.Patch(), "", "")
+	}
+	if err := t.set(dest, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// set validates and sets the version of a channel. The lock is held by the
+// caller.
+func (t *Train) set(c Channel, v *semver.Version) error {
+	if c.Constraint != nil && !c.Constraint.Check(v) {
+		return fmt.Errorf("%w: %s does not satisfy %s for %s", ErrConstraint, v, c.Constraint, c.Name)
+	}
+	if cur := t.current[c.Name]; cur != nil && !v.GreaterThan(cur) {
+		return fmt.Errorf("%w: %s is not higher than %s in %s", ErrNotMonotonic, v, cur, c.Name)
+	}
+
+	t.current[c.Name] = v
+	return nil
+}
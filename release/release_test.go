@@ -0,0 +1,83 @@
+package release
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+func mustConstraint(t *testing.T, c string) *semver.Constraints {
+	t.Helper()
+	con, err := semver.NewConstraint(c)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return con
+}
+
+func TestTrain(t *testing.T) {
+	tr, err := NewTrain(
+		Channel{Name: "dev"},
+		Channel{Name: "beta", Constraint: mustConstraint(t, ">=1.0.0-0")},
+		Channel{Name: "stable", Constraint: mustConstraint(t, ">=1.0.0"), StripPrerelease: true},
+	)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if e := []string{"dev", "beta", "stable"}; !reflect.DeepEqual(tr.Channels(), e) {
+		t.Errorf("Expected channels %v but got %v", e, tr.Channels())
+	}
+
+	if _, err := tr.Promote("dev", "beta"); !errors.Is(err, ErrEmptyChannel) {
+		t.Errorf("Expected %q but got %v", ErrEmptyChannel, err)
+	}
+
+	if err := tr.Publish("dev", semver.MustParse("1.2.0-rc.1+build.5")); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	v, err := tr.Promote("dev", "beta")
+	if err != nil || v.String() != "1.2.0-rc.1+build.5" {
+		t.Errorf("Expected beta to be 1.2.0-rc.1+build.5 but got %v with error %v", v, err)
+	}
+	v, err = tr.Promote("beta", "stable")
+	if err != nil || v.String() != "1.2.0" {
+		t.Errorf("Expected stable to be 1.2.0 but got %v with error %v", v, err)
+	}
+	if cur, _ := tr.Current("dev"); cur.String() != "1.2.0-rc.1+build.5" {
+		t.Errorf("Expected dev to be unchanged but got %s", cur)
+	}
+
+	if _, err := tr.Promote("beta", "stable"); !errors.Is(err, ErrNotMonotonic) {
+		t.Errorf("Expected %q but got %v", ErrNotMonotonic, err)
+	}
+	if err := tr.Publish("dev", semver.MustParse("1.1.0")); !errors.Is(err, ErrNotMonotonic) {
+		t.Errorf("Expected %q but got %v", ErrNotMonotonic, err)
+	}
+	if err := tr.Publish("beta", semver.MustParse("0.9.0")); !errors.Is(err, ErrConstraint) {
+		t.Errorf("Expected %q but got %v", ErrConstraint, err)
+	}
+	if _, err := tr.Promote("stable", "beta"); !errors.Is(err, ErrPromotionOrder) {
+		t.Errorf("Expected %q but got %v", ErrPromotionOrder, err)
+	}
+	if _, err := tr.Current("nightly"); !errors.Is(err, ErrUnknownChannel) {
+		t.Errorf("Expected %q but got %v", ErrUnknownChannel, err)
+	}
+
+	if err := tr.Publish("dev", semver.MustParse("1.3.0-alpha")); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if v, err := tr.Promote("dev", "stable"); err != nil || v.String() != "1.3.0" {
+		t.Errorf("Expected stable to be 1.3.0 but got %v with error %v", v, err)
+	}
+}
+
+func TestNewTrainErrors(t *testing.T) {
+	if _, err := NewTrain(Channel{Name: "a"}, Channel{Name: "a"}); err == nil {
+		t.Error("Expected an error for duplicate channels")
+	}
+	if _, err := NewTrain(Channel{}); err == nil {
+		t.Error("Expected an error for a channel without a name")
+	}
+}
@@ -0,0 +1,118 @@
+package semver
+
+import (
+	"strings"
+)
+
+// ParseWithSuggestion parses a version in the same manner as NewVersion.
+// When the version can not be parsed it also returns a best effort
+// suggestion of the version that was meant, or an empty string when there
+// is none, so interactive tools can offer a "did you mean" correction rather
+// than only the error. For example, "1.2.3 " suggests 1.2.3, "v1..2"
+// suggests v1.2, and "1.2.3-beta_1" suggests 1.2.3-beta-1.
+//
+// The suggestion is built from fixes for common mistakes: surrounding
+// whitespace and quotes, an uppercase V or a leading =, repeated or
+// trailing dots, leading zeros, and characters that are not allowed in the
+// prerelease or metadata. A suggestion always parses with NewVersion but it
+// may not be what was meant, so it should be confirmed rather than used in
+// place of the input.
+func ParseWithSuggestion(v string) (*Version, string, error) {
+	sv, err := NewVersion(v)
+	if err == nil {
+		return sv, "", nil
+	}
+
+	s := repairVersion(v)
+	if s == v {
+		return nil, "", err
+	}
+	if _, serr := NewVersion(s); serr != nil {
+		return nil, "", err
+	}
+	return nil, s, err
+}
+
+// repairVersion applies the fixes described by ParseWithSuggestion.
+func repairVersion(v string) string {
+	s := strings.Trim(v, spaceChars)
+	s = strings.Trim(s, "\"'`")
+	s = strings.TrimPrefix(s, "=")
+	s = strings.Trim(s, spaceChars)
+	if strings.HasPrefix(s, "V") {
+		s = "v" + s[1:]
+	}
+
+	prefix := ""
+	if strings.HasPrefix(s, "v") {
+		prefix, s = "v", s[1:]
+	}
+	core, meta, hasMeta := strings.Cut(s, "+")
+	core, pre, hasPre := strings.Cut(core, "-")
+
+	var parts []string
+	for _, p := range strings.Split(core, ".") {
+		if p == "" {
+			continue
+		}
+		if containsOnly(p, num) {
+			p = trimLeadingZeros(p)
+		}
+		parts = append(parts, p)
+	}
+	s = prefix + strings.Join(parts, ".")
+
+	if hasPre {
+		if pre = repairIdentifiers(pre, true); pre != "" {
+			s += "-" + pre
+		}
+	}
+	if hasMeta {
+		if meta = repairIdentifiers(meta, false); meta != "" {
+			s += "+" + meta
+		}
+	}
+	return s
+}
+
+// repairIdentifiers fixes the dot separated identifiers of a prerelease or
+// metadata. Empty identifiers are removed and runs of characters that are
+// not allowed are replaced with a hyphen. Leading zeros are removed from
+// numeric prerelease identifiers.
+func repairIdentifiers(s string, prerelease bool) string {
+	var ids []string
+	for _, id := range strings.Split(s, ".") {
+		var sb strings.Builder
+		bad := false
+		for i := 0; i < len(id); i++ {
+			if strings.IndexByte(allowed, id[i]) >= 0 {
+				if bad && sb.Len() > 0 {
+					sb.WriteByte('-')
+				}
+				bad = false
+				sb.WriteByte(id[i])
+				continue
+			}
+			bad = true
+		}
+		id = sb.String()
+		if id == "" {
+			continue
+		}
+		if prerelease && containsOnly(id, num) {
+			id = trimLeadingZeros(id)
+		}
+		ids = append(ids, id)
+	}
+	return strings.Join(ids, ".")
+}
+
+// trimLeadingZeros removes the leading zeros from a number, leaving a single
+// 0 for zero.
+func trimLeadingZeros(n string) string {
+	n = strings.TrimLeft(n, "0")
+	if n == "" {
+		return "0"
+	}
+	return n
+}
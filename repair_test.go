@@ -0,0 +1,47 @@
+package semver
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseWithSuggestion(t *testing.T) {
+	tests := []struct {
+		v          string
+		suggestion string
+	}{
+		{"1.2.3 ", "1.2.3"},
+		{"v1..2", "v1.2"},
+		{"V1.2.3", "v1.2.3"},
+		{"\"1.2.3\"", "1.2.3"},
+		{"=1.2.3", "1.2.3"},
+		{"1.2.", "1.2"},
+		{"01.02.3", "1.2.3"},
+		{"1.2.3-beta_1", "1.2.3-beta-1"},
+		{"1.2.3-beta.01", "1.2.3-beta.1"},
+		{"1.2.3-rc..1+build/7", "1.2.3-rc.1+build-7"},
+		{"1.2.3-", "1.2.3"},
+		{"1.2.3.4", ""},
+		{"one", ""},
+		{"", ""},
+	}
+
+	for _, tc := range tests {
+		v, s, err := ParseWithSuggestion(tc.v)
+		if v != nil || err == nil {
+			t.Errorf("Expected %q not to parse", tc.v)
+		}
+		if s != tc.suggestion {
+			t.Errorf("Expected the suggestion for %q to be %q but got %q", tc.v, tc.suggestion, s)
+		}
+	}
+
+	v, s, err := ParseWithSuggestion("v1.2.3-beta.1")
+	if err != nil || s != "" || v.Original() != "v1.2.3-beta.1" {
+		t.Errorf("Expected a valid version without a suggestion but got %v, %q, and %v", v, s, err)
+	}
+
+	if _, _, err := ParseWithSuggestion("v1..2"); !errors.Is(err, ErrInvalidSemVer) {
+		t.Errorf("Expected %q but got %v", ErrInvalidSemVer, err)
+	}
+}
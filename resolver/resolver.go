@@ -0,0 +1,244 @@
+/*
+Package resolver selects versions of packages that satisfy a set of
+requirements along with the requirements of the selected versions.
+
+Packages and their versions are provided by a Source. Resolve searches the
+versions, preferring the highest version of each package, and backtracks when
+a selection leads to a conflict. When no selection satisfies the requirements
+a *ConflictError explains which requirements could not be met together.
+
+	sel, err := resolver.Resolve(src, map[string]*semver.Constraints{
+		"example": c,
+	})
+*/
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// Source provides the available versions of packages and the requirements of
+// each version.
+type Source interface {
+	// Versions returns the available versions of a package.
+	Versions(name string) (semver.Collection, error)
+
+	// Dependencies returns the requirements of a version of a package keyed
+	// by the name of the required package.
+	Dependencies(name string, v *semver.Version) (map[string]*semver.Constraints, error)
+}
+
+// Requirement is a constraint on the versions of a package.
+type Requirement struct {
+	// From is the package and version that has the requirement, such as
+	// foo@1.2.3. It is empty for the requirements passed to Resolve.
+	From string
+
+	// Constraint is the constraint the version needs to satisfy.
+	Constraint *semver.Constraints
+}
+
+// String returns the requirement in the form used in error messages.
+func (r Requirement) String() string {
+	if r.From == "" {
+		return "requires " + r.Constraint.String()
+	}
+	return r.From + " requires " + r.Constraint.String()
+}
+
+// ConflictError is returned by Resolve when no version of a package
+// satisfies all of its requirements.
+type ConflictError struct {
+	// Package is the name of the package a version could not be found for.
+	Package string
+
+	// Requirements are the requirements on the package at the point the
+	// conflict was found.
+	Requirements []Requirement
+}
+
+func (e *ConflictError) Error() string {
+	r := make([]string, len(e.Requirements))
+	for i, req := range e.Requirements {
+		r[i] = req.String()
+	}
+	return fmt.Sprintf("no version of %s satisfies: %s", e.Package, strings.Join(r, ", "))
+}
+
+// Resolve selects a version of every package that is required, directly or
+// by a selected version, so that all of the requirements are satisfied. The
+// highest versions are preferred. The selected versions are keyed by package
+// name.
+//
+// When no selection exists the error is a *ConflictError. Errors returned by
+// the source stop the search and are returned as is. A requirement with nil
+// constraints, passed in or from the source, is an error.
+func Resolve(src Source, reqs map[string]*semver.Constraints) (map[string]*semver.Version, error) {
+	s := &solver{
+		src:      src,
+		versions: make(map[string]semver.Collection),
+	}
+
+	r := make(map[string][]Requirement, len(reqs))
+	for name, c := range reqs {
+		if c == nil {
+			return nil, fmt.Errorf("requirement on %s has no constraint", name)
+		}
+		r[name] = []Requirement{{Constraint: c}}
+	}
+
+	sel := make(map[string]*semver.Version)
+	if err := s.solve(sel, r); err != nil {
+		return nil, err
+	}
+	return sel, nil
+}
+
+// solver holds the state shared across the search.
+type solver struct {
+	src Source
+
+	// versions caches the versions of each package sorted from highest to
+	// lowest.
+	versions map[string]semver.Collection
+}
+
+// solve selects versions for the packages in reqs that are not yet in sel.
+// On success sel holds the selection. On failure sel is restored.
+func (s *solver) solve(sel map[string]*semver.Version, reqs map[string][]Requirement) error {
+	name, ok := nextPackage(sel, reqs)
+	if !ok {
+		return nil
+	}
+
+	vs, err := s.list(name)
+	if err != nil {
+		return err
+	}
+
+	var conflict error = &ConflictError{Package: name, Requirements: reqs[name]}
+	c := intersect(reqs[name])
+	if c.IsEmpty() {
+		return conflict
+	}
+	for _, v := range vs {
+		if !c.Check(v) {
+			continue
+		}
+
+		deps, err := s.src.Dependencies(name, v)
+		if err != nil {
+			return err
+		}
+
+		next, err := addRequirements(sel, reqs, name+"@"+v.String(), deps)
+		if err != nil {
+			var ce *ConflictError
+			if !errors.As(err, &ce) {
+				return err
+			}
+			conflict = err
+			continue
+		}
+
+		sel[name] = v
+		err = s.solve(sel, next)
+		if err == nil {
+			return nil
+		}
+		delete(sel, name)
+
+		var ce *ConflictError
+		if !errors.As(err, &ce) {
+			return err
+		}
+		conflict = err
+	}
+
+	return conflict
+}
+
+// list returns the versions of a package from highest to lowest.
+func (s *solver) list(name string) (semver.Collection, error) {
+	if vs, ok := s.versions[name]; ok {
+		return vs, nil
+	}
+
+	vs, err := s.src.Versions(name)
+	if err != nil {
+		return nil, err
+	}
+	sorted := make(semver.Collection, 0, len(vs))
+	for _, v := range vs {
+		if v != nil {
+			sorted = append(sorted, v)
+		}
+	}
+	sort.Sort(sort.Reverse(sorted))
+
+	s.versions[name] = sorted
+	return sorted, nil
+}
+
+// nextPackage returns the first package, by name, that is required and does
+// not have a version selected. Going in name order keeps the search
+// deterministic.
+func nextPackage(sel map[string]*semver.Version, reqs map[string][]Requirement) (string, bool) {
+	name, found := "", false
+	for n := range reqs {
+		if _, ok := sel[n]; ok {
+			continue
+		}
+		if !found || n < name {
+			name, found = n, true
+		}
+	}
+	return name, found
+}
+
+// addRequirements returns a copy of reqs with the dependencies of a selected
+// version added. If a dependency is on a package that already has a version
+// selected that does not satisfy it a *ConflictError is returned.
+func addRequirements(sel map[string]*semver.Version, reqs map[string][]Requirement, from string, deps map[string]*semver.Constraints) (map[string][]Requirement, error) {
+	next := make(map[string][]Requirement, len(reqs)+len(deps))
+	for n, r := range reqs {
+		next[n] = r
+	}
+
+	names := make([]string, 0, len(deps))
+	for n := range deps {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	for _, n := range names {
+		if deps[n] == nil {
+			return nil, fmt.Errorf("requirement of %s on %s has no constraint", from, n)
+		}
+		r := make([]Requirement, len(next[n]), len(next[n])+1)
+		copy(r, next[n])
+		r = append(r, Requirement{From: from, Constraint: deps[n]})
+		next[n] = r
+
+		if v, ok := sel[n]; ok && !deps[n].Check(v) {
+			return nil, &ConflictError{Package: n, Requirements: r}
+		}
+	}
+
+	return next, nil
+}
+
+// intersect returns the constraints allowing the versions that satisfy all
+// of the requirements.
+func intersect(reqs []Requirement) *semver.Constraints {
+	cs := make([]*semver.Constraints, len(reqs))
+	for i, r := range reqs {
+		cs[i] = r.Constraint
+	}
+	return semver.IntersectionAll(cs)
+}
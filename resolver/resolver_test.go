@@ -0,0 +1,158 @@
+package resolver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// testSource is a Source with packages described as strings. Each package
+// name maps versions to their dependencies, which map a package name to a
+// constraint.
+type testSource map[string]map[string]map[string]string
+
+func (s testSource) Versions(name string) (semver.Collection, error) {
+	p, ok := s[name]
+	if !ok {
+		return nil, errors.New("unknown package " + name)
+	}
+	var vs semver.Collection
+	for v := range p {
+		vs = append(vs, semver.MustParse(v))
+	}
+	return vs, nil
+}
+
+func (s testSource) Dependencies(name string, v *semver.Version) (map[string]*semver.Constraints, error) {
+	deps := make(map[string]*semver.Constraints)
+	for n, c := range s[name][v.Original()] {
+		con, err := semver.NewConstraint(c)
+		if err != nil {
+			return nil, err
+		}
+		deps[n] = con
+	}
+	return deps, nil
+}
+
+func requirements(t *testing.T, reqs map[string]string) map[string]*semver.Constraints {
+	t.Helper()
+	r := make(map[string]*semver.Constraints, len(reqs))
+	for n, c := range reqs {
+		con, err := semver.NewConstraint(c)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		r[n] = con
+	}
+	return r
+}
+
+func TestResolve(t *testing.T) {
+	src := testSource{
+		"app": {
+			"1.0.0": {"lib": "^1.0.0", "log": "^2.0.0"},
+			"1.1.0": {"lib": "^1.2.0", "log": "^3.0.0"},
+		},
+		"lib": {
+			"1.0.0": {},
+			"1.2.0": {"log": "~2.1.0"},
+			"1.3.0": {"log": "^3.0.0"},
+			"2.0.0": {},
+		},
+		"log": {
+			"2.0.0":      {},
+			"2.1.4":      {},
+			"2.2.0":      {},
+			"3.0.0-beta": {},
+		},
+	}
+
+	tests := []struct {
+		reqs     map[string]string
+		expected map[string]string
+	}{
+		{
+			map[string]string{"lib": "*"},
+			map[string]string{"lib": "2.0.0"},
+		},
+		{
+			// log 3 is only a prerelease so app 1.1.0 is skipped and lib
+			// backtracks to 1.2.0 for a log that app 1.0.0 allows.
+			map[string]string{"app": "^1.0.0"},
+			map[string]string{"app": "1.0.0", "lib": "1.2.0", "log": "2.1.4"},
+		},
+		{
+			map[string]string{"app": "^1.0.0", "log": "2.0.0"},
+			map[string]string{"app": "1.0.0", "lib": "1.0.0", "log": "2.0.0"},
+		},
+	}
+
+	for _, tc := range tests {
+		sel, err := Resolve(src, requirements(t, tc.reqs))
+		if err != nil {
+			t.Errorf("Unexpected error resolving %v: %s", tc.reqs, err)
+			continue
+		}
+
+		if len(sel) != len(tc.expected) {
+			t.Errorf("Expected %v for %v but got %v", tc.expected, tc.reqs, sel)
+			continue
+		}
+		for n, v := range tc.expected {
+			if sel[n] == nil || sel[n].String() != v {
+				t.Errorf("Expected %s@%s for %v but got %v", n, v, tc.reqs, sel[n])
+			}
+		}
+	}
+}
+
+func TestResolveConflict(t *testing.T) {
+	src := testSource{
+		"a": {"1.0.0": {"c": "^1.0.0"}},
+		"b": {"1.0.0": {"c": "^2.0.0"}},
+		"c": {"1.0.0": {}, "2.0.0": {}},
+	}
+
+	_, err := Resolve(src, requirements(t, map[string]string{"a": "*", "b": "*"}))
+	var ce *ConflictError
+	if !errors.As(err, &ce) {
+		t.Fatalf("Expected a conflict error but got %v", err)
+	}
+	if ce.Package != "c" {
+		t.Errorf("Expected conflict on c but got %s", ce.Package)
+	}
+	e := "no version of c satisfies: a@1.0.0 requires ^1.0.0, b@1.0.0 requires ^2.0.0"
+	if err.Error() != e {
+		t.Errorf("Expected error %q but got %q", e, err)
+	}
+
+	_, err = Resolve(src, requirements(t, map[string]string{"a": "*", "missing": "*"}))
+	if err == nil || errors.As(err, &ce) {
+		t.Errorf("Expected the source error but got %v", err)
+	}
+}
+
+// nilDepSource is a testSource whose versions require c with nil
+// constraints.
+type nilDepSource struct{ testSource }
+
+func (s nilDepSource) Dependencies(name string, v *semver.Version) (map[string]*semver.Constraints, error) {
+	return map[string]*semver.Constraints{"c": nil}, nil
+}
+
+func TestResolveNilConstraint(t *testing.T) {
+	src := testSource{"a": {"1.0.0": {}}, "c": {"1.0.0": {}}}
+
+	var ce *ConflictError
+	_, err := Resolve(src, map[string]*semver.Constraints{"a": nil})
+	if err == nil || errors.As(err, &ce) {
+		t.Errorf("Expected an error for a nil constraint but got %v", err)
+	}
+
+	_, err = Resolve(nilDepSource{src}, requirements(t, map[string]string{"a": "*"}))
+	if e := "requirement of a@1.0.0 on c has no constraint"; err == nil || err.Error() != e {
+		t.Errorf("Expected error %q but got %v", e, err)
+	}
+}
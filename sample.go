@@ -0,0 +1,231 @@
+package semver
+
+import "sort"
+
+// SampleOptions configures Constraints.Sample.
+type SampleOptions struct {
+	// Prereleases includes prerelease versions at the edges of the ranges,
+	// such as 1.2.0-beta.1 for >=1.2.0-beta.1, when the constraints allow
+	// them.
+	Prereleases bool
+
+	// Available, when set, limits the samples to these versions, such as
+	// the published versions of a package. Otherwise versions are made up
+	// from the bounds of the ranges the constraints allow.
+	Available Collection
+}
+
+// Sample returns up to n versions allowed by the constraints spread across
+// the ranges they allow, in ascending order. The lowest and highest versions
+// are always included when n is at least 2 with the rest chosen evenly in
+// between. When n is 1 the highest version is returned. This is useful for
+// test matrices, such as testing against the oldest, newest, and a middle
+// supported version.
+//
+// Without Available the versions are the bounds of each range along with a
+// version in the middle. As there is no highest version below an exclusive
+// upper bound, such as <2.0.0, a release with a lower minor or patch
+// version stands in for it, such as 1.0.0, or for ^1.2 the next minor
+// version 1.3.0. The highest version of a range without an upper bound is
+// the start of the next major version after its lower bound.
+func (cs Constraints) Sample(n int, opts SampleOptions) Collection {
+	if n <= 0 {
+		return nil
+	}
+
+	var candidates Collection
+	if opts.Available != nil {
+		for _, v := range opts.Available {
+			if v != nil && (opts.Prereleases || v.pre == "") {
+				candidates = append(candidates, v)
+			}
+		}
+	} else {
+		candidates = cs.sampleCandidates(opts.Prereleases)
+	}
+
+	var all Collection
+	for _, v := range candidates {
+		if cs.Check(v) {
+			all = append(all, v)
+		}
+	}
+	sort.Sort(all)
+	all = uniqueVersions(all)
+
+	if len(all) <= n {
+		return all
+	}
+	if n == 1 {
+		return all[len(all)-1:]
+	}
+	out := make(Collection, n)
+	for i := range out {
+		out[i] = all[i*(len(all)-1)/(n-1)]
+	}
+	return out
+}
+
+// sampleCandidates returns versions at the bounds and middle of each range
+// the constraints allow. Not all of them are allowed, such as a prerelease
+// at a bound the constraints are not looking for prereleases at.
+func (cs Constraints) sampleCandidates(prereleases bool) Collection {
+	var out Collection
+	for _, iv := range cs.intervalSet() {
+		lo := New(0, 0, 0, "", "")
+		if iv.lo != nil {
+			lo = iv.lo
+			if !iv.loInc {
+				lo = sampleAbove(iv.lo)
+			}
+		}
+
+		var hi *Version
+		switch {
+		case iv.hi == nil:
+			hi = New(lo.major+1, 0, 0, "", "")
+		case iv.hiInc:
+			hi = iv.hi
+		default:
+			hi = sampleBelow(iv.hi, lo)
+		}
+		if hi.LessThan(lo) {
+			hi = lo
+		}
+
+		if lo.pre != "" && !prereleases {
+			lo = New(lo.major, lo.minor, lo.patch, "", "")
+		}
+		if hi.pre != "" && !prereleases {
+			hi = New(hi.major, hi.minor, hi.patch, "", "")
+		}
+		out = append(out, lo, sampleMiddle(lo, hi), hi)
+	}
+	return out
+}
+
+// sampleAbove returns the closest release version above v.
+func sampleAbove(v *Version) *Version {
+	if v.pre != "" {
+		return New(v.major, v.minor, v.patch, "", "")
+	}
+	return New(v.major, v.minor, v.patch+1, "", "")
+}
+
+// sampleBelow returns a release version below v, see boundaryBelow. When
+// that is not above lo, the minor or patch version of lo is raised instead
+// as long as the result stays below v. Otherwise it is lo.
+func sampleBelow(v, lo *Version) *Version {
+	if b := boundaryBelow(v); b != nil && b.GreaterThan(lo) && b.LessThan(v) {
+		return b
+	}
+
+	for _, b := range []*Version{
+		New(lo.major, lo.minor+1, 0, "", ""),
+		New(lo.major, lo.minor, lo.patch+1, "", ""),
+	} {
+		if b.LessThan(v) {
+			return b
+		}
+	}
+	return lo
+}
+
+// sampleMiddle returns a release version half way between lo and hi in the
+// most significant part where they differ. When that is not above lo, the
+// next part of lo is raised by one instead.
+func sampleMiddle(lo, hi *Version) *Version {
+	var m, next *Version
+	switch {
+	case lo.major != hi.major:
+		m = New(lo.major+(hi.major-lo.major)/2, 0, 0, "", "")
+		next = New(lo.major, lo.minor+1, 0, "", "")
+	case lo.minor != hi.minor:
+		m = New(lo.major, lo.minor+(hi.minor-lo.minor)/2, 0, "", "")
+		next = New(lo.major, lo.minor, lo.patch+1, "", "")
+	default:
+		return New(lo.major, lo.minor, lo.patch+(hi.patch-lo.patch)/2, "", "")
+	}
+	if !m.GreaterThan(lo) {
+		return next
+	}
+	return m
+}
+
+// uniqueVersions removes consecutive versions that are equal from a sorted
+// collection.
+func uniqueVersions(vs Collection) Collection {
+	out := vs[:0]
+	for _, v := range vs {
+		if len(out) == 0 || !out[len(out)-1].Equal(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// BoundaryVersions returns the versions just inside and just outside each
+// bound of the ranges the constraints allow, in ascending order. For <2.0.0
+// that is 2.0.0, the prerelease 2.0.0-0, and 1.0.0 as a release below it,
+// and for >=1.2.3 it is 1.2.3, 1.2.4, 1.2.3-0, and 1.2.2. Versions excluded
+// by != constraints and the deny list are bounds as well. This helps test
+// suites cover off by one errors in ranges. Check each version against the
+// constraints to find out which are allowed.
+func (cs Constraints) BoundaryVersions() Collection {
+	var out Collection
+	for _, iv := range cs.intervalSet() {
+		if lo := iv.lo; lo != nil {
+			a := sampleAbove(lo)
+			out = append(out, lo, a)
+			if iv.loInc {
+				out = append(out, boundaryPrerelease(lo), boundaryBelow(lo))
+			} else {
+				out = append(out, boundaryPrerelease(a))
+			}
+		}
+
+		if hi := iv.hi; hi != nil {
+			a := sampleAbove(hi)
+			out = append(out, hi, boundaryBelow(hi))
+			if iv.hiInc {
+				out = append(out, a, boundaryPrerelease(a))
+			} else {
+				out = append(out, boundaryPrerelease(hi))
+			}
+		}
+	}
+
+	var res Collection
+	for _, v := range out {
+		if v != nil {
+			res = append(res, v)
+		}
+	}
+	sort.Sort(res)
+	return uniqueVersions(res)
+}
+
+// boundaryPrerelease returns the lowest prerelease of a release version. It
+// is nil when v is a prerelease.
+func boundaryPrerelease(v *Version) *Version {
+	if v.pre != "" {
+		return nil
+	}
+	return SmallestPrereleaseAbove(v)
+}
+
+// boundaryBelow returns a release version below v, lowering the last
+// non-zero part of it. The prerelease of v is not taken into account so
+// 1.1.0 is returned for 1.2.0-beta. It is nil when there is no release
+// below v.
+func boundaryBelow(v *Version) *Version {
+	switch {
+	case v.patch > 0:
+		return New(v.major, v.minor, v.patch-1, "", "")
+	case v.minor > 0:
+		return New(v.major, v.minor-1, 0, "", "")
+	case v.major > 0:
+		return New(v.major-1, 0, 0, "", "")
+	}
+	return nil
+}
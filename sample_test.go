@@ -0,0 +1,90 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestConstraintsSample(t *testing.T) {
+	tests := []struct {
+		constraint string
+		n          int
+		pre        bool
+		expected   string
+	}{
+		{"^1.2", 3, false, "1.2.0 1.2.1 1.3.0"},
+		{">=1.2 <3", 3, false, "1.2.0 1.3.0 2.0.0"},
+		{">=1.0.0 <=1.8.0", 3, false, "1.0.0 1.4.0 1.8.0"},
+		{">=1.0.0 <=1.8.0", 2, false, "1.0.0 1.8.0"},
+		{">=1.0.0 <=1.8.0", 1, false, "1.8.0"},
+		{">=1.0.0 <=1.8.0", 0, false, ""},
+		{"~1.2.3", 5, false, "1.2.3 1.2.4"},
+		{">1.2.3 <=1.2.9", 5, false, "1.2.4 1.2.6 1.2.9"},
+		{"<1.5.0", 5, false, "0.0.0 0.1.0 1.4.0"},
+		{">=2.1", 5, false, "2.1.0 2.2.0 3.0.0"},
+		{"^1 || ^3", 10, false, "1.0.0 1.0.1 1.1.0 3.0.0 3.0.1 3.1.0"},
+		{"1.2.3", 3, false, "1.2.3"},
+		{">=1.2.0-beta.1 <1.3.0-0", 5, true, "1.2.0-beta.1 1.2.0"},
+		{">=1.2.0-beta.1 <1.3.0", 5, true, "1.2.0"},
+		{">=1.2.0-beta.1 <1.3.0", 5, false, "1.2.0"},
+		{">=1.0.0 <=1.8.0 !=1.4.0", 3, false, "1.0.0 1.3.0 1.8.0"},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		got := versionsString(c.Sample(tc.n, SampleOptions{Prereleases: tc.pre}))
+		if got != tc.expected {
+			t.Errorf("Expected %q sampled with %d to be %q but got %q", tc.constraint, tc.n, tc.expected, got)
+		}
+	}
+
+	c, _ := NewConstraint("^1.2")
+	available := Collection{
+		MustParse("1.1.0"), MustParse("1.2.0"), MustParse("1.2.1"), MustParse("1.3.0-rc.1"),
+		MustParse("1.3.0"), MustParse("1.4.0"), MustParse("1.5.2"), nil, MustParse("2.0.0"),
+	}
+	if got := versionsString(c.Sample(3, SampleOptions{Available: available})); got != "1.2.0 1.3.0 1.5.2" {
+		t.Errorf("Expected the oldest, middle, and newest available but got %q", got)
+	}
+}
+
+func versionsString(vs Collection) string {
+	s := ""
+	for i, v := range vs {
+		if i > 0 {
+			s += " "
+		}
+		s += v.String()
+	}
+	return s
+}
+
+func TestConstraintsBoundaryVersions(t *testing.T) {
+	tests := []struct {
+		constraint string
+		expected   string
+	}{
+		{"<2.0.0", "1.0.0 2.0.0-0 2.0.0"},
+		{">=1.2.3", "1.2.2 1.2.3-0 1.2.3 1.2.4"},
+		{">1.2.3 <=1.5.0", "1.2.3 1.2.4-0 1.2.4 1.4.0 1.5.0 1.5.1-0 1.5.1"},
+		{"1.2.3 || 1.2.5", "1.2.2 1.2.3-0 1.2.3 1.2.4-0 1.2.4 1.2.5-0 1.2.5 1.2.6-0 1.2.6"},
+		{"^1.2 !=1.4.0", "1.0.0 1.1.0 1.2.0-0 1.2.0 1.2.1 1.3.0 1.4.0-0 1.4.0 1.4.1-0 1.4.1 2.0.0-0 2.0.0"},
+		{">=1.2.0-beta.1 <1.3.0-0", "1.1.0 1.2.0-beta.1 1.2.0 1.3.0-0"},
+		{"*", ""},
+		{">=0.0.0", "0.0.0-0 0.0.0 0.0.1"},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		if got := versionsString(c.BoundaryVersions()); got != tc.expected {
+			t.Errorf("Expected boundaries of %q to be %q but got %q", tc.constraint, tc.expected, got)
+		}
+	}
+}
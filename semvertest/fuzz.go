@@ -0,0 +1,118 @@
+package semvertest
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// VersionSeeds is a seed corpus of versions for FuzzParseVersion, covering
+// the forms NewVersion accepts and some it does not.
+var VersionSeeds = []string{
+	"1.2.3",
+	"v1.2.3",
+	"1",
+	"1.2",
+	"1.2.3-beta.1",
+	"1.2.3+foo",
+	"2.3.4-alpha.1+bar",
+	"1.2.3-0.a.00",
+	"1.2.3-beta..1",
+	"01.2.3",
+	"1.2.3.4",
+	" ",
+	"......",
+	"lorem ipsum",
+}
+
+// ConstraintSeeds is a seed corpus of constraints for FuzzParseConstraint,
+// covering each operator, wildcards, hyphen ranges, and some invalid
+// constraints.
+var ConstraintSeeds = []string{
+	"*",
+	"1.x",
+	"2.3.x",
+	"v1.2.3",
+	"=1.2.3",
+	"!=1.2.3",
+	">=1.2 <2",
+	">= 1, <= 3.4",
+	"=>1.2, =<1.4",
+	"^4.5",
+	"~9.8.7",
+	"~>1.2",
+	"1.0.0 - 2",
+	"^1.2 || >=2.1.0-beta.1 <3",
+	"9.2-beta.0",
+	"1.2.3.4.5.6",
+	"987654321.123456789.654123789",
+	"lorem ipsum",
+}
+
+// FuzzParseVersion checks the invariants of parsing data as a version. When
+// NewVersion accepts it the string form of the version must parse to an
+// equal version with the same prerelease and metadata, and be written the
+// same way again. When StrictNewVersion accepts it NewVersion must accept it
+// as the same version. Downstream projects can call it from their own fuzz
+// tests to cover the versions they handle:
+//
+//	func FuzzVersions(f *testing.F) {
+//		for _, s := range semvertest.VersionSeeds {
+//			f.Add([]byte(s))
+//		}
+//		f.Fuzz(semvertest.FuzzParseVersion)
+//	}
+func FuzzParseVersion(t *testing.T, data []byte) {
+	t.Helper()
+	s := string(data)
+	v, err := semver.NewVersion(s)
+	if sv, serr := semver.StrictNewVersion(s); serr == nil {
+		if err != nil {
+			t.Fatalf("%q is accepted by StrictNewVersion but not NewVersion: %s", s, err)
+		}
+		if sv.Compare(v) != 0 || sv.Metadata() != v.Metadata() {
+			t.Errorf("%q is parsed as %s by StrictNewVersion but %s by NewVersion", s, sv, v)
+		}
+	}
+	if err != nil {
+		return
+	}
+
+	str := v.String()
+	rt, err := semver.NewVersion(str)
+	if err != nil {
+		t.Fatalf("%q is written as %q which does not parse: %s", s, str, err)
+	}
+	if !rt.Equal(v) || rt.Prerelease() != v.Prerelease() || rt.Metadata() != v.Metadata() {
+		t.Errorf("%q is written as %q which parses as %s", s, str, rt)
+	}
+	if rt.String() != str {
+		t.Errorf("%q is written as %q and then %q", s, str, rt.String())
+	}
+}
+
+// FuzzParseConstraint checks the invariants of parsing data as constraints.
+// When NewConstraint accepts it the string form of the constraints must
+// parse to equivalent constraints, see semver.VerifyRoundTrip, and be
+// written the same way again. It is used in the same way as
+// FuzzParseVersion with ConstraintSeeds.
+func FuzzParseConstraint(t *testing.T, data []byte) {
+	t.Helper()
+	s := string(data)
+	c, err := semver.NewConstraint(s)
+	if err != nil {
+		return
+	}
+
+	if err := semver.VerifyRoundTrip(c); err != nil {
+		t.Fatalf("%q does not round trip: %s", s, err)
+	}
+	str := c.String()
+	rt, err := semver.NewConstraint(str)
+	if err != nil {
+		t.Fatalf("%q is written as %q which does not parse: %s", s, str, err)
+	}
+	if rt.String() != str {
+		t.Errorf("%q is written as %q and then %q", s, str, rt.String())
+	}
+}
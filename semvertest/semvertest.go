@@ -0,0 +1,155 @@
+/*
+Package semvertest provides helpers for tests of code that uses semver.
+
+The helpers take a testing.TB and report failures through it so tests read
+as a list of expectations:
+
+	c := semvertest.MustConstraint(t, "^1.2")
+	semvertest.AssertAdmits(t, c, "1.2.0", "1.9.3")
+	semvertest.AssertRejects(t, c, "2.0.0")
+	semvertest.AssertOrdering(t, "1.0.0-alpha", "1.0.0-beta", "1.0.0")
+
+Golden files record how constraints are normalized by Constraints.String so
+changes show up in review. Set the SEMVERTEST_UPDATE environment variable to
+write the golden files instead of comparing against them.
+
+FuzzParseVersion and FuzzParseConstraint check the parsing invariants of the
+semver package from a fuzz test, such as that the string form of a version
+parses to the same version, so downstream projects can fuzz the package with
+their own corpora.
+*/
+package semvertest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// UpdateEnv is the environment variable that, when set to a non-empty value,
+// makes AssertGolden and AssertNormalized write golden files.
+const UpdateEnv = "SEMVERTEST_UPDATE"
+
+// MustVersion parses a version with semver.NewVersion and stops the test if
+// it is invalid.
+func MustVersion(tb testing.TB, v string) *semver.Version {
+	tb.Helper()
+	sv, err := semver.NewVersion(v)
+	if err != nil {
+		tb.Fatalf("invalid version %q: %s", v, err)
+	}
+	return sv
+}
+
+// MustVersions parses versions with MustVersion.
+func MustVersions(tb testing.TB, vs ...string) semver.Collection {
+	tb.Helper()
+	c := make(semver.Collection, len(vs))
+	for i, v := range vs {
+		c[i] = MustVersion(tb, v)
+	}
+	return c
+}
+
+// MustConstraint parses constraints with semver.NewConstraint and stops the
+// test if they are invalid.
+func MustConstraint(tb testing.TB, c string) *semver.Constraints {
+	tb.Helper()
+	con, err := semver.NewConstraint(c)
+	if err != nil {
+		tb.Fatalf("invalid constraint %q: %s", c, err)
+	}
+	return con
+}
+
+// AssertAdmits reports an error for each version that does not satisfy the
+// constraints, as decided by Check, along with the reasons from Validate.
+func AssertAdmits(tb testing.TB, c *semver.Constraints, versions ...string) {
+	tb.Helper()
+	for _, v := range versions {
+		sv := MustVersion(tb, v)
+		if !c.Check(sv) {
+			_, errs := c.Validate(sv)
+			tb.Errorf("expected %q to admit %s: %s", c, v, joinErrors(errs))
+		}
+	}
+}
+
+// AssertRejects reports an error for each version that satisfies the
+// constraints.
+func AssertRejects(tb testing.TB, c *semver.Constraints, versions ...string) {
+	tb.Helper()
+	for _, v := range versions {
+		if c.Check(MustVersion(tb, v)) {
+			tb.Errorf("expected %q to reject %s", c, v)
+		}
+	}
+}
+
+// AssertOrdering reports an error when the versions are not in strictly
+// increasing order.
+func AssertOrdering(tb testing.TB, versions ...string) {
+	tb.Helper()
+	vs := MustVersions(tb, versions...)
+	for i := 1; i < len(vs); i++ {
+		if !vs[i-1].LessThan(vs[i]) {
+			tb.Errorf("expected %s to be less than %s", versions[i-1], versions[i])
+		}
+	}
+}
+
+// AssertGolden compares got to the contents of the golden file at path. When
+// the UpdateEnv environment variable is set the file is written instead.
+func AssertGolden(tb testing.TB, path string, got []byte) {
+	tb.Helper()
+	if os.Getenv(UpdateEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			tb.Fatalf("creating golden file directory: %s", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			tb.Fatalf("writing golden file: %s", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		tb.Fatalf("reading golden file (set %s=1 to create it): %s", UpdateEnv, err)
+	}
+	if !bytes.Equal(got, want) {
+		tb.Errorf("%s does not match, set %s=1 to update it\ngot:\n%s\nwant:\n%s", path, UpdateEnv, got, want)
+	}
+}
+
+// AssertNormalized parses each of the constraints and compares their
+// normalized forms, one "input => normalized" line each, to the golden file
+// at path. Constraints that do not parse are recorded with their error.
+func AssertNormalized(tb testing.TB, path string, constraints ...string) {
+	tb.Helper()
+	var b strings.Builder
+	for _, c := range constraints {
+		b.WriteString(c)
+		b.WriteString(" => ")
+		con, err := semver.NewConstraint(c)
+		if err != nil {
+			b.WriteString("error: ")
+			b.WriteString(err.Error())
+		} else {
+			b.WriteString(con.String())
+		}
+		b.WriteByte('\n')
+	}
+	AssertGolden(tb, path, []byte(b.String()))
+}
+
+func joinErrors(errs []error) string {
+	s := make([]string, len(errs))
+	for i, err := range errs {
+		s[i] = err.Error()
+	}
+	return strings.Join(s, "; ")
+}
@@ -0,0 +1,110 @@
+package semvertest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// recorder is a testing.TB that records failures instead of reporting them.
+type recorder struct {
+	testing.TB
+	errors []string
+	fatal  bool
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+	r.fatal = true
+}
+
+func TestAssertions(t *testing.T) {
+	c := MustConstraint(t, "^1.2")
+	ne := MustConstraint(t, "!=1.2.3")
+	if v := MustVersion(t, "1.2.3"); v.String() != "1.2.3" {
+		t.Errorf("Expected 1.2.3 but got %s", v)
+	}
+
+	tests := []struct {
+		name   string
+		assert func(tb *recorder)
+		errors int
+	}{
+		{"admits", func(tb *recorder) { AssertAdmits(tb, c, "1.2.0", "1.9.3") }, 0},
+		{"admits failing", func(tb *recorder) { AssertAdmits(tb, c, "1.2.0", "2.0.0", "1.1.0") }, 2},
+		{"rejects", func(tb *recorder) { AssertRejects(tb, c, "2.0.0", "1.0.0") }, 0},
+		{"rejects failing", func(tb *recorder) { AssertRejects(tb, c, "1.5.0") }, 1},
+		{"admits like check", func(tb *recorder) { AssertAdmits(tb, ne, "1.2.4-beta") }, 0},
+		{"rejects like check", func(tb *recorder) { AssertRejects(tb, ne, "1.2.4-beta") }, 1},
+		{"ordering", func(tb *recorder) { AssertOrdering(tb, "1.0.0-alpha", "1.0.0-beta", "1.0.0", "1.0.1") }, 0},
+		{"ordering failing", func(tb *recorder) { AssertOrdering(tb, "1.0.0", "1.0.0-beta", "1.0.0-alpha") }, 2},
+	}
+
+	for _, tc := range tests {
+		r := &recorder{TB: t}
+		tc.assert(r)
+		if len(r.errors) != tc.errors {
+			t.Errorf("%s: expected %d errors but got %v", tc.name, tc.errors, r.errors)
+		}
+	}
+
+	r := &recorder{TB: t}
+	MustConstraint(r, ">=1.2.x.y")
+	if !r.fatal {
+		t.Error("Expected an invalid constraint to stop the test")
+	}
+}
+
+func TestAssertNormalized(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "normalized.golden")
+	inputs := []string{"^1.2 || ~1.0", ">= 1.2, < 2", "1.2.x.y"}
+
+	t.Setenv(UpdateEnv, "1")
+	AssertNormalized(t, path, inputs...)
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	t.Logf("golden file:\n%s", b)
+
+	t.Setenv(UpdateEnv, "")
+	r := &recorder{TB: t}
+	AssertNormalized(r, path, inputs...)
+	if len(r.errors) != 0 {
+		t.Errorf("Expected a match but got %v", r.errors)
+	}
+
+	r = &recorder{TB: t}
+	AssertNormalized(r, path, inputs[:2]...)
+	if len(r.errors) != 1 {
+		t.Errorf("Expected a mismatch but got %v", r.errors)
+	}
+
+	r = &recorder{TB: t}
+	AssertGolden(r, filepath.Join(t.TempDir(), "missing.golden"), nil)
+	if !r.fatal {
+		t.Error("Expected a missing golden file to stop the test")
+	}
+}
+
+func FuzzVersion(f *testing.F) {
+	for _, s := range VersionSeeds {
+		f.Add([]byte(s))
+	}
+	f.Fuzz(FuzzParseVersion)
+}
+
+func FuzzConstraint(f *testing.F) {
+	for _, s := range ConstraintSeeds {
+		f.Add([]byte(s))
+	}
+	f.Fuzz(FuzzParseConstraint)
+}
@@ -0,0 +1,103 @@
+package semver
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// NextMajorRange returns the constraints moved to the next major release
+// line, keeping the operators. Lower bounds, exact versions, and ^ and ~
+// constraints move to the start of the next major version, so ^1.4 becomes
+// ^2.0. Upper bounds keep their place in the line, so >=1.2.3 <1.8.0 becomes
+// >=2.0.0 <2.8.0. Partial versions and wildcards keep their precision, so
+// <=1.4 becomes <=2.4 and 1.x becomes 2.x. Prereleases are dropped and * is
+// unchanged.
+//
+// != constraints exclude versions in the old release line and are dropped.
+// The prerelease policy and deny list are kept.
+//
+// This is useful for migration tools that generate the same policy one major
+// version later.
+func NextMajorRange(c *Constraints) (*Constraints, error) {
+	return shiftRange(c, func(v *Version, upper bool) (*Version, error) {
+		if v.major == math.MaxUint64 {
+			return nil, fmt.Errorf("can not move %s to the next major version", v)
+		}
+		if upper {
+			return New(v.major+1, v.minor, v.patch, "", ""), nil
+		}
+		return New(v.major+1, 0, 0, "", ""), nil
+	}, false)
+}
+
+// NextMinorRange returns the constraints moved to the next minor release
+// line in the same way as NextMajorRange, so ~1.4 becomes ~1.5 and
+// >=1.2.0 <1.4.5 becomes >=1.3.0 <1.5.5. Versions that only have a major
+// version, such as 1.x or the 2 in <2, cover every minor version and are
+// unchanged.
+func NextMinorRange(c *Constraints) (*Constraints, error) {
+	return shiftRange(c, func(v *Version, upper bool) (*Version, error) {
+		if v.minor == math.MaxUint64 {
+			return nil, fmt.Errorf("can not move %s to the next minor version", v)
+		}
+		if upper {
+			return New(v.major, v.minor+1, v.patch, "", ""), nil
+		}
+		return New(v.major, v.minor+1, 0, "", ""), nil
+	}, true)
+}
+
+// shiftRange moves the version of each constraint with next and parses the
+// result. When keepMajorOnly is true constraints that only have a major
+// version are unchanged.
+func shiftRange(c *Constraints, next func(v *Version, upper bool) (*Version, error), keepMajorOnly bool) (*Constraints, error) {
+	if c.IsEmpty() {
+		return c, nil
+	}
+
+	groups := make([][]string, len(c.constraints))
+	for k, o := range c.constraints {
+		var g []string
+		for _, cc := range o {
+			switch {
+			case cc.origfunc == "!=":
+				continue
+			case cc.dirty && !cc.minorDirty && !cc.patchDirty,
+				keepMajorOnly && cc.minorDirty:
+				g = append(g, cc.string())
+				continue
+			}
+
+			upper := cc.origfunc == "<" || cc.origfunc == "<=" || cc.origfunc == "=<"
+			v, err := next(cc.con, upper)
+			if err != nil {
+				return nil, err
+			}
+			g = append(g, shiftedConstraint(cc, v))
+		}
+		if len(g) == 0 {
+			g = []string{"*"}
+		}
+		groups[k] = g
+	}
+	return rebuildConstraints(c, groups)
+}
+
+// shiftedConstraint returns the constraint with the version replaced by v. A
+// partial or wildcard version keeps its precision, such as 2.4 for 1.4 and
+// 2.x for 1.x.
+func shiftedConstraint(c *constraint, v *Version) string {
+	x := ""
+	if strings.ContainsAny(c.orig, "xX*") {
+		x = ".x"
+	}
+	switch {
+	case c.minorDirty:
+		return c.origfunc + strconv.FormatUint(v.major, 10) + x
+	case c.patchDirty:
+		return c.origfunc + strconv.FormatUint(v.major, 10) + "." + strconv.FormatUint(v.minor, 10) + x
+	}
+	return c.origfunc + v.String()
+}
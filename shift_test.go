@@ -0,0 +1,106 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestNextMajorRange(t *testing.T) {
+	tests := []struct {
+		constraint string
+		expected   string
+	}{
+		{"^1.4", "^2.0"},
+		{"~1.4.2", "~2.0.0"},
+		{">=1.2 <2", ">=2.0 <3"},
+		{"1.x", "2.x"},
+		{"1.4.x", "2.0.x"},
+		{"^0.4.2", "^1.0.0"},
+		{"^1.2.0-beta.1", "^2.0.0"},
+		{"1.2 - 1.8", ">=2.0 <=2.8"},
+		{">=1.2 <1.8", ">=2.0 <2.8"},
+		{">=1.2.3 <1.8.0", ">=2.0.0 <2.8.0"},
+		{">=1.2 <=1.4", ">=2.0 <=2.4"},
+		{"<=1", "<=2"},
+		{"1.2", "2.0"},
+		{"1.2.3 - 1.4", ">=2.0.0 <=2.4"},
+		{"1.2.3 - 1.4.x", ">=2.0.0 <=2.4.x"},
+		{"^1.2 || ^2.3", "^2.0 || ^3.0"},
+		{"*", "*"},
+		{"!=1.4.5 >=1.4", ">=2.0"},
+		{"!=1.4.5", "*"},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Errorf("err: %s", err)
+			continue
+		}
+
+		n, err := NextMajorRange(c)
+		if err != nil {
+			t.Errorf("Unexpected error moving %q: %s", tc.constraint, err)
+			continue
+		}
+		if n.String() != tc.expected {
+			t.Errorf("Expected %q moved to the next major to be %q but got %q", tc.constraint, tc.expected, n)
+		}
+	}
+
+	c, _ := NewConstraint(">=1.2 <=1.4")
+	if n, err := NextMajorRange(c); err != nil || !n.Check(MustParse("2.4.5")) {
+		t.Errorf("Expected >=1.2 <=1.4 moved to the next major to allow 2.4.5 but got %v", n)
+	}
+
+	c, _ = NewConstraint("^18446744073709551615.0.0")
+	if _, err := NextMajorRange(c); err == nil {
+		t.Error("Expected an error moving past the largest major version")
+	}
+}
+
+func TestNextMinorRange(t *testing.T) {
+	tests := []struct {
+		constraint string
+		expected   string
+	}{
+		{"~1.4", "~1.5"},
+		{"~1.4.2", "~1.5.0"},
+		{"^0.4.2", "^0.5.0"},
+		{">=1.2 <2", ">=1.3 <2"},
+		{"<=1.4", "<=1.5"},
+		{"1.2", "1.3"},
+		{"1.2.3 - 1.4", ">=1.3.0 <=1.5"},
+		{">=1.2 <1.4.5", ">=1.3 <1.5.5"},
+		{"1.x", "1.x"},
+		{"1.4.x", "1.5.x"},
+		{"~1.4.0-rc.1 || 2.0.3", "~1.5.0 || 2.1.0"},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Errorf("err: %s", err)
+			continue
+		}
+
+		n, err := NextMinorRange(c)
+		if err != nil {
+			t.Errorf("Unexpected error moving %q: %s", tc.constraint, err)
+			continue
+		}
+		if n.String() != tc.expected {
+			t.Errorf("Expected %q moved to the next minor to be %q but got %q", tc.constraint, tc.expected, n)
+		}
+	}
+
+	c, _ := NewConstraint("~1.4")
+	c.Deny = []*Version{MustParse("1.5.1")}
+	c.PrereleasePolicy = PrereleaseSameTuple
+	n, err := NextMinorRange(c)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(n.Deny) != 1 || n.PrereleasePolicy != PrereleaseSameTuple {
+		t.Error("Expected the deny list and prerelease policy to be kept")
+	}
+}
@@ -0,0 +1,101 @@
+package semver
+
+// SortedCollection is a collection of versions kept in sorted order as
+// versions are inserted and removed. This is useful for long running
+// services, such as those watching a registry, that need the latest version
+// matching constraints as versions are published and retracted. Insert,
+// Remove, and Latest use binary search rather than sorting or scanning the
+// whole collection.
+//
+// The zero value is an empty collection ready to use. A SortedCollection is
+// not safe for concurrent use.
+type SortedCollection struct {
+	// Unique, when true, makes Insert skip versions equal to one already in
+	// the collection. Versions are compared with Equal so build metadata is
+	// ignored.
+	Unique bool
+
+	versions Collection
+}
+
+// Insert adds the version to the collection in sorted order and reports if
+// it was added. Versions equal to ones already in the collection are added
+// after them, unless Unique is set in which case they are not added. Nil
+// versions are not added.
+func (s *SortedCollection) Insert(v *Version) bool {
+	if v == nil {
+		return false
+	}
+
+	i := s.versions.Search(v)
+	if s.Unique && i < len(s.versions) && s.versions[i].Equal(v) {
+		return false
+	}
+	for i < len(s.versions) && s.versions[i].Equal(v) {
+		i++
+	}
+
+	s.versions = append(s.versions, nil)
+	copy(s.versions[i+1:], s.versions[i:])
+	s.versions[i] = v
+	return true
+}
+
+// Remove removes the versions equal to v from the collection and returns how
+// many were removed. Versions are compared with Equal so build metadata is
+// ignored.
+func (s *SortedCollection) Remove(v *Version) int {
+	if v == nil {
+		return 0
+	}
+
+	i := s.versions.Search(v)
+	j := i
+	for j < len(s.versions) && s.versions[j].Equal(v) {
+		j++
+	}
+	if j == i {
+		return 0
+	}
+
+	n := copy(s.versions[i:], s.versions[j:])
+	for k := i + n; k < len(s.versions); k++ {
+		s.versions[k] = nil
+	}
+	s.versions = s.versions[:i+n]
+	return j - i
+}
+
+// Len returns the number of versions in the collection.
+func (s *SortedCollection) Len() int {
+	return len(s.versions)
+}
+
+// Versions returns the versions in sorted order. The returned collection
+// shares memory with s and must not be modified. It is only valid until the
+// next call to Insert or Remove.
+func (s *SortedCollection) Versions() Collection {
+	return s.versions
+}
+
+// Latest returns the highest version in the collection that satisfies the
+// constraints, or nil when none does. Nil constraints are satisfied by any
+// version. Binary search skips the versions above the constraints, see
+// Collection.SearchConstraintBounds.
+func (s *SortedCollection) Latest(cs *Constraints) *Version {
+	if cs == nil {
+		if len(s.versions) == 0 {
+			return nil
+		}
+		return s.versions[len(s.versions)-1]
+	}
+
+	start, end := s.versions.SearchConstraintBounds(cs)
+	pre := cs.prereleaseGroups()
+	for i := end - 1; i >= start; i-- {
+		if cs.checkGroups(s.versions[i], pre) {
+			return s.versions[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package semver
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortedCollection(t *testing.T) {
+	var s SortedCollection
+	for _, v := range []string{"1.4.0", "1.2.0", "2.0.0-beta.1", "1.9.0", "1.2.0+b1", "0.9.0"} {
+		if !s.Insert(MustParse(v)) {
+			t.Errorf("Expected %s to be inserted", v)
+		}
+	}
+	if s.Insert(nil) {
+		t.Error("Expected nil not to be inserted")
+	}
+
+	var got []string
+	for _, v := range s.Versions() {
+		got = append(got, v.Original())
+	}
+	expected := []string{"0.9.0", "1.2.0", "1.2.0+b1", "1.4.0", "1.9.0", "2.0.0-beta.1"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %q but got %q", expected, got)
+	}
+
+	c, err := NewConstraint("^1.2 !=1.9.0")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if v := s.Latest(c); v == nil || v.String() != "1.4.0" {
+		t.Errorf("Expected the latest to be 1.4.0 but got %v", v)
+	}
+	if v := s.Latest(nil); v == nil || v.String() != "2.0.0-beta.1" {
+		t.Errorf("Expected the latest to be 2.0.0-beta.1 but got %v", v)
+	}
+
+	if n := s.Remove(MustParse("1.4.0")); n != 1 {
+		t.Errorf("Expected 1 version removed but got %d", n)
+	}
+	if n := s.Remove(MustParse("1.2.0")); n != 2 {
+		t.Errorf("Expected 2 versions removed but got %d", n)
+	}
+	if n := s.Remove(MustParse("3.0.0")); n != 0 {
+		t.Errorf("Expected no versions removed but got %d", n)
+	}
+	if v := s.Latest(c); v != nil {
+		t.Errorf("Expected no latest version but got %s", v)
+	}
+	if s.Len() != 3 {
+		t.Errorf("Expected 3 versions but got %d", s.Len())
+	}
+
+	u := SortedCollection{Unique: true}
+	u.Insert(MustParse("1.2.0"))
+	if u.Insert(MustParse("1.2.0+b1")) || u.Len() != 1 {
+		t.Errorf("Expected a unique collection to skip an equal version")
+	}
+	if v := (&SortedCollection{}).Latest(nil); v != nil {
+		t.Errorf("Expected no latest version in an empty collection but got %s", v)
+	}
+}
@@ -0,0 +1,12 @@
+package semver
+
+import "context"
+
+// VersionSource lists the versions available for a named package, such as a
+// module from a Go module proxy or a package from a registry. Implementations
+// are in the source package.
+type VersionSource interface {
+	// List returns the versions of the named package. The order of the
+	// versions is not specified.
+	List(ctx context.Context, name string) (Collection, error)
+}
@@ -0,0 +1,141 @@
+/*
+Package source provides implementations of semver.VersionSource that list
+versions from package registries.
+*/
+package source
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// ErrNotFound is returned when a registry does not have the named package.
+var ErrNotFound = errors.New("package not found")
+
+// DefaultGoProxyURL is the Go module proxy used when GoProxy.URL is empty.
+const DefaultGoProxyURL = "https://proxy.golang.org"
+
+// GoProxy lists the versions of Go modules using the module proxy protocol.
+// See https://go.dev/ref/mod#goproxy-protocol for details.
+type GoProxy struct {
+	// URL is the base URL of the proxy. DefaultGoProxyURL is used when it is
+	// empty.
+	URL string
+
+	// Client is used for requests to the proxy. http.DefaultClient is used
+	// when it is nil.
+	Client *http.Client
+}
+
+var _ semver.VersionSource = (*GoProxy)(nil)
+
+// List returns the tagged versions of the module with the given path. Lines
+// returned by the proxy that are not semantic versions are skipped. An error
+// wrapping ErrNotFound is returned when the proxy does not have the module.
+func (p *GoProxy) List(ctx context.Context, name string) (semver.Collection, error) {
+	path, err := escapeModulePath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	base := p.URL
+	if base == "" {
+		base = DefaultGoProxyURL
+	}
+	body, err := get(ctx, p.Client, strings.TrimRight(base, "/")+"/"+path+"/@v/list")
+	if err != nil {
+		return nil, fmt.Errorf("listing module %s: %w", name, err)
+	}
+	defer body.Close()
+
+	var vs semver.Collection
+	sc := bufio.NewScanner(body)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		v, err := semver.NewVersion(line)
+		if err != nil {
+			continue
+		}
+		vs = append(vs, v)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("listing module %s: %w", name, err)
+	}
+
+	return vs, nil
+}
+
+// escapeModulePath escapes a module path for use in a proxy URL. Upper case
+// letters are replaced by an exclamation mark followed by the lower case
+// letter so paths work on case insensitive file systems.
+func escapeModulePath(path string) (string, error) {
+	if path == "" {
+		return "", errors.New("empty module path")
+	}
+
+	var sb strings.Builder
+	for _, r := range path {
+		switch {
+		case r == '!' || r >= 0x80:
+			return "", fmt.Errorf("invalid module path %q", path)
+		case 'A' <= r && r <= 'Z':
+			sb.WriteByte('!')
+			sb.WriteRune(r + 'a' - 'A')
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String(), nil
+}
+
+// get requests the URL and returns the body of a successful response. The
+// caller closes the body.
+func get(ctx context.Context, client *http.Client, u string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	return do(client, req)
+}
+
+// do sends the request and returns the body of a successful response. The
+// caller closes the body.
+func do(client *http.Client, req *http.Request) (io.ReadCloser, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// checkResponse returns an error for a response that is not successful,
+// which is ErrNotFound when the package does not exist. The body of the
+// response is closed when there is an error.
+func checkResponse(resp *http.Response) error {
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		resp.Body.Close()
+		return ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		resp.Body.Close()
+		return fmt.Errorf("unexpected response %s from %s", resp.Status, resp.Request.URL)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestGoProxyList(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/github.com/!masterminds/semver/v3/@v/list":
+			_, _ = w.Write([]byte("v3.1.0\nv3.0.0\n\nnot-a-version\nv3.2.0-rc.1\n"))
+		case "/example.com/error/@v/list":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusGone)
+		}
+	}))
+	defer srv.Close()
+
+	p := &GoProxy{URL: srv.URL + "/", Client: srv.Client()}
+	vs, err := p.List(context.Background(), "github.com/Masterminds/semver/v3")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	a := make([]string, len(vs))
+	for i, v := range vs {
+		a[i] = v.Original()
+	}
+	if e := []string{"v3.1.0", "v3.0.0", "v3.2.0-rc.1"}; !reflect.DeepEqual(a, e) {
+		t.Errorf("Expected versions %v but got %v", e, a)
+	}
+
+	if _, err := p.List(context.Background(), "example.com/missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected %q but got %v", ErrNotFound, err)
+	}
+	if _, err := p.List(context.Background(), "example.com/error"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected an unexpected response error but got %v", err)
+	}
+	if _, err := p.List(context.Background(), "example.com/b!ng"); err == nil {
+		t.Error("Expected an error for an invalid module path")
+	}
+}
@@ -0,0 +1,114 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// DefaultNPMURL is the npm registry used when NPM.URL is empty.
+const DefaultNPMURL = "https://registry.npmjs.org"
+
+// NPM lists the versions of packages in an npm registry.
+type NPM struct {
+	// URL is the base URL of the registry. DefaultNPMURL is used when it is
+	// empty.
+	URL string
+
+	// Client is used for requests to the registry. http.DefaultClient is
+	// used when it is nil.
+	Client *http.Client
+}
+
+var _ semver.VersionSource = (*NPM)(nil)
+
+// npmPackage is the part of the abbreviated package metadata returned by
+// the registry that is used.
+type npmPackage struct {
+	DistTags map[string]string          `json:"dist-tags"`
+	Versions map[string]json.RawMessage `json:"versions"`
+}
+
+// List returns the published versions of the named package, which may be
+// scoped (e.g., @types/node). Versions the registry lists that are not
+// semantic versions are skipped. An error wrapping ErrNotFound is returned
+// when the registry does not have the package.
+func (n *NPM) List(ctx context.Context, name string) (semver.Collection, error) {
+	p, err := n.fetch(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	vs := make(semver.Collection, 0, len(p.Versions))
+	for s := range p.Versions {
+		v, err := semver.NewVersion(s)
+		if err != nil {
+			continue
+		}
+		vs = append(vs, v)
+	}
+
+	return vs, nil
+}
+
+// DistTags returns the versions the dist-tags of the named package point
+// to, keyed by tag (e.g., latest or next). Tags that do not point to a
+// semantic version are skipped.
+func (n *NPM) DistTags(ctx context.Context, name string) (map[string]*semver.Version, error) {
+	p, err := n.fetch(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string]*semver.Version, len(p.DistTags))
+	for t, s := range p.DistTags {
+		v, err := semver.NewVersion(s)
+		if err != nil {
+			continue
+		}
+		tags[t] = v
+	}
+
+	return tags, nil
+}
+
+// fetch requests the abbreviated metadata for a package.
+func (n *NPM) fetch(ctx context.Context, name string) (*npmPackage, error) {
+	if name == "" {
+		return nil, errors.New("empty package name")
+	}
+
+	base := n.URL
+	if base == "" {
+		base = DefaultNPMURL
+	}
+
+	// The slash in a scoped package name is escaped as the registry expects
+	// the name as a single path segment.
+	u := strings.TrimRight(base, "/") + "/" + url.PathEscape(name)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.npm.install-v1+json")
+
+	body, err := do(n.Client, req)
+	if err != nil {
+		return nil, fmt.Errorf("listing package %s: %w", name, err)
+	}
+	defer body.Close()
+
+	p := &npmPackage{}
+	if err := json.NewDecoder(body).Decode(p); err != nil {
+		return nil, fmt.Errorf("listing package %s: %w", name, err)
+	}
+
+	return p, nil
+}
@@ -0,0 +1,57 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestNPM(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "application/vnd.npm.install-v1+json" {
+			t.Errorf("Unexpected Accept header %q", r.Header.Get("Accept"))
+		}
+		switch r.URL.EscapedPath() {
+		case "/@types%2Fnode":
+			_, _ = w.Write([]byte(`{
+				"name": "@types/node",
+				"dist-tags": {"latest": "20.1.0", "next": "21.0.0-beta.1", "bad": "nope"},
+				"versions": {"20.0.0": {}, "20.1.0": {}, "21.0.0-beta.1": {}, "bogus": {}}
+			}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	n := &NPM{URL: srv.URL, Client: srv.Client()}
+	vs, err := n.List(context.Background(), "@types/node")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	sort.Sort(vs)
+
+	a := make([]string, len(vs))
+	for i, v := range vs {
+		a[i] = v.String()
+	}
+	if e := []string{"20.0.0", "20.1.0", "21.0.0-beta.1"}; !reflect.DeepEqual(a, e) {
+		t.Errorf("Expected versions %v but got %v", e, a)
+	}
+
+	tags, err := n.DistTags(context.Background(), "@types/node")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(tags) != 2 || tags["latest"].String() != "20.1.0" || tags["next"].String() != "21.0.0-beta.1" {
+		t.Errorf("Unexpected dist-tags %v", tags)
+	}
+
+	if _, err := n.List(context.Background(), "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected %q but got %v", ErrNotFound, err)
+	}
+}
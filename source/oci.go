@@ -0,0 +1,249 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// OCI lists the tags of images in an OCI or Docker registry using the
+// distribution v2 API. Tags that are not versions are skipped.
+//
+// Registries that require authentication are supported using either basic
+// authentication or bearer tokens from the token service the registry names
+// in its challenge. The username and password are used for both.
+type OCI struct {
+	// URL is the base URL of the registry, such as https://ghcr.io.
+	URL string
+
+	// Username and Password are the credentials for the registry. They are
+	// optional for registries that allow anonymous access.
+	Username string
+	Password string
+
+	// Strict specifies that tags need to be strict semantic versions, as
+	// parsed by semver.StrictNewVersion. Otherwise tags are coerced with
+	// semver.NewVersion so tags such as v1.2 are included.
+	Strict bool
+
+	// Client is used for requests to the registry. http.DefaultClient is
+	// used when it is nil.
+	Client *http.Client
+}
+
+var _ semver.VersionSource = (*OCI)(nil)
+
+// List returns the tags of the named repository, such as library/nginx,
+// that are versions. An error wrapping ErrNotFound is returned when the
+// registry does not have the repository.
+func (o *OCI) List(ctx context.Context, name string) (semver.Collection, error) {
+	if name == "" {
+		return nil, errors.New("empty repository name")
+	}
+	if o.URL == "" {
+		return nil, errors.New("no registry URL")
+	}
+
+	base, err := url.Parse(strings.TrimRight(o.URL, "/"))
+	if err != nil {
+		return nil, err
+	}
+	next, err := base.Parse(base.Path + "/v2/" + name + "/tags/list")
+	if err != nil {
+		return nil, err
+	}
+
+	var vs semver.Collection
+	auth := ""
+	seen := make(map[string]bool)
+	for next != nil {
+		seen[next.String()] = true
+		var tags struct {
+			Tags []string `json:"tags"`
+		}
+		resp, err := o.get(ctx, next.String(), &auth)
+		if err != nil {
+			return nil, fmt.Errorf("listing repository %s: %w", name, err)
+		}
+		err = json.NewDecoder(resp.Body).Decode(&tags)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("listing repository %s: %w", name, err)
+		}
+
+		for _, t := range tags.Tags {
+			if v, err := o.parse(t); err == nil {
+				vs = append(vs, v)
+			}
+		}
+
+		// Large repositories are listed in pages with a Link header
+		// pointing to the next one.
+		// The credentials are sent with each page so only pages on the
+		// registry are followed. A page that was already fetched ends the
+		// listing rather than looping.
+		next = nil
+		if l := nextLink(resp.Header.Get("Link")); l != "" {
+			u, err := base.Parse(l)
+			if err != nil {
+				return nil, fmt.Errorf("listing repository %s: %w", name, err)
+			}
+			if u.Scheme != base.Scheme || u.Host != base.Host {
+				return nil, fmt.Errorf("listing repository %s: next page %s is not on the registry", name, u.Redacted())
+			}
+			if !seen[u.String()] {
+				next = u
+			}
+		}
+	}
+
+	return vs, nil
+}
+
+// parse parses a tag as a version.
+func (o *OCI) parse(tag string) (*semver.Version, error) {
+	if o.Strict {
+		return semver.StrictNewVersion(tag)
+	}
+	return semver.NewVersion(tag)
+}
+
+// get requests the URL with the Authorization header in auth. When the
+// registry challenges the request, auth is set from the challenge and the
+// request is tried again.
+func (o *OCI) get(ctx context.Context, u string, auth *string) (*http.Response, error) {
+	client := o.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	for retried := false; ; retried = true {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/json")
+		if *auth != "" {
+			req.Header.Set("Authorization", *auth)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && !retried {
+			challenge := resp.Header.Get("WWW-Authenticate")
+			resp.Body.Close()
+			if *auth, err = o.authorize(ctx, client, challenge); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := checkResponse(resp); err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+}
+
+// authorize returns the Authorization header answering a challenge from the
+// registry.
+func (o *OCI) authorize(ctx context.Context, client *http.Client, challenge string) (string, error) {
+	scheme, params := parseChallenge(challenge)
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if o.Username == "" && o.Password == "" {
+			return "", errors.New("registry requires credentials")
+		}
+		req := &http.Request{Header: http.Header{}}
+		req.SetBasicAuth(o.Username, o.Password)
+		return req.Header.Get("Authorization"), nil
+	case "bearer":
+	default:
+		return "", fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("invalid authentication realm %q", params["realm"])
+	}
+	q := realm.Query()
+	for _, k := range []string{"service", "scope"} {
+		if params[k] != "" {
+			q.Set(k, params[k])
+		}
+	}
+	realm.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if o.Username != "" || o.Password != "" {
+		req.SetBasicAuth(o.Username, o.Password)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected response %s from token service %s", resp.Status, realm.Host)
+	}
+
+	var tok struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return "", err
+	}
+	if tok.Token == "" {
+		tok.Token = tok.AccessToken
+	}
+	if tok.Token == "" {
+		return "", errors.New("token service did not return a token")
+	}
+
+	return "Bearer " + tok.Token, nil
+}
+
+// parseChallenge splits a WWW-Authenticate header into its scheme and
+// parameters, such as Bearer realm="https://auth.example.com/token".
+func parseChallenge(h string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(h), " ")
+	params := make(map[string]string)
+	for rest != "" {
+		var k, v string
+		k, rest, _ = strings.Cut(strings.TrimLeft(rest, " ,"), "=")
+		if strings.HasPrefix(rest, `"`) {
+			v, rest, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			v, rest, _ = strings.Cut(rest, ",")
+		}
+		if k = strings.ToLower(strings.TrimSpace(k)); k != "" {
+			params[k] = v
+		}
+	}
+	return scheme, params
+}
+
+// nextLink returns the target of the rel="next" link in a Link header.
+func nextLink(h string) string {
+	for _, l := range strings.Split(h, ",") {
+		target, params, _ := strings.Cut(strings.TrimSpace(l), ";")
+		if !strings.Contains(strings.ReplaceAll(params, " ", ""), `rel="next"`) {
+			continue
+		}
+		return strings.Trim(strings.TrimSpace(target), "<>")
+	}
+	return ""
+}
@@ -0,0 +1,135 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestOCIList(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			if u, p, ok := r.BasicAuth(); !ok || u != "user" || p != "pass" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if r.URL.Query().Get("scope") != "repository:library/app:pull" || r.URL.Query().Get("service") != "registry.test" {
+				t.Errorf("Unexpected token request %s", r.URL)
+			}
+			_, _ = w.Write([]byte(`{"token": "abc"}`))
+		case "/v2/library/app/tags/list":
+			if r.Header.Get("Authorization") != "Bearer abc" {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="`+srv.URL+`/token",service="registry.test",scope="repository:library/app:pull"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if r.URL.Query().Get("last") == "" {
+				w.Header().Set("Link", `</v2/library/app/tags/list?n=3&last=latest>; rel="next"`)
+				_, _ = w.Write([]byte(`{"name": "library/app", "tags": ["1.2.3", "v1.3", "latest"]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"name": "library/app", "tags": ["2.0.0-rc.1", "sha-abc123"]}`))
+		case "/v2/basic/app/tags/list":
+			if u, p, ok := r.BasicAuth(); !ok || u != "user" || p != "pass" {
+				w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"name": "basic/app", "tags": ["1.0.0"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	o := &OCI{URL: srv.URL, Username: "user", Password: "pass", Client: srv.Client()}
+	vs, err := o.List(context.Background(), "library/app")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	a := make([]string, len(vs))
+	for i, v := range vs {
+		a[i] = v.Original()
+	}
+	if e := []string{"1.2.3", "v1.3", "2.0.0-rc.1"}; !reflect.DeepEqual(a, e) {
+		t.Errorf("Expected versions %v but got %v", e, a)
+	}
+
+	o.Strict = true
+	if vs, err = o.List(context.Background(), "library/app"); err != nil || len(vs) != 2 {
+		t.Errorf("Expected 2 strict versions but got %v with error %v", vs, err)
+	}
+
+	if vs, err = o.List(context.Background(), "basic/app"); err != nil || len(vs) != 1 {
+		t.Errorf("Expected 1 version with basic authentication but got %v with error %v", vs, err)
+	}
+
+	if _, err = o.List(context.Background(), "missing/app"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected %q but got %v", ErrNotFound, err)
+	}
+
+	o.Password = "wrong"
+	if _, err = o.List(context.Background(), "library/app"); err == nil {
+		t.Error("Expected an error with the wrong credentials")
+	}
+}
+
+func TestOCIListNextLinks(t *testing.T) {
+	var other []string
+	evil := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		other = append(other, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"tags": ["9.9.9"]}`))
+	}))
+	defer evil.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, _, ok := r.BasicAuth(); !ok {
+			w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/loop/app/tags/list":
+			w.Header().Set("Link", `</v2/loop/app/tags/list>; rel="next"`)
+			_, _ = w.Write([]byte(`{"tags": ["1.0.0"]}`))
+		case "/v2/leak/app/tags/list":
+			w.Header().Set("Link", `<`+evil.URL+`/v2/leak/app/tags/list?last=1.0.0>; rel="next"`)
+			_, _ = w.Write([]byte(`{"tags": ["1.0.0"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	o := &OCI{URL: srv.URL, Username: "user", Password: "pass", Client: srv.Client()}
+	if vs, err := o.List(context.Background(), "loop/app"); err != nil || len(vs) != 1 {
+		t.Errorf("Expected a next link to the same page to end the listing but got %v with error %v", vs, err)
+	}
+
+	if _, err := o.List(context.Background(), "leak/app"); err == nil {
+		t.Error("Expected an error for a next link to another host")
+	}
+	if len(other) != 0 {
+		t.Errorf("Expected no request to another host but got %d with %q", len(other), other)
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	s, p := parseChallenge(`Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:a/b:pull,push"`)
+	if s != "Bearer" {
+		t.Errorf("Expected scheme Bearer but got %q", s)
+	}
+	e := map[string]string{
+		"realm":   "https://auth.example.com/token",
+		"service": "registry.example.com",
+		"scope":   "repository:a/b:pull,push",
+	}
+	if !reflect.DeepEqual(p, e) {
+		t.Errorf("Expected parameters %v but got %v", e, p)
+	}
+}
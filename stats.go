@@ -0,0 +1,159 @@
+package semver
+
+import (
+	"sort"
+)
+
+// CollectionStats summarizes a set of versions. It is returned by Stats.
+type CollectionStats struct {
+	// Count is the number of versions.
+	Count int
+
+	// Min and Max are the lowest and highest versions.
+	Min, Max *Version
+
+	// NewestStable is the highest version that is not a prerelease.
+	NewestStable *Version
+
+	// Prereleases is the number of prereleases.
+	Prereleases int
+
+	// PrereleaseRatio is the fraction of the versions that are prereleases.
+	PrereleaseRatio float64
+
+	// Majors counts the versions in each major release line, ordered by
+	// major version.
+	Majors []MajorCount
+
+	// Minors counts the versions in each minor release line, ordered by
+	// major and then minor version.
+	Minors []MinorCount
+}
+
+// MajorCount is the number of versions with a major version.
+type MajorCount struct {
+	Major uint64
+	Count int
+}
+
+// MinorCount is the number of versions with a major and minor version.
+type MinorCount struct {
+	Major, Minor uint64
+	Count        int
+}
+
+// Stats returns statistics about the versions, such as the highest stable
+// version and the number of versions in each release line. Nil versions
+// are ignored. The stats of an empty collection have nil versions and no
+// counts.
+func Stats(c Collection) *CollectionStats {
+	s := &CollectionStats{}
+	majors := make(map[uint64]int)
+	type line struct{ major, minor uint64 }
+	minors := make(map[line]int)
+
+	for _, v := range c {
+		if v == nil {
+			continue
+		}
+
+		s.Count++
+		if s.Min == nil || v.LessThan(s.Min) {
+			s.Min = v
+		}
+		if s.Max == nil || v.GreaterThan(s.Max) {
+			s.Max = v
+		}
+		if v.pre != "" {
+			s.Prereleases++
+		} else if s.NewestStable == nil || v.GreaterThan(s.NewestStable) {
+			s.NewestStable = v
+		}
+		majors[v.major]++
+		minors[line{v.major, v.minor}]++
+	}
+
+	if s.Count > 0 {
+		s.PrereleaseRatio = float64(s.Prereleases) / float64(s.Count)
+	}
+
+	for m, n := range majors {
+		s.Majors = append(s.Majors, MajorCount{Major: m, Count: n})
+	}
+	sort.Slice(s.Majors, func(i, j int) bool {
+		return s.Majors[i].Major < s.Majors[j].Major
+	})
+
+	for l, n := range minors {
+		s.Minors = append(s.Minors, MinorCount{Major: l.major, Minor: l.minor, Count: n})
+	}
+	sort.Slice(s.Minors, func(i, j int) bool {
+		a, b := s.Minors[i], s.Minors[j]
+		if a.Major != b.Major {
+			return a.Major < b.Major
+		}
+		return a.Minor < b.Minor
+	})
+
+	return s
+}
+
+// VersionRank is the position of a version among a set of versions. It is
+// returned by Rank.
+type VersionRank struct {
+	// Below and Above are the number of versions lower and higher than the
+	// version. Above is how many versions the version is behind the latest,
+	// such as for a deployment that is 14 versions behind.
+	Below, Above int
+
+	// Total is the number of versions in the set.
+	Total int
+
+	// Percentile is the percentage of the versions that are not higher
+	// than the version, from 0 to 100. It is 100 for the latest version and
+	// 0 when the set is empty.
+	Percentile float64
+}
+
+// Rank returns the position of v among the versions in the collection.
+// Versions are compared with Compare, so build metadata is ignored, and
+// versions equal to one another are counted once. Prereleases are counted
+// like any other version, see Collection.FilterChannel to rank among the
+// releases. v does not need to be in the collection. Nil versions are
+// ignored and the collection does not need to be sorted.
+func Rank(vs Collection, v *Version) VersionRank {
+	type key struct {
+		major, minor, patch uint64
+		pre                 string
+	}
+	seen := make(map[key]bool, len(vs))
+
+	var r VersionRank
+	atOrBelow := 0
+	for _, o := range vs {
+		if o == nil {
+			continue
+		}
+		k := key{o.major, o.minor, o.patch, o.pre}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+
+		r.Total++
+		switch d := o.Compare(v); {
+		case d < 0:
+			r.Below++
+			atOrBelow++
+		case d > 0:
+			r.Above++
+		default:
+			atOrBelow++
+		}
+	}
+
+	if r.Total > 0 {
+		r.Percentile = 100 * float64(atOrBelow) / float64(r.Total)
+	}
+	return r
+}
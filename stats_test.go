@@ -0,0 +1,77 @@
+package semver
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	c := Collection{
+		MustParse("1.2.0"),
+		MustParse("2.0.0-beta.1"),
+		MustParse("1.0.0"),
+		nil,
+		MustParse("1.2.1"),
+		MustParse("2.0.0-rc.1"),
+		MustParse("0.9.0"),
+	}
+
+	s := Stats(c)
+	if s.Count != 6 {
+		t.Errorf("Expected a count of 6 but got %d", s.Count)
+	}
+	if s.Min.String() != "0.9.0" || s.Max.String() != "2.0.0-rc.1" {
+		t.Errorf("Expected min 0.9.0 and max 2.0.0-rc.1 but got %s and %s", s.Min, s.Max)
+	}
+	if s.NewestStable.String() != "1.2.1" {
+		t.Errorf("Expected newest stable 1.2.1 but got %s", s.NewestStable)
+	}
+	if s.Prereleases != 2 || s.PrereleaseRatio != 2.0/6 {
+		t.Errorf("Expected 2 prereleases with a ratio of 1/3 but got %d and %f", s.Prereleases, s.PrereleaseRatio)
+	}
+
+	majors := []MajorCount{{0, 1}, {1, 3}, {2, 2}}
+	if !reflect.DeepEqual(s.Majors, majors) {
+		t.Errorf("Expected majors %v but got %v", majors, s.Majors)
+	}
+	minors := []MinorCount{{0, 9, 1}, {1, 0, 1}, {1, 2, 2}, {2, 0, 2}}
+	if !reflect.DeepEqual(s.Minors, minors) {
+		t.Errorf("Expected minors %v but got %v", minors, s.Minors)
+	}
+
+	e := Stats(nil)
+	if e.Count != 0 || e.Min != nil || e.NewestStable != nil || e.PrereleaseRatio != 0 || e.Majors != nil {
+		t.Errorf("Expected empty stats but got %+v", e)
+	}
+}
+
+func TestRank(t *testing.T) {
+	vs := Collection{
+		MustParse("1.0.0"),
+		MustParse("1.1.0"),
+		nil,
+		MustParse("1.2.0-beta.1"),
+		MustParse("1.2.0"),
+		MustParse("1.2.0+b2"),
+		MustParse("2.0.0"),
+	}
+
+	tests := []struct {
+		v        string
+		expected VersionRank
+	}{
+		{"1.1.0", VersionRank{Below: 1, Above: 3, Total: 5, Percentile: 40}},
+		{"2.0.0", VersionRank{Below: 4, Above: 0, Total: 5, Percentile: 100}},
+		{"1.1.5", VersionRank{Below: 2, Above: 3, Total: 5, Percentile: 40}},
+		{"0.1.0", VersionRank{Below: 0, Above: 5, Total: 5, Percentile: 0}},
+	}
+	for _, tc := range tests {
+		if r := Rank(vs, MustParse(tc.v)); r != tc.expected {
+			t.Errorf("Expected the rank of %s to be %+v but got %+v", tc.v, tc.expected, r)
+		}
+	}
+
+	if r := Rank(nil, MustParse("1.0.0")); r != (VersionRank{}) {
+		t.Errorf("Expected an empty rank but got %+v", r)
+	}
+}
@@ -0,0 +1,128 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrNoVersions is returned by SuggestConstraint when there are no versions
+// to suggest a constraint for.
+var ErrNoVersions = errors.New("No versions to suggest a constraint for")
+
+// SuggestPolicy specifies the form of constraint proposed by
+// SuggestConstraint.
+type SuggestPolicy int
+
+const (
+	// SuggestCaret proposes a ^ constraint on the lowest version (e.g.,
+	// ^1.4.2). When the versions span more than one caret range a range up to
+	// the next breaking version after the highest version is used instead
+	// (e.g., >=1.4.2 <3.0.0).
+	SuggestCaret SuggestPolicy = iota
+
+	// SuggestTilde proposes a ~ constraint on the lowest version (e.g.,
+	// ~1.4.2). When the versions span more than one minor version a range up
+	// to the next minor version after the highest version is used instead
+	// (e.g., >=1.4.2 <1.7.0).
+	SuggestTilde
+
+	// SuggestRange proposes a range from the lowest to the highest version
+	// (e.g., >=1.4.2 <=1.6.3).
+	SuggestRange
+)
+
+// SuggestConstraint proposes the tightest constraint of the form given by the
+// policy that is satisfied by all of the versions. This is useful for tools
+// that generate constraints from the versions that are in use. Nil versions
+// are ignored and ErrNoVersions is returned when there are no versions.
+//
+// When any of the versions is a prerelease the bounds of the constraint
+// have prereleases so the prerelease versions satisfy it.
+func SuggestConstraint(versions Collection, policy SuggestPolicy) (*Constraints, error) {
+	var lo, hi *Version
+	pre := false
+	for _, v := range versions {
+		if v == nil {
+			continue
+		}
+		if lo == nil || v.LessThan(lo) {
+			lo = v
+		}
+		if hi == nil || v.GreaterThan(hi) {
+			hi = v
+		}
+		if v.pre != "" {
+			pre = true
+		}
+	}
+	if lo == nil {
+		return nil, ErrNoVersions
+	}
+
+	// The major, minor, and patch of the highest version decide if it is
+	// within the range of the lowest one. A prerelease of the next breaking
+	// version is not.
+	top := New(hi.major, hi.minor, hi.patch, "", "")
+	low := suggestBound(lo, pre)
+
+	var s string
+	switch policy {
+	case SuggestCaret:
+		if top.LessThan(caretUpper(lo)) {
+			s = "^" + low
+		} else {
+			s = ">=" + low + " <" + suggestBound(caretUpper(hi), pre)
+		}
+	case SuggestTilde:
+		// ~0.0.0 matches every version so it is never suggested.
+		zero := lo.major == 0 && lo.minor == 0 && lo.patch == 0
+		if !zero && top.LessThan(tildeUpper(lo)) {
+			s = "~" + low
+		} else {
+			s = ">=" + low + " <" + suggestBound(tildeUpper(hi), pre)
+		}
+	case SuggestRange:
+		if hi.pre != "" || !pre {
+			s = ">=" + low + " <=" + suggestBound(hi, pre)
+		} else {
+			s = ">=" + low + " <" + suggestBound(New(hi.major, hi.minor, hi.patch+1, "", ""), pre)
+		}
+	default:
+		return nil, fmt.Errorf("unknown suggest policy %d", policy)
+	}
+
+	return NewConstraint(s)
+}
+
+// suggestBound returns the version as it is written in a suggested
+// constraint. Metadata is dropped and, when the constraint needs to allow
+// prereleases, a version without a prerelease gets the lowest one.
+func suggestBound(v *Version, pre bool) string {
+	s := strconv.FormatUint(v.major, 10) + "." +
+		strconv.FormatUint(v.minor, 10) + "." +
+		strconv.FormatUint(v.patch, 10)
+	if v.pre != "" {
+		return s + "-" + v.pre
+	}
+	if pre {
+		return s + "-0"
+	}
+	return s
+}
+
+// caretUpper returns the lowest version that ^v does not allow.
+func caretUpper(v *Version) *Version {
+	switch {
+	case v.major > 0:
+		return New(v.major+1, 0, 0, "", "")
+	case v.minor > 0:
+		return New(0, v.minor+1, 0, "", "")
+	}
+	return New(0, 0, v.patch+1, "", "")
+}
+
+// tildeUpper returns the lowest version that ~v does not allow.
+func tildeUpper(v *Version) *Version {
+	return New(v.major, v.minor+1, 0, "", "")
+}
@@ -0,0 +1,56 @@
+package semver
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSuggestConstraint(t *testing.T) {
+	tests := []struct {
+		versions []string
+		policy   SuggestPolicy
+		expected string
+	}{
+		{[]string{"1.4.2", "1.6.3", "1.5.0"}, SuggestCaret, "^1.4.2"},
+		{[]string{"1.4.2", "2.1.0"}, SuggestCaret, ">=1.4.2 <3.0.0"},
+		{[]string{"0.2.3", "0.2.9"}, SuggestCaret, "^0.2.3"},
+		{[]string{"0.2.3", "0.3.0"}, SuggestCaret, ">=0.2.3 <0.4.0"},
+		{[]string{"0.0.3"}, SuggestCaret, "^0.0.3"},
+		{[]string{"1.4.2", "1.9.0-beta.1"}, SuggestCaret, "^1.4.2-0"},
+		{[]string{"1.4.2", "2.0.0-beta.1"}, SuggestCaret, ">=1.4.2-0 <3.0.0-0"},
+		{[]string{"1.4.2+build.1", "1.4.9"}, SuggestTilde, "~1.4.2"},
+		{[]string{"1.4.2", "1.6.3"}, SuggestTilde, ">=1.4.2 <1.7.0"},
+		{[]string{"0.0.0", "0.0.5"}, SuggestTilde, ">=0.0.0 <0.1.0"},
+		{[]string{"1.4.2", "1.6.3"}, SuggestRange, ">=1.4.2 <=1.6.3"},
+		{[]string{"1.4.2-rc.1", "1.6.3"}, SuggestRange, ">=1.4.2-rc.1 <1.6.4-0"},
+		{[]string{"1.4.2", "1.6.3-rc.1"}, SuggestRange, ">=1.4.2-0 <=1.6.3-rc.1"},
+	}
+
+	for _, tc := range tests {
+		vs := make(Collection, len(tc.versions))
+		for i, v := range tc.versions {
+			vs[i] = MustParse(v)
+		}
+
+		c, err := SuggestConstraint(vs, tc.policy)
+		if err != nil {
+			t.Errorf("Unexpected error suggesting a constraint for %v: %s", tc.versions, err)
+			continue
+		}
+		if c.String() != tc.expected {
+			t.Errorf("Expected constraint for %v to be %q but got %q", tc.versions, tc.expected, c.String())
+		}
+		for _, v := range vs {
+			if !c.Check(v) {
+				t.Errorf("Expected suggested constraint %q to be satisfied by %s", c, v)
+			}
+		}
+	}
+
+	if _, err := SuggestConstraint(Collection{nil}, SuggestCaret); !errors.Is(err, ErrNoVersions) {
+		t.Errorf("Expected %q without versions but got %v", ErrNoVersions, err)
+	}
+	if _, err := SuggestConstraint(Collection{MustParse("1.2.3")}, SuggestPolicy(42)); err == nil {
+		t.Error("Expected an error for an unknown policy")
+	}
+}
@@ -0,0 +1,79 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrPlaceholder is returned when a placeholder in a constraint template is
+// not defined, is not a version, or is not written correctly.
+var ErrPlaceholder = errors.New("Invalid constraint placeholder")
+
+// ExpandConstraint parses a constraint template with ${NAME} placeholders,
+// such as >=${MIN} <${NEXT_MAJOR}, replacing each placeholder with its value
+// from vars. See ExpandConstraintFunc.
+func ExpandConstraint(tmpl string, vars map[string]string) (*Constraints, error) {
+	return ExpandConstraintFunc(tmpl, func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	})
+}
+
+// ExpandConstraintFunc parses a constraint template with ${NAME}
+// placeholders, replacing each placeholder with the value returned by lookup.
+// This lets policy files be parameterized, such as with os.LookupEnv to read
+// the values from environment variables.
+//
+// Each value must be a single version as written in a constraint, such as
+// 1.2.3, 1.4, or 2.x. An operator, space, or || in a value is an error
+// rather than being allowed to change the meaning of the constraint. Errors
+// for placeholders wrap ErrPlaceholder and name the placeholder.
+func ExpandConstraintFunc(tmpl string, lookup func(name string) (string, bool)) (*Constraints, error) {
+	var sb strings.Builder
+	rest := tmpl
+	for {
+		i := strings.Index(rest, "${")
+		if i < 0 {
+			break
+		}
+		sb.WriteString(rest[:i])
+
+		end := strings.IndexByte(rest[i:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("%w: unterminated placeholder in %q", ErrPlaceholder, tmpl)
+		}
+		name := rest[i+2 : i+end]
+		if !validPlaceholderName(name) {
+			return nil, fmt.Errorf("%w: invalid placeholder name %q in %q", ErrPlaceholder, name, tmpl)
+		}
+
+		val, ok := lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is not defined", ErrPlaceholder, name)
+		}
+		if strings.Contains(val, "|") || !constraintVersionRegex.MatchString(val) {
+			return nil, fmt.Errorf("%w: %s is %q which is not a version", ErrPlaceholder, name, val)
+		}
+		sb.WriteString(val)
+		rest = rest[i+end+1:]
+	}
+	sb.WriteString(rest)
+
+	return NewConstraint(sb.String())
+}
+
+// validPlaceholderName reports if a placeholder name is made up of ASCII
+// letters, digits, and underscores and does not start with a digit.
+func validPlaceholderName(name string) bool {
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if !(c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,61 @@
+package semver
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExpandConstraint(t *testing.T) {
+	vars := map[string]string{
+		"MIN":        "1.4.2",
+		"NEXT_MAJOR": "2",
+		"LINE":       "3.1.x",
+		"PRE":        "2.0.0-beta.1",
+		"injection":  "1 || *",
+		"op":         ">=1.0.0",
+		"empty":      "",
+	}
+
+	tests := []struct {
+		tmpl     string
+		expected string
+		err      bool
+	}{
+		{">=${MIN} <${NEXT_MAJOR}", ">=1.4.2 <2", false},
+		{"^${MIN} || ${LINE}", "^1.4.2 || 3.1.x", false},
+		{">=${PRE}", ">=2.0.0-beta.1", false},
+		{"^1.2", "^1.2", false},
+		{">=${MISSING}", "", true},
+		{">=${injection}", "", true},
+		{"${op}", "", true},
+		{">=${empty}", "", true},
+		{">=${MIN", "", true},
+		{">=${}", "", true},
+		{">=${1MIN}", "", true},
+		{">=${MIN-X}", "", true},
+	}
+
+	for _, tc := range tests {
+		c, err := ExpandConstraint(tc.tmpl, vars)
+		if tc.err {
+			if !errors.Is(err, ErrPlaceholder) {
+				t.Errorf("Expected ErrPlaceholder for %q but got %v", tc.tmpl, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %s", tc.tmpl, err)
+			continue
+		}
+		if c.String() != tc.expected {
+			t.Errorf("Expected %q to expand to %q but got %q", tc.tmpl, tc.expected, c)
+		}
+	}
+
+	c, err := ExpandConstraintFunc(">=${A} <${B}", func(name string) (string, bool) {
+		return map[string]string{"A": "1", "B": "1.5"}[name], true
+	})
+	if err != nil || c.String() != ">=1 <1.5" {
+		t.Errorf("Expected >=1 <1.5 but got %v, %v", c, err)
+	}
+}
@@ -0,0 +1,116 @@
+package semver
+
+// Union returns constraints allowing the versions that either a or b allow.
+// See UnionAll.
+func Union(a, b *Constraints) *Constraints {
+	return UnionAll([]*Constraints{a, b})
+}
+
+// UnionAll returns constraints allowing the versions that any of the
+// constraints allow, such as the versions supported by at least one
+// consumer across many manifests. The groups of AND constraints are combined
+// with || and simplified. Groups that are the same are only kept once, and a
+// group is left out when another group allows every version it does, such
+// as ^1.4 with ^1, unless it allows prereleases the other might not.
+//
+// The result has the prerelease policy of the first constraints. A version
+// in the deny lists is only denied when none of the constraints allow it.
+// Nil constraints are skipped. When there are none the result is empty and
+// does not allow any version, see IsEmpty.
+func UnionAll(cs []*Constraints) *Constraints {
+	var first *Constraints
+	var groups [][]*constraint
+	seen := make(map[string]bool)
+	for _, c := range cs {
+		if c == nil {
+			continue
+		}
+		if first == nil {
+			first = c
+		}
+		for _, o := range c.constraints {
+			if k := groupKey(o); !seen[k] {
+				seen[k] = true
+				groups = append(groups, o)
+			}
+		}
+	}
+	if first == nil {
+		return newConstraints(nil)
+	}
+
+	sets := make([][]interval, len(groups))
+	for k, o := range groups {
+		iv, ex := groupInterval(o)
+		sets[k] = subtractIntervals([]interval{iv}, ex)
+	}
+
+	var or [][]*constraint
+	dropped := make([]bool, len(groups))
+	for k, o := range groups {
+		if !groupAcceptsPrerelease(o, first.PrereleasePolicy) {
+			for j := range groups {
+				if j == k || dropped[j] {
+					continue
+				}
+				if len(subtractIntervals(sets[k], sets[j])) != 0 {
+					continue
+				}
+				// Of two groups allowing the same versions the first is kept.
+				if j > k && len(subtractIntervals(sets[j], sets[k])) == 0 &&
+					!groupAcceptsPrerelease(groups[j], first.PrereleasePolicy) {
+					continue
+				}
+				dropped[k] = true
+				break
+			}
+		}
+		if !dropped[k] {
+			or = append(or, o)
+		}
+	}
+
+	res := newConstraints(or)
+	res.PrereleasePolicy = first.PrereleasePolicy
+	for _, c := range cs {
+		if c == nil {
+			continue
+		}
+		for _, d := range c.Deny {
+			if !unionAllows(cs, d) && res.Check(d) {
+				res.Deny = append(res.Deny, d)
+			}
+		}
+	}
+	return res
+}
+
+// groupAcceptsPrerelease reports if a group may allow prerelease versions
+// with the prerelease policy.
+func groupAcceptsPrerelease(o []*constraint, policy PrereleasePolicy) bool {
+	if policy == PrereleaseSameTuple {
+		for _, c := range o {
+			if c.con.pre != "" {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, c := range o {
+		if c.rejectsPrerelease() {
+			return false
+		}
+	}
+	return true
+}
+
+// unionAllows reports if any of the constraints allow the version.
+func unionAllows(cs []*Constraints, v *Version) bool {
+	for _, c := range cs {
+		if c != nil && c.Check(v) {
+			return true
+		}
+	}
+	return false
+}
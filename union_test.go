@@ -0,0 +1,56 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestUnionAll(t *testing.T) {
+	tests := []struct {
+		constraints []string
+		expected    string
+	}{
+		{[]string{"^1.2", "^2"}, "^1.2 || ^2"},
+		{[]string{"^1.4", "^1", "~1.6.2"}, "^1"},
+		{[]string{"^1 || ^2", "^2 || ^3", "^1"}, "^1 || ^2 || ^3"},
+		{[]string{">=1.2.0 <2.0.0", "^1.2"}, ">=1.2.0 <2.0.0"},
+		{[]string{"^1", ">=1.2.0-beta.1 <1.3.0-0"}, "^1 || >=1.2.0-beta.1 <1.3.0-0"},
+		{[]string{"^1 !=1.4.0", "~1.4"}, "^1 !=1.4.0 || ~1.4"},
+		{[]string{"~1.4", "^1 !=1.4.0"}, "^1 !=1.4.0 || ~1.4"},
+		{[]string{"<2.0.0 >=3.0.0", "^3"}, "^3"},
+		{nil, ""},
+	}
+
+	for _, tc := range tests {
+		var cs []*Constraints
+		for _, s := range tc.constraints {
+			c, err := NewConstraint(s)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			cs = append(cs, c, nil)
+		}
+
+		u := UnionAll(cs)
+		if u.String() != tc.expected {
+			t.Errorf("Expected %q to combine as %q but got %q", tc.constraints, tc.expected, u)
+		}
+
+		for _, vr := range []string{"0.9.0", "1.2.0-beta.2", "1.2.5", "1.4.0", "1.4.5", "1.6.3", "2.0.0", "2.5.0", "3.1.0", "4.0.0"} {
+			v := MustParse(vr)
+			if e := unionAllows(cs, v); u.Check(v) != e {
+				t.Errorf("Expected union of %q with %s to be %t", tc.constraints, vr, e)
+			}
+		}
+	}
+
+	a, _ := NewConstraint("^1")
+	a.Deny = []*Version{MustParse("1.2.0"), MustParse("1.5.0")}
+	b, _ := NewConstraint("~1.5")
+	u := Union(a, b)
+	if len(u.Deny) != 1 || !u.Deny[0].Equal(MustParse("1.2.0")) {
+		t.Errorf("Expected only 1.2.0 to be denied but got %v", u.Deny)
+	}
+	if !u.Check(MustParse("1.5.0")) || u.Check(MustParse("1.2.0")) {
+		t.Error("Expected 1.5.0 to be allowed and 1.2.0 to be denied")
+	}
+}
@@ -0,0 +1,164 @@
+package semver
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// NewConstraintFromVers parses a version range in the Package URL vers
+// format, such as vers:npm/>=1.2.0|<2.0.0|!=1.4.1, returning the versioning
+// scheme along with the constraints. The versions must be semantic versions.
+// Each pair of lower and upper bound becomes a group, = versions become
+// groups of their own, and != versions are excluded from every group.
+//
+// The prerelease rules of this package apply to the constraints, see
+// NewConstraintFromOSV.
+func NewConstraintFromVers(s string) (string, *Constraints, error) {
+	s = strings.Join(strings.Fields(s), "")
+	rest, ok := strings.CutPrefix(s, "vers:")
+	if !ok {
+		return "", nil, fmt.Errorf("%w: %q does not start with vers:", ErrInvalidRange, s)
+	}
+	scheme, rest, ok := strings.Cut(rest, "/")
+	if !ok || scheme == "" || rest == "" {
+		return "", nil, fmt.Errorf("%w: %q must have a scheme and constraints", ErrInvalidRange, s)
+	}
+	if rest == "*" {
+		c, err := NewConstraint("*")
+		return scheme, c, err
+	}
+
+	type comparison struct {
+		op string
+		v  *Version
+	}
+	var cmps []comparison
+	for _, p := range strings.Split(rest, "|") {
+		op := "="
+		for _, o := range []string{">=", "<=", "!=", "<", ">", "="} {
+			if strings.HasPrefix(p, o) {
+				op = o
+				p = p[len(o):]
+				break
+			}
+		}
+		ver, err := url.PathUnescape(p)
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: %q: %w", ErrInvalidRange, p, err)
+		}
+		v, err := NewVersion(ver)
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: %q: %w", ErrInvalidRange, ver, err)
+		}
+		cmps = append(cmps, comparison{op, v})
+	}
+	sort.SliceStable(cmps, func(i, j int) bool {
+		return cmps[i].v.LessThan(cmps[j].v)
+	})
+
+	var groups [][]string
+	var ne []string
+	var lower string
+	ranged := false
+	for _, c := range cmps {
+		ver := c.v.String()
+		switch c.op {
+		case "=":
+			groups = append(groups, []string{"=" + ver})
+		case "!=":
+			ne = append(ne, "!="+ver)
+		case ">", ">=":
+			if lower != "" {
+				return "", nil, fmt.Errorf("%w: %s%s follows another lower bound", ErrInvalidRange, c.op, ver)
+			}
+			lower = c.op + ver
+			ranged = true
+		default:
+			switch {
+			case lower != "":
+				groups = append(groups, []string{lower, c.op + ver})
+				lower = ""
+			case ranged:
+				return "", nil, fmt.Errorf("%w: %s%s follows another upper bound", ErrInvalidRange, c.op, ver)
+			default:
+				groups = append(groups, []string{c.op + ver})
+			}
+			ranged = true
+		}
+	}
+	if lower != "" {
+		groups = append(groups, []string{lower})
+	}
+	if len(groups) == 0 {
+		groups = append(groups, []string{"*"})
+	}
+
+	ors := make([]string, len(groups))
+	for k, g := range groups {
+		ors[k] = strings.Join(append(g, ne...), " ")
+	}
+	c, err := NewConstraint(strings.Join(ors, " || "))
+	if err != nil {
+		return "", nil, err
+	}
+	return scheme, c, nil
+}
+
+// Vers returns the constraints as a Package URL vers range for the
+// versioning scheme, such as vers:npm/>=1.2.0|<2.0.0. Overlapping groups are
+// joined and the deny list becomes != versions. An error wrapping
+// ErrUnsupportedRange is returned when the constraints can not be expressed
+// as a vers range, such as when they do not allow any version.
+//
+// Which prereleases are allowed is not captured, see NewConstraintFromOSV.
+func (cs Constraints) Vers(scheme string) (string, error) {
+	ivs, ne, err := cs.intervals()
+	if err != nil {
+		return "", err
+	}
+	if len(ivs) == 0 {
+		return "", fmt.Errorf("%w: %s does not allow any version", ErrUnsupportedRange, cs)
+	}
+
+	type comparison struct {
+		op string
+		v  *Version
+	}
+	var cmps []comparison
+	for _, iv := range ivs {
+		switch {
+		case iv.point():
+			cmps = append(cmps, comparison{"=", iv.lo})
+			continue
+		case iv.lo == nil:
+		case iv.loInc:
+			cmps = append(cmps, comparison{">=", iv.lo})
+		default:
+			cmps = append(cmps, comparison{">", iv.lo})
+		}
+		switch {
+		case iv.hi == nil:
+		case iv.hiInc:
+			cmps = append(cmps, comparison{"<=", iv.hi})
+		default:
+			cmps = append(cmps, comparison{"<", iv.hi})
+		}
+	}
+	for _, v := range ne {
+		cmps = append(cmps, comparison{"!=", v})
+	}
+	sort.SliceStable(cmps, func(i, j int) bool {
+		return cmps[i].v.LessThan(cmps[j].v)
+	})
+
+	if len(cmps) == 0 {
+		return "vers:" + scheme + "/*", nil
+	}
+	parts := make([]string, len(cmps))
+	for i, c := range cmps {
+		parts[i] = c.op + url.PathEscape(c.v.String())
+	}
+	return "vers:" + scheme + "/" + strings.Join(parts, "|"), nil
+}
@@ -0,0 +1,111 @@
+package semver
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewConstraintFromVers(t *testing.T) {
+	tests := []struct {
+		vers     string
+		scheme   string
+		expected string
+	}{
+		{"vers:npm/>=1.2.0|<2.0.0", "npm", ">=1.2.0 <2.0.0"},
+		{"vers:npm/<2.0.0|>=1.2.0", "npm", ">=1.2.0 <2.0.0"},
+		{"vers:semver/<1.0.0|>=1.2.0|<1.5.0|>2.0.0", "semver", "<1.0.0 || >=1.2.0 <1.5.0 || >2.0.0"},
+		{"vers:npm/1.2.3|2.0.0", "npm", "=1.2.3 || =2.0.0"},
+		{"vers:npm/>=1.2.0|!=1.4.1|<2.0.0", "npm", ">=1.2.0 <2.0.0 !=1.4.1"},
+		{"vers:npm/!=1.4.1", "npm", "* !=1.4.1"},
+		{"vers:npm/*", "npm", "*"},
+		{" vers:npm/ >= 1.2.0 | < 2.0.0 ", "npm", ">=1.2.0 <2.0.0"},
+		{"vers:npm/>=1.0.0-beta%2B1", "npm", ">=1.0.0-beta+1"},
+	}
+
+	for _, tc := range tests {
+		scheme, c, err := NewConstraintFromVers(tc.vers)
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %s", tc.vers, err)
+			continue
+		}
+		if scheme != tc.scheme {
+			t.Errorf("Expected scheme %q for %q but got %q", tc.scheme, tc.vers, scheme)
+		}
+		if c.String() != tc.expected {
+			t.Errorf("Expected %q to be %q but got %q", tc.vers, tc.expected, c)
+		}
+	}
+
+	invalid := []string{
+		"npm/>=1.2.0",
+		"vers:>=1.2.0",
+		"vers:npm/",
+		"vers:npm/>=1.0.0|>=1.2.0",
+		"vers:npm/<1.0.0|<1.2.0",
+		"vers:npm/>=one",
+	}
+	for _, s := range invalid {
+		if _, _, err := NewConstraintFromVers(s); !errors.Is(err, ErrInvalidRange) {
+			t.Errorf("Expected ErrInvalidRange for %q but got %v", s, err)
+		}
+	}
+}
+
+func TestConstraintsVers(t *testing.T) {
+	tests := []struct {
+		constraint string
+		expected   string
+	}{
+		{"^1.2", "vers:npm/>=1.2.0|<2.0.0"},
+		{"~1.2.3 || 2.x", "vers:npm/>=1.2.3|<1.3.0|>=2.0.0|<3.0.0"},
+		{"^1.2 !=1.4.1", "vers:npm/>=1.2.0|!=1.4.1|<2.0.0"},
+		{"^1.2 !=1.2.0", "vers:npm/>1.2.0|<2.0.0"},
+		{"^1.2 !=3.0.0", "vers:npm/>=1.2.0|<2.0.0"},
+		{"1.2.3 || 1.4.0", "vers:npm/=1.2.3|=1.4.0"},
+		{"*", "vers:npm/*"},
+		{"* !=1.2.3", "vers:npm/!=1.2.3"},
+		{"<1.0.0 || >2.0.0", "vers:npm/<1.0.0|>2.0.0"},
+		{"^1.2 || ^1.5", "vers:npm/>=1.2.0|<2.0.0"},
+		{">=1.0.0-beta+1", "vers:npm/>=1.0.0-beta+1"},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		s, err := c.Vers("npm")
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %s", tc.constraint, err)
+			continue
+		}
+		if s != tc.expected {
+			t.Errorf("Expected %q to be %q but got %q", tc.constraint, tc.expected, s)
+		}
+
+		_, back, err := NewConstraintFromVers(s)
+		if err != nil {
+			t.Errorf("Unexpected error parsing %q: %s", s, err)
+			continue
+		}
+		for _, v := range []string{"0.9.0", "1.2.0", "1.2.3", "1.3.0", "1.4.0", "1.4.1", "2.0.0", "2.0.1", "3.0.0"} {
+			if c.Check(MustParse(v)) != back.Check(MustParse(v)) {
+				t.Errorf("Expected %q and %q to agree on %s", tc.constraint, back, v)
+			}
+		}
+	}
+
+	c, _ := NewConstraint("^1.2")
+	c.Deny = []*Version{MustParse("1.4.0"), MustParse("3.0.0")}
+	if s, err := c.Vers("npm"); err != nil || s != "vers:npm/>=1.2.0|!=1.4.0|<2.0.0" {
+		t.Errorf("Expected the deny list as != versions but got %q, %v", s, err)
+	}
+
+	for _, s := range []string{"^1.2 !=1.4.0 || ~1.4", "!=1.x", "1.2.3 !=1.2.3"} {
+		c, _ := NewConstraint(s)
+		if _, err := c.Vers("npm"); !errors.Is(err, ErrUnsupportedRange) {
+			t.Errorf("Expected ErrUnsupportedRange for %q but got %v", s, err)
+		}
+	}
+}
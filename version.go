@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // The compiled version of the regex created at init() is cached here so it
@@ -36,8 +37,27 @@ var (
 
 	// ErrInvalidPrerelease is returned when the pre-release is an invalid format
 	ErrInvalidPrerelease = errors.New("Invalid Prerelease string")
+
+	// ErrTooManySegments is returned when a version has more than the major,
+	// minor, and patch segments (e.g., 1.2.3.4).
+	ErrTooManySegments = errors.New("Version has too many segments")
+
+	// ErrNonASCII is returned when a version or constraint contains a
+	// character outside of ASCII. This includes characters that look like
+	// ASCII, such as full-width digits or Cyrillic letters.
+	ErrNonASCII = errors.New("Non-ASCII character")
 )
 
+// DetailedNewVersionErrors specifies if NewVersion should return errors
+// describing why a version could not be parsed. When true the error returned
+// for an invalid version wraps ErrInvalidSemVer along with one of
+// ErrEmptyString, ErrInvalidCharacters, ErrSegmentStartsZero,
+// ErrTooManySegments, ErrNonASCII, ErrInvalidPrerelease, or
+// ErrInvalidMetadata, which can be tested for with errors.Is. When false
+// ErrInvalidSemVer is returned on its own, which is faster for callers that
+// only need to know parsing failed.
+var DetailedNewVersionErrors = true
+
 // semVerRegex is the regular expression used to parse a semantic version.
 // This is not the official regex from the semver spec. It has been modified to allow for loose handling
 // where versions like 2.1 are detected.
@@ -45,7 +65,9 @@ const semVerRegex string = `v?(0|[1-9]\d*)(?:\.(0|[1-9]\d*))?(?:\.(0|[1-9]\d*))?
 	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
 	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?`
 
-// Version represents a single semantic version.
+// Version represents a single semantic version. It is not changed after it
+// is created, other than by the decoding methods, so it is safe for
+// concurrent use.
 type Version struct {
 	major, minor, patch uint64
 	pre                 string
@@ -57,6 +79,26 @@ func init() {
 	versionRegex = regexp.MustCompile("^" + semVerRegex + "$")
 }
 
+// segmentNames are the names of the major, minor, and patch segments used in
+// error messages.
+var segmentNames = [...]string{"major", "minor", "patch"}
+
+// Segment is one of the major, minor, and patch parts of a version.
+type Segment uint8
+
+const (
+	SegmentMajor Segment = iota
+	SegmentMinor
+	SegmentPatch
+)
+
+func (s Segment) String() string {
+	if int(s) < len(segmentNames) {
+		return segmentNames[s]
+	}
+	return "unknown"
+}
+
 const (
 	num     string = "0123456789"
 	allowed string = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ-" + num
@@ -68,6 +110,15 @@ const (
 // If you want to coerce a version such as 1 or 1.2 and parse it as the 1.x
 // releases of semver did, use the NewVersion() function.
 func StrictNewVersion(v string) (*Version, error) {
+	sv, err := strictNewVersion(v)
+	if m := loadMetrics(); m != nil {
+		m.VersionParsed(err)
+	}
+	return sv, err
+}
+
+// strictNewVersion does the work of StrictNewVersion.
+func strictNewVersion(v string) (*Version, error) {
 	// Parsing here does not use RegEx in order to increase performance and reduce
 	// allocations.
 
@@ -75,6 +126,14 @@ func StrictNewVersion(v string) (*Version, error) {
 		return nil, ErrEmptyString
 	}
 
+	if err := checkLimit("MaxVersionLength", MaxVersionLength, len(v)); err != nil {
+		return nil, err
+	}
+
+	if err := checkASCII(v); err != nil {
+		return nil, err
+	}
+
 	// Split the parts into [0]major, [1]minor, and [2]patch,prerelease,build
 	parts := strings.SplitN(v, ".", 3)
 	if len(parts) != 3 {
@@ -101,19 +160,30 @@ func StrictNewVersion(v string) (*Version, error) {
 		sv.pre = extra[1]
 		parts[2] = extra[0]
 		if err := validatePrerelease(sv.pre); err != nil {
+			if err == ErrSegmentStartsZero {
+				for _, p := range strings.Split(sv.pre, ".") {
+					if len(p) > 1 && p[0] == '0' && containsOnly(p, num) {
+						return nil, fmt.Errorf("%w: prerelease identifier %q", err, p)
+					}
+				}
+			}
 			return nil, err
 		}
 	}
 
 	// Validate the number segments are valid. This includes only having positive
 	// numbers and no leading 0's.
-	for _, p := range parts {
+	for i, p := range parts {
+		if i == 2 && strings.Contains(p, ".") {
+			return nil, ErrTooManySegments
+		}
+
 		if !containsOnly(p, num) {
 			return nil, ErrInvalidCharacters
 		}
 
 		if len(p) > 1 && p[0] == '0' {
-			return nil, ErrSegmentStartsZero
+			return nil, fmt.Errorf("%w: %s version %q", ErrSegmentStartsZero, segmentNames[i], p)
 		}
 	}
 
@@ -142,12 +212,30 @@ func StrictNewVersion(v string) (*Version, error) {
 // attempts to convert it to SemVer. If you want  to validate it was a strict
 // semantic version at parse time see StrictNewVersion().
 func NewVersion(v string) (*Version, error) {
+	sv := &Version{}
+	err := parseVersion(v, sv)
+	if m := loadMetrics(); m != nil {
+		m.VersionParsed(err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sv, nil
+}
+
+// parseVersion does the work of NewVersion, storing the result in sv. The
+// prerelease and metadata share memory with v rather than being copied.
+func parseVersion(v string, sv *Version) error {
+	if err := checkLimit("MaxVersionLength", MaxVersionLength, len(v)); err != nil {
+		return err
+	}
+
 	m := versionRegex.FindStringSubmatch(v)
 	if m == nil {
-		return nil, ErrInvalidSemVer
+		return invalidVersionError(v)
 	}
 
-	sv := &Version{
+	*sv = Version{
 		metadata: m[5],
 		pre:      m[4],
 		original: v,
@@ -156,13 +244,13 @@ func NewVersion(v string) (*Version, error) {
 	var err error
 	sv.major, err = strconv.ParseUint(m[1], 10, 64)
 	if err != nil {
-		return nil, fmt.Errorf("Error parsing version segment: %s", err)
+		return fmt.Errorf("Error parsing version segment: %s", err)
 	}
 
 	if m[2] != "" {
 		sv.minor, err = strconv.ParseUint(m[2], 10, 64)
 		if err != nil {
-			return nil, fmt.Errorf("Error parsing version segment: %s", err)
+			return fmt.Errorf("Error parsing version segment: %s", err)
 		}
 	} else {
 		sv.minor = 0
@@ -171,7 +259,7 @@ func NewVersion(v string) (*Version, error) {
 	if m[3] != "" {
 		sv.patch, err = strconv.ParseUint(m[3], 10, 64)
 		if err != nil {
-			return nil, fmt.Errorf("Error parsing version segment: %s", err)
+			return fmt.Errorf("Error parsing version segment: %s", err)
 		}
 	} else {
 		sv.patch = 0
@@ -182,17 +270,80 @@ func NewVersion(v string) (*Version, error) {
 
 	if sv.pre != "" {
 		if err = validatePrerelease(sv.pre); err != nil {
-			return nil, err
+			return err
 		}
 	}
 
 	if sv.metadata != "" {
 		if err = validateMetadata(sv.metadata); err != nil {
-			return nil, err
+			return err
 		}
 	}
 
-	return sv, nil
+	return nil
+}
+
+// invalidVersionError returns the error for a version NewVersion was unable
+// to parse.
+func invalidVersionError(v string) error {
+	if !DetailedNewVersionErrors {
+		return ErrInvalidSemVer
+	}
+
+	if err := diagnoseVersion(v); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSemVer, err)
+	}
+	return ErrInvalidSemVer
+}
+
+// diagnoseVersion finds the reason the version could not be parsed by
+// NewVersion. It is only used once parsing has failed so it does not need to
+// be fast.
+func diagnoseVersion(v string) error {
+	if v == "" {
+		return ErrEmptyString
+	}
+
+	if err := checkASCII(v); err != nil {
+		return err
+	}
+
+	core := strings.TrimPrefix(v, "v")
+	var pre, meta string
+	hasPre, hasMeta := false, false
+	if i := strings.IndexByte(core, '+'); i >= 0 {
+		core, meta, hasMeta = core[:i], core[i+1:], true
+	}
+	if i := strings.IndexByte(core, '-'); i >= 0 {
+		core, pre, hasPre = core[:i], core[i+1:], true
+	}
+
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 {
+		return ErrTooManySegments
+	}
+	for _, p := range parts {
+		if p == "" || !containsOnly(p, num) {
+			return ErrInvalidCharacters
+		}
+		if len(p) > 1 && p[0] == '0' {
+			return ErrSegmentStartsZero
+		}
+	}
+
+	if hasPre {
+		if err := validatePrerelease(pre); err != nil {
+			return err
+		}
+	}
+
+	if hasMeta {
+		if err := validateMetadata(meta); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // New creates a new instance of Version with each of the parts passed in as
@@ -270,6 +421,47 @@ func (v Version) Metadata() string {
 	return v.metadata
 }
 
+// Channel returns the release channel of a prerelease, which is the first
+// prerelease identifier when it is not numeric. For example, the channel of
+// 1.2.3-beta.4 is beta. An empty string is returned for releases and for
+// prereleases that start with a number, such as 1.2.3-4.
+func (v Version) Channel() string {
+	if v.pre == "" {
+		return ""
+	}
+	id, _, _ := strings.Cut(v.pre, ".")
+	if containsOnly(id, num) {
+		return ""
+	}
+	return id
+}
+
+// MetadataIdentifiers returns the dot separated identifiers of the metadata,
+// such as build, 42, and linux for 1.2.3+build.42.linux. Nil is returned
+// when the version has no metadata.
+func (v Version) MetadataIdentifiers() []string {
+	if v.metadata == "" {
+		return nil
+	}
+	return strings.Split(v.metadata, ".")
+}
+
+// MetadataValue looks up a key in the metadata, where the identifier after
+// the key is its value. Many CI systems record structured data this way,
+// such as sha.4f2a9c1 in 1.2.3+build.42.sha.4f2a9c1 where the value of sha
+// is 4f2a9c1. The first matching key is used and false is returned when the
+// key is not found or is the last identifier. See BuildInfo for the keys
+// used by ParseBuildInfo.
+func (v Version) MetadataValue(key string) (string, bool) {
+	ids := v.MetadataIdentifiers()
+	for i := 0; i+1 < len(ids); i++ {
+		if ids[i] == key {
+			return ids[i+1], true
+		}
+	}
+	return "", false
+}
+
 // originalVPrefix returns the original 'v' prefix if any.
 func (v Version) originalVPrefix() string {
 	// Note, only lowercase v is supported as a prefix by the parser.
@@ -334,6 +526,26 @@ func (v Version) IncMajor() Version {
 	return vNext
 }
 
+// Truncate produces the version with the segments below the given one set
+// to 0, such as 1.2.0 from 1.2.9 for SegmentMinor or 1.0.0 for SegmentMajor.
+// The prerelease and metadata are unset, so truncating to SegmentPatch
+// produces the release of the version. It is the lower bound of the ~ and ^
+// ranges and a key for grouping versions by release line.
+func (v Version) Truncate(s Segment) Version {
+	vNext := v
+	vNext.metadata = ""
+	vNext.pre = ""
+	switch s {
+	case SegmentMajor:
+		vNext.minor = 0
+		vNext.patch = 0
+	case SegmentMinor:
+		vNext.patch = 0
+	}
+	vNext.original = v.originalVPrefix() + "" + vNext.String()
+	return vNext
+}
+
 // SetPrerelease defines the prerelease value.
 // Value must not include the required 'hyphen' prefix.
 func (v Version) SetPrerelease(prerelease string) (Version, error) {
@@ -350,6 +562,9 @@ func (v Version) SetPrerelease(prerelease string) (Version, error) {
 
 // SetMetadata defines metadata value.
 // Value must not include the required 'plus' prefix.
+// Each dot separated identifier must be non-empty and only contain ASCII
+// alphanumerics and hyphens. Otherwise ErrInvalidMetadata is returned and
+// the version is unchanged. An empty value removes the metadata.
 func (v Version) SetMetadata(metadata string) (Version, error) {
 	vNext := v
 	if len(metadata) > 0 {
@@ -362,6 +577,75 @@ func (v Version) SetMetadata(metadata string) (Version, error) {
 	return vNext, nil
 }
 
+// VPrefixPolicy controls the v prefix written by Version.Normalize.
+type VPrefixPolicy uint8
+
+const (
+	// VPrefixKeep writes a v prefix when the original version had one.
+	VPrefixKeep VPrefixPolicy = iota
+
+	// VPrefixAdd always writes a v prefix, as Go modules require.
+	VPrefixAdd
+
+	// VPrefixRemove never writes a v prefix.
+	VPrefixRemove
+)
+
+// NormalizeOptions controls how Version.Normalize writes a version. The zero
+// value writes the version as String does, keeping a v prefix.
+type NormalizeOptions struct {
+	// VPrefix controls the v prefix.
+	VPrefix VPrefixPolicy
+
+	// TrimZeros leaves out a patch version of 0, and a minor version of 0
+	// when the patch version is also 0, such as 1.2 for 1.2.0 and 2 for
+	// 2.0.0. Otherwise missing minor and patch versions are padded with
+	// zeros, such as 1.2.0 for 1.2.
+	TrimZeros bool
+
+	// DropMetadata removes the build metadata.
+	DropMetadata bool
+}
+
+// Normalize returns the version with the original value replaced by the
+// version written following the options, so Original returns the normalized
+// form. This is the way to canonicalize stored version strings, such as in a
+// migration rewriting every stored version as v1.2.0.
+func (v Version) Normalize(opts NormalizeOptions) Version {
+	vNext := v
+	if opts.DropMetadata {
+		vNext.metadata = ""
+	}
+
+	var prefix string
+	switch opts.VPrefix {
+	case VPrefixKeep:
+		prefix = v.originalVPrefix()
+	case VPrefixAdd:
+		prefix = "v"
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(prefix)
+	switch {
+	case opts.TrimZeros && v.minor == 0 && v.patch == 0:
+		fmt.Fprintf(&buf, "%d", v.major)
+	case opts.TrimZeros && v.patch == 0:
+		fmt.Fprintf(&buf, "%d.%d", v.major, v.minor)
+	default:
+		fmt.Fprintf(&buf, "%d.%d.%d", v.major, v.minor, v.patch)
+	}
+	if vNext.pre != "" {
+		fmt.Fprintf(&buf, "-%s", vNext.pre)
+	}
+	if vNext.metadata != "" {
+		fmt.Fprintf(&buf, "+%s", vNext.metadata)
+	}
+
+	vNext.original = buf.String()
+	return vNext
+}
+
 // LessThan tests if one version is less than another one.
 func (v *Version) LessThan(o *Version) bool {
 	return v.Compare(o) < 0
@@ -432,6 +716,70 @@ func (v *Version) Compare(o *Version) int {
 	return comparePrerelease(ps, po)
 }
 
+// CompareWithMetadata compares this version to another one in the same manner
+// as Compare except that versions which would otherwise be equal are ordered
+// by their build metadata. The metadata is compared lexically and a version
+// without metadata is lower than one with it. This gives a deterministic
+// order for build systems that record build numbers in the metadata.
+//
+// Note, the comparison is lexical so build.10 is lower than build.9. Pad
+// the numbers, such as build.0009, for them to sort numerically.
+func (v *Version) CompareWithMetadata(o *Version) int {
+	if d := v.Compare(o); d != 0 {
+		return d
+	}
+
+	return strings.Compare(v.metadata, o.metadata)
+}
+
+// The number of bits given to each part of the key returned by SortKey. The
+// lowest bit records if the version is a release (1) or a prerelease (0).
+const (
+	sortKeyMajorBits = 20
+	sortKeyMinorBits = 20
+	sortKeyPatchBits = 23
+)
+
+// SortKey returns the version packed into a single integer whose natural order
+// matches the order of the versions. This is useful for radix sorting and for
+// database indexes over very large sets of versions.
+//
+// The key has a fixed precision. The major and minor versions get 20 bits
+// each (up to 1048575) and the patch version gets 23 bits (up to 8388607).
+// Larger values saturate, along with every part after them, at the maximum.
+// The final bit places prereleases before the release of the same
+// major.minor.patch but the prerelease itself is not encoded. That means if v
+// is less than o then v.SortKey() <= o.SortKey() and when the keys are equal
+// Compare is needed to order the versions.
+func (v Version) SortKey() uint64 {
+	// Once a part saturates the parts after it are saturated as well. Without
+	// this 1048576.0.0 would sort below 1048575.1.0.
+	var k uint64
+	over := false
+	for _, p := range [...]struct {
+		n    uint64
+		bits uint
+	}{
+		{v.major, sortKeyMajorBits},
+		{v.minor, sortKeyMinorBits},
+		{v.patch, sortKeyPatchBits},
+	} {
+		m := uint64(1)<<p.bits - 1
+		if over || p.n > m {
+			over = true
+			k = k<<p.bits | m
+		} else {
+			k = k<<p.bits | p.n
+		}
+	}
+
+	k <<= 1
+	if over || v.pre == "" {
+		k |= 1
+	}
+	return k
+}
+
 // UnmarshalJSON implements JSON.Unmarshaler interface.
 func (v *Version) UnmarshalJSON(b []byte) error {
 	var s string
@@ -507,33 +855,19 @@ func compareSegment(v, o uint64) int {
 }
 
 func comparePrerelease(v, o string) int {
-	// split the prelease versions by their part. The separator, per the spec,
-	// is a .
-	sparts := strings.Split(v, ".")
-	oparts := strings.Split(o, ".")
-
-	// Find the longer length of the parts to know how many loop iterations to
-	// go through.
-	slen := len(sparts)
-	olen := len(oparts)
-
-	l := slen
-	if olen > slen {
-		l = olen
-	}
-
-	// Iterate over each part of the prereleases to compare the differences.
-	for i := 0; i < l; i++ {
-		// Since the lentgh of the parts can be different we need to create
-		// a placeholder. This is to avoid out of bounds issues.
-		stemp := ""
-		if i < slen {
-			stemp = sparts[i]
+	// Walk the prerelease versions one identifier at a time. The separator,
+	// per the spec, is a . and the identifiers are sliced out of the original
+	// strings in place so no allocations are needed to compare them.
+	sok, ook := true, true
+	for sok || ook {
+		// Since the number of identifiers can be different an exhausted side
+		// compares as an empty placeholder.
+		var stemp, otemp string
+		if sok {
+			stemp, v, sok = nextIdentifier(v)
 		}
-
-		otemp := ""
-		if i < olen {
-			otemp = oparts[i]
+		if ook {
+			otemp, o, ook = nextIdentifier(o)
 		}
 
 		d := comparePrePart(stemp, otemp)
@@ -548,6 +882,16 @@ func comparePrerelease(v, o string) int {
 	return 0
 }
 
+// nextIdentifier returns the first dot separated identifier in s along with
+// the remainder of s following the separator. more reports whether another
+// identifier follows the one returned.
+func nextIdentifier(s string) (id, rest string, more bool) {
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		return s[:i], s[i+1:], true
+	}
+	return s, "", false
+}
+
 func comparePrePart(s, o string) int {
 	// Fastpath if they are equal
 	if s == o {
@@ -570,36 +914,72 @@ func comparePrePart(s, o string) int {
 		return -1
 	}
 
-	// When comparing strings "99" is greater than "103". To handle
-	// cases like this we need to detect numbers and compare them. According
-	// to the semver spec, numbers are always positive. If there is a - at the
-	// start like -99 this is to be evaluated as an alphanum. numbers always
-	// have precedence over alphanum. Parsing as Uints because negative numbers
-	// are ignored.
-
-	oi, n1 := strconv.ParseUint(o, 10, 64)
-	si, n2 := strconv.ParseUint(s, 10, 64)
-
-	// The case where both are strings compare the strings
-	if n1 != nil && n2 != nil {
-		if s > o {
-			return 1
-		}
-		return -1
-	} else if n1 != nil {
-		// o is a string and s is a number
+	// Per https://semver.org/#spec-item-11 identifiers consisting of only
+	// digits are compared numerically and identifiers with letters or hyphens
+	// are compared lexically in ASCII sort order. Numeric identifiers always
+	// have lower precedence than alphanumeric identifiers. If there is a - at
+	// the start like -99 this is to be evaluated as an alphanum.
+	sn := isNumericIdentifier(s)
+	on := isNumericIdentifier(o)
+
+	switch {
+	case sn && on:
+		return compareNumericIdentifier(s, o)
+	case sn:
+		// s is a number and o is a string
 		return -1
-	} else if n2 != nil {
+	case on:
 		// s is a string and o is a number
 		return 1
 	}
-	// Both are numbers
-	if si > oi {
+
+	// The case where both are strings compare the strings
+	if s > o {
 		return 1
 	}
 	return -1
 }
 
+// isNumericIdentifier reports whether s is a non-empty run of ASCII digits.
+func isNumericIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// compareNumericIdentifier compares two numeric identifiers by value. The
+// digits are compared directly rather than parsed so identifiers too large to
+// fit in a uint64 are still ordered numerically.
+func compareNumericIdentifier(s, o string) int {
+	// Leading zeros are not valid in a numeric identifier but versions created
+	// with New() are not validated so they are trimmed to be safe.
+	s = strings.TrimLeft(s, "0")
+	o = strings.TrimLeft(o, "0")
+
+	if d := compareSegment(uint64(len(s)), uint64(len(o))); d != 0 {
+		return d
+	}
+	return strings.Compare(s, o)
+}
+
+// checkASCII returns an error wrapping ErrNonASCII describing the first
+// character in s that is not ASCII.
+func checkASCII(s string) error {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			r, _ := utf8.DecodeRuneInString(s[i:])
+			return fmt.Errorf("%w %q (%U) at offset %d", ErrNonASCII, r, r, i)
+		}
+	}
+	return nil
+}
+
 // Like strings.ContainsAny but does an only instead of any.
 func containsOnly(s string, comp string) bool {
 	return strings.IndexFunc(s, func(r rune) bool {
@@ -615,7 +995,7 @@ func validatePrerelease(p string) error {
 	eparts := strings.Split(p, ".")
 	for _, p := range eparts {
 		if p == "" {
-			return ErrInvalidMetadata
+			return ErrInvalidPrerelease
 		} else if containsOnly(p, num) {
 			if len(p) > 1 && p[0] == '0' {
 				return ErrSegmentStartsZero
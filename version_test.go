@@ -3,7 +3,9 @@ package semver
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -77,6 +79,39 @@ func TestStrictNewVersion(t *testing.T) {
 	}
 }
 
+func TestStrictNewVersionLeadingZero(t *testing.T) {
+	tests := []struct {
+		version string
+		msg     string
+	}{
+		{"01.2.3", `Version segment starts with 0: major version "01"`},
+		{"1.02.3", `Version segment starts with 0: minor version "02"`},
+		{"1.2.03", `Version segment starts with 0: patch version "03"`},
+		{"1.2.3-01", `Version segment starts with 0: prerelease identifier "01"`},
+		{"1.2.3-alpha.0.007", `Version segment starts with 0: prerelease identifier "007"`},
+		{"1.2.3-beta.02+build.01", `Version segment starts with 0: prerelease identifier "02"`},
+	}
+
+	for _, tc := range tests {
+		_, err := StrictNewVersion(tc.version)
+		if err == nil {
+			t.Errorf("expected error for version: %s", tc.version)
+			continue
+		}
+		if !errors.Is(err, ErrSegmentStartsZero) {
+			t.Errorf("expected error for version %s to be ErrSegmentStartsZero but got %q", tc.version, err)
+		}
+		if err.Error() != tc.msg {
+			t.Errorf("expected error for version %s to be %q but got %q", tc.version, tc.msg, err)
+		}
+	}
+
+	// Leading zeros are allowed in build metadata.
+	if _, err := StrictNewVersion("1.2.3+build.01"); err != nil {
+		t.Errorf("unexpected error for version with leading zero in metadata: %s", err)
+	}
+}
+
 func TestNewVersion(t *testing.T) {
 	tests := []struct {
 		version string
@@ -144,6 +179,58 @@ func TestNewVersion(t *testing.T) {
 	}
 }
 
+func TestNewVersionErrors(t *testing.T) {
+	tests := []struct {
+		version string
+		err     error
+	}{
+		{"", ErrEmptyString},
+		{"foo", ErrInvalidCharacters},
+		{"1.2.beta", ErrInvalidCharacters},
+		{"\n1.2", ErrInvalidCharacters},
+		{"1..2", ErrInvalidCharacters},
+		{"1.02.3", ErrSegmentStartsZero},
+		{"1.2.3.4", ErrTooManySegments},
+		{"v1.2.3.4-beta", ErrTooManySegments},
+		{"1.0.0-alpha_beta", ErrInvalidPrerelease},
+		{"1.0.0-alpha..1", ErrInvalidPrerelease},
+		{"1.2.3-alpha.01", ErrSegmentStartsZero},
+		{"1.1.2+.123", ErrInvalidMetadata},
+		{"9.8.7+meta+meta", ErrInvalidMetadata},
+		{"１.2.3", ErrNonASCII},
+		{"1.2.3-bеta", ErrNonASCII},
+	}
+
+	for _, tc := range tests {
+		_, err := NewVersion(tc.version)
+		if !errors.Is(err, ErrInvalidSemVer) {
+			t.Errorf("expected error for version %q to wrap %q but got %q", tc.version, ErrInvalidSemVer, err)
+		}
+		if !errors.Is(err, tc.err) {
+			t.Errorf("expected error for version %q to wrap %q but got %q", tc.version, tc.err, err)
+		}
+	}
+
+	_, err := StrictNewVersion("1.2.3.4")
+	if !errors.Is(err, ErrTooManySegments) {
+		t.Errorf("expected strict error for version 1.2.3.4 to be %q but got %q", ErrTooManySegments, err)
+	}
+
+	_, err = StrictNewVersion("1.2.3-bеta")
+	if !errors.Is(err, ErrNonASCII) {
+		t.Errorf("expected strict error for a Cyrillic prerelease to wrap %q but got %q", ErrNonASCII, err)
+	}
+	if e := `Non-ASCII character 'е' (U+0435) at offset 7`; err == nil || err.Error() != e {
+		t.Errorf("expected error %q but got %q", e, err)
+	}
+
+	DetailedNewVersionErrors = false
+	defer func() { DetailedNewVersionErrors = true }()
+	if _, err := NewVersion("1.2.3.4"); err != ErrInvalidSemVer {
+		t.Errorf("expected error to be ErrInvalidSemVer without details but got %q", err)
+	}
+}
+
 func TestNew(t *testing.T) {
 	// v0.1.2
 	v := New(0, 1, 2, "", "")
@@ -277,6 +364,15 @@ func TestCompare(t *testing.T) {
 		{"1.0.0-beta.4", "1.0.0-beta.-2", -1},
 		{"1.0.0-beta.-2", "1.0.0-beta.-3", -1},
 		{"1.0.0-beta.-3", "1.0.0-beta.5", 1},
+		{"1.0.0-alpha.9", "1.0.0-alpha.10", -1},
+		{"1.0.0-alpha.10", "1.0.0-alpha.9", 1},
+		{"1.0.0-1", "1.0.0-alpha", -1},
+		{"1.0.0-alpha", "1.0.0-1", 1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.0.0-rc.18446744073709551616", "1.0.0-rc.18446744073709551615", 1},
+		{"1.0.0-rc.18446744073709551616", "1.0.0-rc.9", 1},
+		{"1.0.0-rc.18446744073709551616", "1.0.0-rc.a", -1},
+		{"1.0.0-rc.18446744073709551616", "1.0.0-rc.18446744073709551616", 0},
 	}
 
 	for _, tc := range tests {
@@ -301,6 +397,80 @@ func TestCompare(t *testing.T) {
 	}
 }
 
+func TestCompareWithMetadata(t *testing.T) {
+	tests := []struct {
+		v1       string
+		v2       string
+		expected int
+	}{
+		{"1.2.3", "1.5.1+build.1", -1},
+		{"1.2+bar", "1.2+baz", -1},
+		{"1.2+baz", "1.2+bar", 1},
+		{"1.2+bar", "1.2+bar", 0},
+		{"1.2", "1.2+bar", -1},
+		{"1.2.3-beta+2", "1.2.3-beta+1", 1},
+		{"1.2.3-beta+2", "1.2.3+1", -1},
+		{"1.2.3+build.10", "1.2.3+build.9", -1},
+	}
+
+	for _, tc := range tests {
+		v1 := MustParse(tc.v1)
+		v2 := MustParse(tc.v2)
+
+		if a := v1.CompareWithMetadata(v2); a != tc.expected {
+			t.Errorf("Comparison of '%s' and '%s' failed. Expected '%d', got '%d'", tc.v1, tc.v2, tc.expected, a)
+		}
+	}
+}
+
+func TestComparePrereleaseAllocs(t *testing.T) {
+	v1 := MustParse("1.0.0-alpha.beta.11")
+	v2 := MustParse("1.0.0-alpha.beta.rc.1")
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = v1.Compare(v2)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected prerelease comparison not to allocate but got %v allocations", allocs)
+	}
+}
+
+func TestSortKey(t *testing.T) {
+	tests := []struct {
+		v1       string
+		v2       string
+		expected int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"1.2.3", "1.2.4", -1},
+		{"1.2.3", "1.3.0", -1},
+		{"1.9.9", "2.0.0", -1},
+		{"1.2.3-alpha", "1.2.3", -1},
+		{"1.2.3", "1.2.4-alpha", -1},
+		{"1.2.3-alpha", "1.2.3-beta", 0},
+		{"1.2.3+foo", "1.2.3+bar", 0},
+		{"0.0.8388607", "0.1.0", -1},
+		{"0.0.8388608", "0.0.9999999", 0},
+		{"1048575.1048575.8388607", "1048576.0.0", 0},
+		{"1048575.1.0", "1048576.0.0", -1},
+		{"1.1048576.0-alpha", "1.1048577.0", 0},
+		{"1.1048576.0", "2.0.0", -1},
+	}
+
+	for _, tc := range tests {
+		k1 := MustParse(tc.v1).SortKey()
+		k2 := MustParse(tc.v2).SortKey()
+
+		a := compareSegment(k1, k2)
+		if a != tc.expected {
+			t.Errorf(
+				"Sort key comparison of '%s' and '%s' failed. Expected '%d', got '%d'",
+				tc.v1, tc.v2, tc.expected, a,
+			)
+		}
+	}
+}
+
 func TestLessThan(t *testing.T) {
 	tests := []struct {
 		v1       string
@@ -522,6 +692,31 @@ func TestInc(t *testing.T) {
 	}
 }
 
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		v1               string
+		segment          Segment
+		expected         string
+		expectedOriginal string
+	}{
+		{"1.2.9", SegmentMinor, "1.2.0", "1.2.0"},
+		{"v1.2.9", SegmentMajor, "1.0.0", "v1.0.0"},
+		{"1.2.9-beta.1+meta", SegmentPatch, "1.2.9", "1.2.9"},
+		{"1.2.9-beta.1", SegmentMinor, "1.2.0", "1.2.0"},
+		{"1.0.0", SegmentMajor, "1.0.0", "1.0.0"},
+	}
+
+	for _, tc := range tests {
+		v2 := MustParse(tc.v1).Truncate(tc.segment)
+		if a := v2.String(); a != tc.expected {
+			t.Errorf("Truncate %s of %q failed. Expected %q got %q", tc.segment, tc.v1, tc.expected, a)
+		}
+		if a := v2.Original(); a != tc.expectedOriginal {
+			t.Errorf("Truncate %s of %q failed. Expected original %q got %q", tc.segment, tc.v1, tc.expectedOriginal, a)
+		}
+	}
+}
+
 func TestSetPrerelease(t *testing.T) {
 	tests := []struct {
 		v1                 string
@@ -567,6 +762,114 @@ func TestSetPrerelease(t *testing.T) {
 	}
 }
 
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		version          string
+		opts             NormalizeOptions
+		expectedOriginal string
+	}{
+		{"1.2", NormalizeOptions{}, "1.2.0"},
+		{"v1.2", NormalizeOptions{}, "v1.2.0"},
+		{"v1.2.3", NormalizeOptions{VPrefix: VPrefixRemove}, "1.2.3"},
+		{"1.2.3", NormalizeOptions{VPrefix: VPrefixAdd}, "v1.2.3"},
+		{"1.2.0-beta.1+b5", NormalizeOptions{TrimZeros: true}, "1.2-beta.1+b5"},
+		{"2.0.0", NormalizeOptions{TrimZeros: true}, "2"},
+		{"2.0.1", NormalizeOptions{TrimZeros: true}, "2.0.1"},
+		{"v1.2.3+b5", NormalizeOptions{DropMetadata: true}, "v1.2.3"},
+		{"1.2+b5", NormalizeOptions{VPrefix: VPrefixAdd, DropMetadata: true}, "v1.2.0"},
+	}
+
+	for _, tc := range tests {
+		v, err := NewVersion(tc.version)
+		if err != nil {
+			t.Fatalf("Error parsing version: %s", err)
+		}
+
+		n := v.Normalize(tc.opts)
+		if n.Original() != tc.expectedOriginal {
+			t.Errorf("Expected %q normalized with %+v to be %q, but got %q", tc.version, tc.opts, tc.expectedOriginal, n.Original())
+		}
+		if !n.Equal(v) {
+			t.Errorf("Expected %q normalized to equal the version, but got %s", tc.version, &n)
+		}
+		if tc.opts.DropMetadata && n.Metadata() != "" {
+			t.Errorf("Expected the metadata of %q to be dropped, but got %q", tc.version, n.Metadata())
+		}
+
+		p, err := NewVersion(n.Original())
+		if err != nil {
+			t.Errorf("Error parsing normalized version %q: %s", n.Original(), err)
+		} else if p.String() != n.String() {
+			t.Errorf("Expected %q to parse as %q, but got %q", n.Original(), n.String(), p.String())
+		}
+	}
+}
+
+func TestChannel(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected string
+	}{
+		{"1.2.3", ""},
+		{"1.2.3+build.5", ""},
+		{"1.2.3-beta.4", "beta"},
+		{"1.2.3-beta", "beta"},
+		{"1.2.3-rc1.2", "rc1"},
+		{"1.2.3-4.beta", ""},
+		{"1.2.3-0a.1", "0a"},
+	}
+
+	for _, tc := range tests {
+		if c := MustParse(tc.version).Channel(); c != tc.expected {
+			t.Errorf("Expected the channel of %q to be %q, but got %q", tc.version, tc.expected, c)
+		}
+	}
+}
+
+func TestMetadataIdentifiers(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected []string
+	}{
+		{"1.2.3", nil},
+		{"1.2.3+build.42.linux", []string{"build", "42", "linux"}},
+		{"1.2.3-beta.1+sha.abc123", []string{"sha", "abc123"}},
+	}
+
+	for _, tc := range tests {
+		v := MustParse(tc.version)
+		if ids := v.MetadataIdentifiers(); !reflect.DeepEqual(ids, tc.expected) {
+			t.Errorf("Expected metadata identifiers of %q to be %q, but got %q", tc.version, tc.expected, ids)
+		}
+	}
+}
+
+func TestMetadataValue(t *testing.T) {
+	v := MustParse("1.2.3+build.42.sha.abc123.build.43.dirty")
+	tests := []struct {
+		key      string
+		expected string
+		ok       bool
+	}{
+		{"build", "42", true},
+		{"sha", "abc123", true},
+		{"dirty", "", false},
+		{"branch", "", false},
+		{"abc123", "build", true},
+	}
+
+	for _, tc := range tests {
+		val, ok := v.MetadataValue(tc.key)
+		if val != tc.expected || ok != tc.ok {
+			t.Errorf("Expected metadata value of %q to be %q, %t, but got %q, %t", tc.key, tc.expected, tc.ok, val, ok)
+		}
+	}
+
+	if _, ok := MustParse("1.2.3").MetadataValue("sha"); ok {
+		t.Error("Expected no metadata value for a version without metadata")
+	}
+}
+
 func TestSetMetadata(t *testing.T) {
 	tests := []struct {
 		v1               string
@@ -579,6 +882,14 @@ func TestSetMetadata(t *testing.T) {
 		{"1.2.3", "**", "1.2.3", "", "1.2.3", ErrInvalidMetadata},
 		{"1.2.3", "meta", "1.2.3+meta", "meta", "1.2.3+meta", nil},
 		{"v1.2.4", "meta", "1.2.4+meta", "meta", "v1.2.4+meta", nil},
+		{"1.2.3+old", "build.007.x-86", "1.2.3+build.007.x-86", "build.007.x-86", "1.2.3+build.007.x-86", nil},
+		{"1.2.3+old", "", "1.2.3", "", "1.2.3", nil},
+		{"1.2.3+old", "build..1", "1.2.3+old", "old", "1.2.3+old", ErrInvalidMetadata},
+		{"1.2.3", "build.", "1.2.3", "", "1.2.3", ErrInvalidMetadata},
+		{"1.2.3", ".build", "1.2.3", "", "1.2.3", ErrInvalidMetadata},
+		{"1.2.3", "build_1", "1.2.3", "", "1.2.3", ErrInvalidMetadata},
+		{"1.2.3", "build+1", "1.2.3", "", "1.2.3", ErrInvalidMetadata},
+		{"1.2.3", "buïld", "1.2.3", "", "1.2.3", ErrInvalidMetadata},
 	}
 
 	for _, tc := range tests {
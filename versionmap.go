@@ -0,0 +1,114 @@
+package semver
+
+// VersionMap is a map keyed by versions that keeps its keys in sorted order.
+// Along with lookups of a key it has Floor and Ceiling lookups of the
+// nearest key at or below, or at or above, a version. Resolvers and caches
+// use these for questions like the greatest version, with an entry, that is
+// not above v, which a map keyed by version strings can not answer.
+//
+// Keys are compared with Equal so build metadata is ignored, and setting a
+// key equal to one in the map replaces its value. The keys are kept in a
+// SortedCollection so lookups use binary search.
+//
+// The zero value is an empty map ready to use. A VersionMap is not safe for
+// concurrent use.
+type VersionMap struct {
+	keys   SortedCollection
+	values []interface{}
+}
+
+// index returns the index of the key equal to v and whether there is one.
+func (m *VersionMap) index(v *Version) (int, bool) {
+	i := m.keys.versions.Search(v)
+	return i, i < len(m.keys.versions) && m.keys.versions[i].Equal(v)
+}
+
+// Set sets the value for the version, replacing the value of an equal key.
+// Nil versions are ignored.
+func (m *VersionMap) Set(v *Version, value interface{}) {
+	if v == nil {
+		return
+	}
+
+	i, ok := m.index(v)
+	if ok {
+		m.values[i] = value
+		return
+	}
+
+	m.keys.Insert(v)
+	m.values = append(m.values, nil)
+	copy(m.values[i+1:], m.values[i:])
+	m.values[i] = value
+}
+
+// Get returns the value for the key equal to the version and whether there
+// is one.
+func (m *VersionMap) Get(v *Version) (interface{}, bool) {
+	if v == nil {
+		return nil, false
+	}
+	i, ok := m.index(v)
+	if !ok {
+		return nil, false
+	}
+	return m.values[i], true
+}
+
+// Delete removes the key equal to the version and reports if there was one.
+func (m *VersionMap) Delete(v *Version) bool {
+	if v == nil {
+		return false
+	}
+	i, ok := m.index(v)
+	if !ok {
+		return false
+	}
+
+	m.keys.Remove(v)
+	copy(m.values[i:], m.values[i+1:])
+	m.values[len(m.values)-1] = nil
+	m.values = m.values[:len(m.values)-1]
+	return true
+}
+
+// Len returns the number of keys in the map.
+func (m *VersionMap) Len() int {
+	return m.keys.Len()
+}
+
+// Keys returns the keys in sorted order. The returned collection shares
+// memory with m and must not be modified. It is only valid until the next
+// call to Set or Delete.
+func (m *VersionMap) Keys() Collection {
+	return m.keys.Versions()
+}
+
+// Floor returns the greatest key that is not above the version along with
+// its value. The key is nil when every key is above the version.
+func (m *VersionMap) Floor(v *Version) (*Version, interface{}) {
+	if v == nil {
+		return nil, nil
+	}
+	i, ok := m.index(v)
+	if !ok {
+		i--
+	}
+	if i < 0 {
+		return nil, nil
+	}
+	return m.keys.versions[i], m.values[i]
+}
+
+// Ceiling returns the lowest key that is not below the version along with
+// its value. The key is nil when every key is below the version.
+func (m *VersionMap) Ceiling(v *Version) (*Version, interface{}) {
+	if v == nil {
+		return nil, nil
+	}
+	i, _ := m.index(v)
+	if i == len(m.keys.versions) {
+		return nil, nil
+	}
+	return m.keys.versions[i], m.values[i]
+}
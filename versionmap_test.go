@@ -0,0 +1,52 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestVersionMap(t *testing.T) {
+	var m VersionMap
+	for _, v := range []string{"1.4.0", "1.2.0", "2.0.0", "1.2.0+b1"} {
+		m.Set(MustParse(v), v)
+	}
+	if m.Len() != 3 {
+		t.Fatalf("Expected 3 keys but got %d", m.Len())
+	}
+	if val, ok := m.Get(MustParse("1.2.0")); !ok || val != "1.2.0+b1" {
+		t.Errorf("Expected 1.2.0 to be replaced by 1.2.0+b1 but got %v", val)
+	}
+	if _, ok := m.Get(MustParse("1.3.0")); ok {
+		t.Error("Expected no value for 1.3.0")
+	}
+
+	tests := []struct {
+		v              string
+		floor, ceiling string
+	}{
+		{"1.3.0", "1.2.0", "1.4.0"},
+		{"1.4.0", "1.4.0", "1.4.0"},
+		{"1.0.0", "", "1.2.0"},
+		{"3.0.0", "2.0.0", ""},
+		{"2.0.0-beta.1", "1.4.0", "2.0.0"},
+	}
+	for _, tc := range tests {
+		k, val := m.Floor(MustParse(tc.v))
+		if (k == nil && tc.floor != "") || (k != nil && (k.String() != tc.floor || val == nil)) {
+			t.Errorf("Expected the floor of %s to be %q but got %v", tc.v, tc.floor, k)
+		}
+		k, val = m.Ceiling(MustParse(tc.v))
+		if (k == nil && tc.ceiling != "") || (k != nil && (k.String() != tc.ceiling || val == nil)) {
+			t.Errorf("Expected the ceiling of %s to be %q but got %v", tc.v, tc.ceiling, k)
+		}
+	}
+
+	if !m.Delete(MustParse("1.4.0")) || m.Delete(MustParse("1.4.0")) {
+		t.Error("Expected 1.4.0 to be deleted once")
+	}
+	if k, val := m.Floor(MustParse("1.9.0")); k == nil || val != "1.2.0+b1" {
+		t.Errorf("Expected the floor of 1.9.0 to be 1.2.0 but got %v", k)
+	}
+	if keys := m.Keys(); len(keys) != 2 || keys[1].String() != "2.0.0" {
+		t.Errorf("Unexpected keys %v", keys)
+	}
+}
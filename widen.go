@@ -0,0 +1,290 @@
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// WidenToInclude returns constraints adjusted as little as possible so they
+// also allow v, as an update tool does when a new version is released. The
+// style of the constraints is kept where possible:
+//
+//   - A group whose upper bound excludes v has the bound raised, keeping its
+//     precision. >=1.2 <2 with 2.4.0 becomes >=1.2 <3.
+//   - A group whose lower bound excludes v has the bound lowered.
+//   - Otherwise a group in the style of a ^, ~, or wildcard group is added.
+//     ^1.2.3 with 2.1.0 becomes ^1.2.3 || ^2.1.0.
+//   - As a last resort v itself is added as a group.
+//
+// When the constraints already allow v they are returned unchanged. The
+// prerelease policy and deny list are kept. A denied version can not be
+// included and is an error.
+func WidenToInclude(c *Constraints, v *Version) (*Constraints, error) {
+	if c.denied(v) {
+		return nil, fmt.Errorf("%s is denied by %s", v, c)
+	}
+	if c.Check(v) {
+		return c, nil
+	}
+
+	groups := c.sortedGroups()
+	out := make([][]string, len(groups))
+	for k, o := range groups {
+		out[k] = constraintStrings(o)
+	}
+
+	ver := New(v.major, v.minor, v.patch, v.pre, "").String()
+	widened := false
+	for k, o := range groups {
+		if g, ok := widenGroup(o, v, ver); ok {
+			out[k] = g
+			widened = true
+			break
+		}
+	}
+	if !widened {
+		out = append(out, []string{styledGroup(groups, v, ver)})
+	}
+
+	res, err := rebuildConstraints(c, out)
+	if err != nil {
+		return nil, err
+	}
+	if !res.Check(v) {
+		// The new group did not allow v, such as a prerelease v with a group
+		// that only allows releases. Adding v itself always does.
+		out = append(out, []string{ver})
+		if res, err = rebuildConstraints(c, out); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// NarrowBelow returns constraints adjusted as little as possible so they only
+// allow versions lower than v, as an update tool does when a version needs to
+// be avoided. Groups that only allow versions lower than v are unchanged,
+// groups that only allow versions at or above v are removed, and the others
+// get an upper bound of v in place of any upper bound they had. ^1.2.3 with
+// 1.5.0 becomes ^1.2.3 <1.5.0.
+//
+// An error is returned when no versions lower than v are allowed. The
+// prerelease policy and deny list are kept.
+func NarrowBelow(c *Constraints, v *Version) (*Constraints, error) {
+	ver := New(v.major, v.minor, v.patch, v.pre, "").String()
+
+	var out [][]string
+	for _, o := range c.sortedGroups() {
+		lo, hi, _, hiInclusive := groupBounds(o)
+		switch {
+		case hi != nil && (hi.LessThan(v) || (!hiInclusive && hi.Equal(v))):
+			out = append(out, constraintStrings(o))
+			continue
+		case lo != nil && !lo.LessThan(v):
+			continue
+		}
+
+		var g []string
+		for _, cc := range o {
+			if cc.origfunc == "<" || cc.origfunc == "<=" || cc.origfunc == "=<" {
+				continue
+			}
+			g = append(g, cc.string())
+		}
+		out = append(out, append(g, "<"+ver))
+	}
+
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%s does not allow any version below %s", c, v)
+	}
+	return rebuildConstraints(c, out)
+}
+
+// widenGroup adjusts the bounds of a group so it allows v. This is only done
+// when the constraints v fails are all upper bounds or all lower bounds.
+func widenGroup(o []*constraint, v *Version, ver string) ([]string, bool) {
+	var failed []int
+	for i, c := range o {
+		if ok, _ := c.evaluate(v, false); !ok {
+			failed = append(failed, i)
+		}
+	}
+	if len(failed) == 0 {
+		return nil, false
+	}
+
+	g := constraintStrings(o)
+	for _, i := range failed {
+		c := o[i]
+		switch c.origfunc {
+		case "<":
+			g[i] = "<" + raisedBound(c, v)
+		case "<=", "=<":
+			g[i] = c.origfunc + ver
+		case ">", ">=", "=>":
+			g[i] = ">=" + ver
+		default:
+			return nil, false
+		}
+	}
+
+	// Raising one bound and lowering another is not a minimal change.
+	up := strings.HasPrefix(g[failed[0]], "<")
+	for _, i := range failed[1:] {
+		if strings.HasPrefix(g[i], "<") != up {
+			return nil, false
+		}
+	}
+	return g, true
+}
+
+// raisedBound returns the lowest version above v at the same precision as
+// the < constraint, such as 3 for <2 or 1.5 for <1.4.
+func raisedBound(c *constraint, v *Version) string {
+	switch {
+	case c.minorDirty || (!c.dirty && c.con.minor == 0 && c.con.patch == 0):
+		if c.minorDirty {
+			return strconv.FormatUint(v.major+1, 10)
+		}
+		return New(v.major+1, 0, 0, "", "").String()
+	case c.patchDirty || (!c.dirty && c.con.patch == 0):
+		if c.patchDirty {
+			return strconv.FormatUint(v.major, 10) + "." + strconv.FormatUint(v.minor+1, 10)
+		}
+		return New(v.major, v.minor+1, 0, "", "").String()
+	}
+	return New(v.major, v.minor, v.patch+1, "", "").String()
+}
+
+// styledGroup returns a group allowing v in the style of the first group
+// that is a single ^, ~, or wildcard constraint. v itself is used when
+// there is no such group.
+func styledGroup(groups [][]*constraint, v *Version, ver string) string {
+	for _, o := range groups {
+		if len(o) != 1 {
+			continue
+		}
+		c := o[0]
+		switch {
+		case c.origfunc == "^" || c.origfunc == "~" || c.origfunc == "~>":
+			return c.origfunc + ver
+		case (c.origfunc == "" || c.origfunc == "=") && c.minorDirty:
+			return c.origfunc + strconv.FormatUint(v.major, 10) + ".x"
+		case (c.origfunc == "" || c.origfunc == "=") && c.patchDirty:
+			return c.origfunc + strconv.FormatUint(v.major, 10) + "." + strconv.FormatUint(v.minor, 10) + ".x"
+		}
+	}
+	return ver
+}
+
+// groupBounds returns the version below which a group allows nothing and the
+// version above which it allows nothing, along with if each of them is
+// allowed. Nil is returned for a bound the group does not have.
+func groupBounds(o []*constraint) (lo, hi *Version, loInclusive, hiInclusive bool) {
+	for _, c := range o {
+		l, h, linc, hinc := c.bounds()
+		if l != nil && (lo == nil || l.GreaterThan(lo) || (l.Equal(lo) && !linc)) {
+			lo, loInclusive = l, linc
+		}
+		if h != nil && (hi == nil || h.LessThan(hi) || (h.Equal(hi) && !hinc)) {
+			hi, hiInclusive = h, hinc
+		}
+	}
+	return lo, hi, loInclusive, hiInclusive
+}
+
+// bounds returns the version below which the constraint allows nothing and
+// the version above which it allows nothing, along with if each of them is
+// allowed. >1.2.3 allows versions above 1.2.3 but not 1.2.3 itself.
+func (c *constraint) bounds() (lo, hi *Version, loInclusive, hiInclusive bool) {
+	// The version after the range of a wildcard, such as 2.0.0 for 1.x.
+	var next *Version
+	switch {
+	case c.minorDirty:
+		next = New(c.con.major+1, 0, 0, "", "")
+	case c.patchDirty:
+		next = New(c.con.major, c.con.minor+1, 0, "", "")
+	}
+	anyVersion := c.dirty && !c.minorDirty && !c.patchDirty
+
+	// A wildcard on its own, such as >=* or <=*, allows any version except
+	// with > and <. >* allows the versions above 0.0.0 and <* allows none.
+	if anyVersion {
+		switch c.origfunc {
+		case ">":
+			return c.con, nil, false, false
+		case "<":
+			return c.con, c.con, false, false
+		}
+		return nil, nil, false, false
+	}
+
+	switch c.origfunc {
+	case "", "=":
+		if c.dirty {
+			return c.con, next, true, false
+		}
+		return c.con, c.con, true, true
+	case ">":
+		switch {
+		case c.patchDirty:
+			return New(c.con.major, c.con.minor+1, 0, "", ""), nil, true, false
+		case c.dirty:
+			return New(c.con.major+1, 0, 0, "", ""), nil, true, false
+		}
+		return c.con, nil, false, false
+	case ">=", "=>":
+		return c.con, nil, true, false
+	case "<":
+		return nil, c.con, false, false
+	case "<=", "=<":
+		if c.dirty {
+			return nil, next, false, false
+		}
+		return nil, c.con, false, true
+	case "~", "~>":
+		if c.con.major == 0 && c.con.minor == 0 && c.con.patch == 0 && !c.dirty {
+			return c.con, nil, true, false
+		}
+		if c.minorDirty {
+			return c.con, next, true, false
+		}
+		return c.con, tildeUpper(c.con), true, false
+	case "^":
+		if c.minorDirty {
+			return c.con, next, true, false
+		}
+		if c.patchDirty && c.con.major == 0 {
+			return c.con, next, true, false
+		}
+		return c.con, caretUpper(c.con), true, false
+	}
+	return nil, nil, false, false
+}
+
+// constraintStrings returns the string form of each constraint in a group.
+func constraintStrings(o []*constraint) []string {
+	s := make([]string, len(o))
+	for i, c := range o {
+		s[i] = c.string()
+	}
+	return s
+}
+
+// rebuildConstraints parses groups of constraints keeping the settings of c.
+func rebuildConstraints(c *Constraints, groups [][]string) (*Constraints, error) {
+	ors := make([]string, len(groups))
+	for k, g := range groups {
+		ors[k] = strings.Join(g, " ")
+	}
+
+	res, err := NewConstraint(strings.Join(ors, " || "))
+	if err != nil {
+		return nil, err
+	}
+	res.PrereleasePolicy = c.PrereleasePolicy
+	res.Deny = c.Deny
+	return res, nil
+}
@@ -0,0 +1,143 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestWidenToInclude(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		expected   string
+	}{
+		{"^1.2.3", "1.4.0", "^1.2.3"},
+		{"^1.2.3", "2.1.0", "^1.2.3 || ^2.1.0"},
+		{"~1.2.3", "1.4.0", "~1.2.3 || ~1.4.0"},
+		{"1.x", "2.0.5", "1.x || 2.x"},
+		{"1.2.x", "1.3.0", "1.2.x || 1.3.x"},
+		{">=1.2 <2", "2.4.0", ">=1.2 <3"},
+		{">=1.2 <1.4", "1.6.2", ">=1.2 <1.7"},
+		{">=1.2.0 <2.0.0", "3.1.0", ">=1.2.0 <4.0.0"},
+		{">=1.2.0 <1.2.5", "1.2.7", ">=1.2.0 <1.2.8"},
+		{"1.2.0 - 1.4.0", "1.5.1", ">=1.2.0 <=1.5.1"},
+		{">=1.2.0 <2", "1.0.0", ">=1.0.0 <2"},
+		{"1.2.3 || 1.2.4", "1.2.6", "1.2.3 || 1.2.4 || 1.2.6"},
+		{">=1.2.0 <2", "2.1.0-beta.1", ">=1.2.0 <3 || 2.1.0-beta.1"},
+		{"^1.2.3", "2.1.0-beta.1+build.5", "^1.2.3 || ^2.1.0-beta.1"},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Errorf("err: %s", err)
+			continue
+		}
+		v := MustParse(tc.version)
+
+		w, err := WidenToInclude(c, v)
+		if err != nil {
+			t.Errorf("Unexpected error widening %q to include %s: %s", tc.constraint, tc.version, err)
+			continue
+		}
+		if w.String() != tc.expected {
+			t.Errorf("Expected %q widened to include %s to be %q but got %q", tc.constraint, tc.version, tc.expected, w)
+		}
+		if !w.Check(v) {
+			t.Errorf("Expected %q to include %s", w, tc.version)
+		}
+	}
+
+	c, _ := NewConstraint("^1.2.3")
+	c.Deny = []*Version{MustParse("1.5.0")}
+	if _, err := WidenToInclude(c, MustParse("1.5.0")); err == nil {
+		t.Error("Expected an error widening to include a denied version")
+	}
+}
+
+func TestNarrowBelow(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		expected   string
+	}{
+		{"^1.2.3", "1.5.0", "^1.2.3 <1.5.0"},
+		{"^1.2.3", "2.0.0", "^1.2.3"},
+		{">=1.2.0 <3", "2.1.0", ">=1.2.0 <2.1.0"},
+		{">=1.2.0 <=1.8.0", "1.5.0", ">=1.2.0 <1.5.0"},
+		{"^1.2.3 || ^2.1.0", "2.0.0", "^1.2.3"},
+		{"^1.2.3 || ^2.1.0", "2.3.0", "^1.2.3 || ^2.1.0 <2.3.0"},
+		{"1.x", "1.4.0", "1.x <1.4.0"},
+		{"*", "1.0.0", "* <1.0.0"},
+		{"~1.2.3 || 1.4.1", "1.4.1", "~1.2.3"},
+		{">*", "1.0.0", ">* <1.0.0"},
+		{">=*", "1.0.0", ">=* <1.0.0"},
+		{"<=*", "1.0.0", "<1.0.0"},
+		{"<*", "1.0.0", "<*"},
+	}
+
+	for _, tc := range tests {
+		c, err := NewConstraint(tc.constraint)
+		if err != nil {
+			t.Errorf("err: %s", err)
+			continue
+		}
+		v := MustParse(tc.version)
+
+		n, err := NarrowBelow(c, v)
+		if err != nil {
+			t.Errorf("Unexpected error narrowing %q below %s: %s", tc.constraint, tc.version, err)
+			continue
+		}
+		if n.String() != tc.expected {
+			t.Errorf("Expected %q narrowed below %s to be %q but got %q", tc.constraint, tc.version, tc.expected, n)
+		}
+		if n.Check(v) {
+			t.Errorf("Expected %q not to allow %s", n, tc.version)
+		}
+	}
+
+	c, _ := NewConstraint("^2.1.0")
+	if _, err := NarrowBelow(c, MustParse("2.0.0")); err == nil {
+		t.Error("Expected an error narrowing below every allowed version")
+	}
+}
+
+func TestConstraintBounds(t *testing.T) {
+	tests := []struct {
+		constraint string
+		lo, hi     string
+		loInc      bool
+		hiInc      bool
+	}{
+		{">1.2.3", "1.2.3", "", false, false},
+		{">1.2", "1.3.0", "", true, false},
+		{"<=1.4", "", "1.5.0", false, false},
+		{"1.2.3", "1.2.3", "1.2.3", true, true},
+		{">*", "0.0.0", "", false, false},
+		{">=*", "", "", false, false},
+		{"<=*", "", "", false, false},
+		{"=*", "", "", false, false},
+		{"<*", "0.0.0", "0.0.0", false, false},
+	}
+
+	str := func(v *Version) string {
+		if v == nil {
+			return ""
+		}
+		return v.String()
+	}
+	for _, tc := range tests {
+		c := mustConstraint(t, tc.constraint).constraints[0][0]
+		lo, hi, loInc, hiInc := c.bounds()
+		if str(lo) != tc.lo || str(hi) != tc.hi || loInc != tc.loInc || hiInc != tc.hiInc {
+			t.Errorf("Expected %q to have bounds %q %t and %q %t but got %q %t and %q %t", tc.constraint, tc.lo, tc.loInc, tc.hi, tc.hiInc, str(lo), loInc, str(hi), hiInc)
+		}
+	}
+
+	if !mustConstraint(t, "<=*").Check(MustParse("5.0.0")) {
+		t.Error("Expected <=* to allow any version")
+	}
+	if !Intersection(mustConstraint(t, "<*"), mustConstraint(t, ">=1.0.0")).IsEmpty() {
+		t.Error("Expected <* to allow nothing")
+	}
+}
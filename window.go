@@ -0,0 +1,67 @@
+package semver
+
+// Window is a range of versions between two versions. It is a lightweight
+// alternative to Constraints for simple range math, such as whether the
+// versions supported by two components overlap, where there are no groups,
+// wildcards, or prerelease rules to take into account.
+//
+// Versions are compared with Compare, so build metadata is ignored, and a
+// prerelease is in the window when it is between the ends like any other
+// version. The zero value is a window holding every version.
+type Window struct {
+	// From is the low end of the window. When it is nil the window has no
+	// low end.
+	From *Version
+
+	// To is the high end of the window. When it is nil the window has no
+	// high end.
+	To *Version
+
+	// IncludeFrom and IncludeTo include the versions equal to the ends in
+	// the window.
+	IncludeFrom, IncludeTo bool
+}
+
+func (w Window) interval() interval {
+	return interval{lo: w.From, hi: w.To, loInc: w.IncludeFrom, hiInc: w.IncludeTo}
+}
+
+func windowOf(iv interval) Window {
+	return Window{From: iv.lo, To: iv.hi, IncludeFrom: iv.loInc, IncludeTo: iv.hiInc}
+}
+
+// Contains reports if the version is in the window. A nil version is not.
+func (w Window) Contains(v *Version) bool {
+	return v != nil && w.interval().contains(v)
+}
+
+// IsEmpty reports if the ends of the window leave no room for a version,
+// such as for (1.2.0, 1.2.0] or when From is above To.
+func (w Window) IsEmpty() bool {
+	return w.interval().empty()
+}
+
+// Overlaps reports if there is a version in both windows.
+func (w Window) Overlaps(o Window) bool {
+	return !intersectInterval(w.interval(), o.interval()).empty()
+}
+
+// Intersect returns the window of the versions in both windows. It is empty
+// when the windows do not overlap.
+func (w Window) Intersect(o Window) Window {
+	return windowOf(intersectInterval(w.interval(), o.interval()))
+}
+
+// String returns the window in the form of constraints, such as
+// >=1.2.0 <2.0.0, =1.2.0 for a window holding one version, or * for one
+// with no ends.
+func (w Window) String() string {
+	return w.interval().String()
+}
+
+// Constraints returns constraints allowing the versions in the window. Note,
+// the constraints match prereleases by their PrereleasePolicy, so unlike
+// Contains they do not allow prereleases unless an end is one.
+func (w Window) Constraints() (*Constraints, error) {
+	return NewConstraint(w.String())
+}
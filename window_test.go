@@ -0,0 +1,56 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestWindow(t *testing.T) {
+	w := Window{From: MustParse("1.2.0"), To: MustParse("2.0.0"), IncludeFrom: true}
+	for v, e := range map[string]bool{
+		"1.2.0":        true,
+		"1.2.0+b1":     true,
+		"1.9.9":        true,
+		"2.0.0-beta.1": true,
+		"2.0.0":        false,
+		"1.1.9":        false,
+	} {
+		if w.Contains(MustParse(v)) != e {
+			t.Errorf("Expected %s to contain %s to be %t", w, v, e)
+		}
+	}
+	if w.Contains(nil) {
+		t.Error("Expected a nil version not to be in the window")
+	}
+	if !(Window{}).Contains(MustParse("0.0.1")) {
+		t.Error("Expected the zero window to contain every version")
+	}
+
+	tests := []struct {
+		a, b      Window
+		overlaps  bool
+		intersect string
+	}{
+		{w, Window{From: MustParse("1.5.0"), IncludeFrom: true}, true, ">=1.5.0 <2.0.0"},
+		{w, Window{From: MustParse("2.0.0"), IncludeFrom: true}, false, ">=2.0.0 <2.0.0"},
+		{w, Window{To: MustParse("1.2.0"), IncludeTo: true}, true, "=1.2.0"},
+		{w, Window{To: MustParse("1.2.0")}, false, ">=1.2.0 <1.2.0"},
+		{w, Window{}, true, ">=1.2.0 <2.0.0"},
+	}
+	for _, tc := range tests {
+		if o := tc.a.Overlaps(tc.b); o != tc.overlaps {
+			t.Errorf("Expected %s and %s overlapping to be %t", tc.a, tc.b, tc.overlaps)
+		}
+		i := tc.a.Intersect(tc.b)
+		if i.String() != tc.intersect || i.IsEmpty() == tc.overlaps {
+			t.Errorf("Expected the intersection of %s and %s to be %s but got %s", tc.a, tc.b, tc.intersect, i)
+		}
+	}
+
+	c, err := w.Constraints()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if c.String() != ">=1.2.0 <2.0.0" || !c.Check(MustParse("1.5.0")) || c.Check(MustParse("2.0.0-beta.1")) {
+		t.Errorf("Unexpected constraints %s", c)
+	}
+}